@@ -0,0 +1,238 @@
+// Package ais decodes the most common AIS messages carried by VDM and VDO
+// sentences: class A position reports (types 1, 2 and 3), static and voyage
+// data (type 5), class B position reports (type 18) and class B static data
+// reports (type 24).
+package ais
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTooShort is returned when a payload is shorter than its message type
+// requires.
+var ErrTooShort = errors.New("ais: payload too short")
+
+// Message is a decoded AIS message.
+type Message interface {
+	MessageType() int
+	UserID() uint32
+}
+
+// Header holds the fields common to all messages.
+type Header struct {
+	Type   int    // Message type
+	Repeat int    // Repeat indicator
+	MMSI   uint32 // Maritime Mobile Service Identity of the sender
+}
+
+// MessageType returns the message type.
+func (h Header) MessageType() int { return h.Type }
+
+// UserID returns the MMSI of the sender.
+func (h Header) UserID() uint32 { return h.MMSI }
+
+// PositionReport is a class A (types 1, 2 and 3) or class B (type 18)
+// position report. Unavailable values are flagged by the Valid fields.
+type PositionReport struct {
+	Header
+	NavigationStatus      int     // Class A only, 15 when not defined
+	RateOfTurn            float64 // Class A only, degrees per minute, positive to starboard
+	RateOfTurnValid       bool
+	SpeedOverGround       float64 // Knots
+	SpeedOverGroundValid  bool
+	PositionAccuracy      bool // High accuracy, better than 10 m
+	Longitude             float64
+	Latitude              float64
+	PositionValid         bool
+	CourseOverGround      float64 // Degrees from true north
+	CourseOverGroundValid bool
+	TrueHeading           int // Degrees from true north
+	TrueHeadingValid      bool
+	Timestamp             int // Second of the UTC minute, 60 and above when not available
+}
+
+// StaticData is a static and voyage related data message (type 5), or one
+// part of a class B static data report (type 24).
+type StaticData struct {
+	Header
+	PartNumber  int // Type 24 only, 0 for part A and 1 for part B
+	IMO         uint32
+	CallSign    string
+	Name        string
+	ShipType    int
+	ToBow       int // Dimensions from the position reference, in meters
+	ToStern     int
+	ToPort      int
+	ToStarboard int
+	ETAMonth    int // Estimated time of arrival, 0 when not available
+	ETADay      int
+	ETAHour     int // 24 when not available
+	ETAMinute   int // 60 when not available
+	Draught     float64
+	Destination string
+}
+
+// Navigation statuses
+const (
+	UnderWayUsingEngine  = 0
+	AtAnchor             = 1
+	NotUnderCommand      = 2
+	RestrictedManoeuvre  = 3
+	ConstrainedByDraught = 4
+	Moored               = 5
+	Aground              = 6
+	EngagedInFishing     = 7
+	UnderWaySailing      = 8
+	NotDefined           = 15
+)
+
+// Decode decodes a payload, given as one bit per byte like the Payload of
+// a VDMVDO sentence.
+func Decode(bits []byte) (Message, error) {
+	if len(bits) < 38 {
+		return nil, ErrTooShort
+	}
+	r := reader(bits)
+	h := Header{
+		Type:   int(r.uint(0, 6)),
+		Repeat: int(r.uint(6, 2)),
+		MMSI:   uint32(r.uint(8, 30)),
+	}
+	switch h.Type {
+	case 1, 2, 3:
+		if len(bits) < 168 {
+			return nil, ErrTooShort
+		}
+		return decodeClassA(h, r), nil
+	case 18:
+		if len(bits) < 168 {
+			return nil, ErrTooShort
+		}
+		return decodeClassB(h, r), nil
+	case 5:
+		if len(bits) < 420 {
+			return nil, ErrTooShort
+		}
+		return decodeStatic(h, r), nil
+	case 24:
+		if len(bits) < 160 {
+			return nil, ErrTooShort
+		}
+		return decodeStaticReport(h, r), nil
+	}
+	return nil, fmt.Errorf("ais: unsupported message type: %d", h.Type)
+}
+
+func decodeClassA(h Header, r reader) PositionReport {
+	m := PositionReport{Header: h, NavigationStatus: int(r.uint(38, 4))}
+	if rot := r.int(42, 8); rot != -128 {
+		// the rate of turn is sent as 4.733 * sqrt(degrees per minute)
+		v := float64(rot) / 4.733
+		m.RateOfTurn = v * v
+		if rot < 0 {
+			m.RateOfTurn = -m.RateOfTurn
+		}
+		m.RateOfTurnValid = true
+	}
+	decodeMotion(&m, r, 50, 60, 61, 89, 116, 128, 137)
+	return m
+}
+
+func decodeClassB(h Header, r reader) PositionReport {
+	m := PositionReport{Header: h, NavigationStatus: NotDefined}
+	decodeMotion(&m, r, 46, 56, 57, 85, 112, 124, 133)
+	return m
+}
+
+func decodeMotion(m *PositionReport, r reader, sog, accuracy, lon, lat, cog, heading, second int) {
+	if v := r.uint(sog, 10); v != 1023 {
+		m.SpeedOverGround, m.SpeedOverGroundValid = float64(v)/10, true
+	}
+	m.PositionAccuracy = r.uint(accuracy, 1) == 1
+	m.Longitude = float64(r.int(lon, 28)) / 600000
+	m.Latitude = float64(r.int(lat, 27)) / 600000
+	m.PositionValid = m.Longitude >= -180 && m.Longitude <= 180 && m.Latitude >= -90 && m.Latitude <= 90
+	if v := r.uint(cog, 12); v < 3600 {
+		m.CourseOverGround, m.CourseOverGroundValid = float64(v)/10, true
+	}
+	if v := r.uint(heading, 9); v < 360 {
+		m.TrueHeading, m.TrueHeadingValid = int(v), true
+	}
+	m.Timestamp = int(r.uint(second, 6))
+}
+
+func decodeStatic(h Header, r reader) StaticData {
+	return StaticData{
+		Header:      h,
+		IMO:         uint32(r.uint(40, 30)),
+		CallSign:    r.text(70, 42),
+		Name:        r.text(112, 120),
+		ShipType:    int(r.uint(232, 8)),
+		ToBow:       int(r.uint(240, 9)),
+		ToStern:     int(r.uint(249, 9)),
+		ToPort:      int(r.uint(258, 6)),
+		ToStarboard: int(r.uint(264, 6)),
+		ETAMonth:    int(r.uint(274, 4)),
+		ETADay:      int(r.uint(278, 5)),
+		ETAHour:     int(r.uint(283, 5)),
+		ETAMinute:   int(r.uint(288, 6)),
+		Draught:     float64(r.uint(294, 8)) / 10,
+		Destination: r.text(302, 120),
+	}
+}
+
+func decodeStaticReport(h Header, r reader) StaticData {
+	m := StaticData{Header: h, PartNumber: int(r.uint(38, 2))}
+	if m.PartNumber == 0 {
+		m.Name = r.text(40, 120)
+		return m
+	}
+	m.ShipType = int(r.uint(40, 8))
+	m.CallSign = r.text(90, 42)
+	m.ToBow = int(r.uint(132, 9))
+	m.ToStern = int(r.uint(141, 9))
+	m.ToPort = int(r.uint(150, 6))
+	m.ToStarboard = int(r.uint(156, 6))
+	return m
+}
+
+// reader reads fields of a payload, missing trailing bits read as zero.
+type reader []byte
+
+func (r reader) uint(start, length int) uint64 {
+	var v uint64
+	for i := start; i < start+length; i++ {
+		v <<= 1
+		if i < len(r) {
+			v |= uint64(r[i] & 1)
+		}
+	}
+	return v
+}
+
+func (r reader) int(start, length int) int64 {
+	v := int64(r.uint(start, length))
+	if v&(1<<uint(length-1)) != 0 {
+		v -= 1 << uint(length)
+	}
+	return v
+}
+
+// text reads six bit characters, trimming the @ padding and spaces.
+func (r reader) text(start, length int) string {
+	var b strings.Builder
+	for i := start; i+6 <= start+length; i += 6 {
+		c := byte(r.uint(i, 6))
+		if c < 32 {
+			c += 64
+		}
+		b.WriteByte(c)
+	}
+	s := b.String()
+	if i := strings.IndexByte(s, '@'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimRight(s, " ")
+}
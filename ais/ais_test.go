@@ -0,0 +1,202 @@
+package ais
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+func payload(t *testing.T, raw string) []byte {
+	s, err := nmea.Parse(raw)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	return s.(nmea.VDMVDO).Payload
+}
+
+var decodetests = []struct {
+	name string
+	raw  string
+	err  string
+	msg  Message
+}{
+	{
+		name: "class A position report",
+		raw:  "!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A",
+		msg: PositionReport{
+			Header:                Header{Type: 1, MMSI: 371798000},
+			NavigationStatus:      UnderWayUsingEngine,
+			RateOfTurn:            -720.0032105295371,
+			RateOfTurnValid:       true,
+			SpeedOverGround:       12.3,
+			SpeedOverGroundValid:  true,
+			PositionAccuracy:      true,
+			Longitude:             -123.39538333333333,
+			Latitude:              48.38163333333333,
+			PositionValid:         true,
+			CourseOverGround:      224,
+			CourseOverGroundValid: true,
+			TrueHeading:           215,
+			TrueHeadingValid:      true,
+			Timestamp:             33,
+		},
+	},
+	{
+		name: "class B position report",
+		raw:  "!AIVDM,1,1,,B,B5NJ;PP005l4ot5Isbl03wsUkP06,0*75",
+		msg: PositionReport{
+			Header:                Header{Type: 18, MMSI: 367430530},
+			NavigationStatus:      NotDefined,
+			SpeedOverGroundValid:  true,
+			Longitude:             -122.26732,
+			Latitude:              37.785035,
+			PositionValid:         true,
+			CourseOverGroundValid: true,
+			Timestamp:             55,
+		},
+	},
+	{
+		name: "static data report part A",
+		raw:  "!AIVDM,1,1,,A,H42O55i18tMET00000000000000,2*6D",
+		msg: StaticData{
+			Header: Header{Type: 24, MMSI: 271041815},
+			Name:   "PROGUY",
+		},
+	},
+	{
+		name: "static data report part B",
+		raw:  "!AIVDM,1,1,,A,H42O55lti4hhhilD3nink000?050,0*40",
+		msg: StaticData{
+			Header:      Header{Type: 24, MMSI: 271041815},
+			PartNumber:  1,
+			CallSign:    "TC6163",
+			ShipType:    60,
+			ToStern:     15,
+			ToStarboard: 5,
+		},
+	},
+	{
+		name: "too short",
+		raw:  "!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8,0*5B",
+		err:  "ais: payload too short",
+	},
+	{
+		name: "unsupported type",
+		raw:  "!AIVDM,1,1,,B,K5DfMB9FLsM?P00d,0*70",
+		err:  "ais: unsupported message type: 27",
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, tt := range decodetests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := Decode(payload(t, tt.raw))
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.msg, msg)
+			}
+		})
+	}
+}
+
+func TestDecodeMultiFragment(t *testing.T) {
+	a := NewAssembler()
+	_, ok := a.Add(nmea.VDMVDO{NumFragments: 2, FragmentNumber: 1, MessageID: 1, Channel: "A",
+		Payload: payload(t, "!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C")})
+	assert.False(t, ok)
+	bits, ok := a.Add(nmea.VDMVDO{NumFragments: 2, FragmentNumber: 2, MessageID: 1, Channel: "A",
+		Payload: payload(t, "!AIVDM,2,2,1,A,88888888880,2*25")})
+	assert.True(t, ok)
+
+	msg, err := Decode(bits)
+	assert.NoError(t, err)
+	assert.Equal(t, StaticData{
+		Header:      Header{Type: 5, MMSI: 351759000},
+		IMO:         9134270,
+		CallSign:    "3FOF8",
+		Name:        "EVER DIADEM",
+		ShipType:    70,
+		ToBow:       225,
+		ToStern:     70,
+		ToPort:      1,
+		ToStarboard: 31,
+		ETAMonth:    5,
+		ETADay:      15,
+		ETAHour:     14,
+		Draught:     12.2,
+		Destination: "NEW YORK",
+	}, msg)
+
+	// a missing fragment drops the message
+	_, ok = a.Add(nmea.VDMVDO{NumFragments: 3, FragmentNumber: 1, MessageID: 2, Channel: "A"})
+	assert.False(t, ok)
+	_, ok = a.Add(nmea.VDMVDO{NumFragments: 3, FragmentNumber: 3, MessageID: 2, Channel: "A"})
+	assert.False(t, ok)
+}
+
+func fragment(talker, source string, number int64, raw string) nmea.VDMVDO {
+	s, _ := nmea.Parse(raw)
+	m := s.(nmea.VDMVDO)
+	m.Talker = talker
+	m.TagBlock.Source = source
+	m.FragmentNumber = number
+	return m
+}
+
+func TestAssemblerKey(t *testing.T) {
+	const (
+		first  = "!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C"
+		second = "!AIVDM,2,2,1,A,88888888880,2*25"
+	)
+	want := append(payload(t, first), payload(t, second)...)
+	a := NewAssembler()
+
+	// the same channel and message id from other talkers and sources
+	for _, m := range []nmea.VDMVDO{
+		fragment("AI", "r1", 1, first),
+		fragment("AB", "r1", 1, first),
+		fragment("AI", "r2", 1, first),
+	} {
+		_, ok := a.Add(m)
+		assert.False(t, ok)
+	}
+	for _, m := range []nmea.VDMVDO{
+		fragment("AB", "r1", 2, second),
+		fragment("AI", "r2", 2, second),
+		fragment("AI", "r1", 2, second),
+	} {
+		bits, ok := a.Add(m)
+		assert.True(t, ok)
+		assert.Equal(t, want, bits)
+	}
+}
+
+func TestAssemblerTimeout(t *testing.T) {
+	const (
+		first  = "!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C"
+		second = "!AIVDM,2,2,1,A,88888888880,2*25"
+	)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewAssembler()
+	a.now = func() time.Time { return now }
+
+	_, ok := a.Add(fragment("AI", "", 1, first))
+	assert.False(t, ok)
+	now = now.Add(DefaultFragmentTimeout)
+	_, ok = a.Add(fragment("AI", "", 2, second))
+	assert.False(t, ok)
+	assert.Empty(t, a.pending)
+
+	// a zero timeout keeps incomplete payloads
+	a.Timeout = 0
+	_, ok = a.Add(fragment("AI", "", 1, first))
+	assert.False(t, ok)
+	now = now.Add(time.Hour)
+	_, ok = a.Add(fragment("AI", "", 2, second))
+	assert.True(t, ok)
+}
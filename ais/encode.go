@@ -0,0 +1,93 @@
+package ais
+
+import (
+	"math"
+	"strings"
+)
+
+// EncodePositionReport encodes a class A (types 1, 2 and 3) or class B
+// (type 18) position report, one bit per byte like the Payload of a VDMVDO
+// sentence.
+func EncodePositionReport(m PositionReport) []byte {
+	w := make(writer, 168)
+	w.uint(0, 6, uint64(m.Type))
+	w.uint(6, 2, uint64(m.Repeat))
+	w.uint(8, 30, uint64(m.MMSI))
+	if m.Type == 18 {
+		w.uint(38, 8, 0xff) // regional reserved
+		encodeMotion(m, w, 46, 56, 57, 85, 112, 124, 133)
+		w.uint(141, 2, 0)
+		w.uint(143, 1, 1) // carrier sense unit
+		return w
+	}
+	w.uint(38, 4, uint64(m.NavigationStatus))
+	rot := int64(-128)
+	if m.RateOfTurnValid {
+		rot = int64(math.Round(4.733 * math.Sqrt(math.Abs(m.RateOfTurn))))
+		if rot > 127 {
+			rot = 127
+		}
+		if m.RateOfTurn < 0 {
+			rot = -rot
+		}
+	}
+	w.int(42, 8, rot)
+	encodeMotion(m, w, 50, 60, 61, 89, 116, 128, 137)
+	return w
+}
+
+func encodeMotion(m PositionReport, w writer, sog, accuracy, lon, lat, cog, heading, second int) {
+	w.uint(sog, 10, 1023)
+	if m.SpeedOverGroundValid {
+		w.uint(sog, 10, uint64(math.Min(math.Round(m.SpeedOverGround*10), 1022)))
+	}
+	if m.PositionAccuracy {
+		w.uint(accuracy, 1, 1)
+	}
+	if m.PositionValid {
+		w.int(lon, 28, int64(math.Round(m.Longitude*600000)))
+		w.int(lat, 27, int64(math.Round(m.Latitude*600000)))
+	} else {
+		w.int(lon, 28, 181*600000)
+		w.int(lat, 27, 91*600000)
+	}
+	w.uint(cog, 12, 3600)
+	if m.CourseOverGroundValid {
+		w.uint(cog, 12, uint64(math.Round(m.CourseOverGround*10))%3600)
+	}
+	w.uint(heading, 9, 511)
+	if m.TrueHeadingValid {
+		w.uint(heading, 9, uint64(m.TrueHeading%360))
+	}
+	w.uint(second, 6, uint64(m.Timestamp))
+}
+
+// Armor encodes a payload in the six bit ASCII armor of VDM and VDO
+// sentences, returning the armored payload and the number of fill bits.
+func Armor(bits []byte) (string, int) {
+	fill := (6 - len(bits)%6) % 6
+	r := reader(bits)
+	var b strings.Builder
+	for i := 0; i < len(bits); i += 6 {
+		c := byte(r.uint(i, 6)) + 48
+		if c > 87 {
+			c += 8
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), fill
+}
+
+// writer writes fields of a payload.
+type writer []byte
+
+func (w writer) uint(start, length int, v uint64) {
+	for i := start + length - 1; i >= start; i-- {
+		w[i] = byte(v & 1)
+		v >>= 1
+	}
+}
+
+func (w writer) int(start, length int, v int64) {
+	w.uint(start, length, uint64(v)&(1<<uint(length)-1))
+}
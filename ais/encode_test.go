@@ -0,0 +1,46 @@
+package ais
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodePositionReport(t *testing.T) {
+	for _, raw := range []string{
+		"!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A",
+		"!AIVDM,1,1,,B,B5NJ;PP005l4ot5Isbl03wsUkP06,0*75",
+	} {
+		t.Run(raw, func(t *testing.T) {
+			msg, err := Decode(payload(t, raw))
+			if !assert.NoError(t, err) {
+				return
+			}
+			decoded, err := Decode(EncodePositionReport(msg.(PositionReport)))
+			assert.NoError(t, err)
+			assert.Equal(t, msg, decoded)
+		})
+	}
+}
+
+func TestEncodeUnavailable(t *testing.T) {
+	bits := EncodePositionReport(PositionReport{Header: Header{Type: 1, MMSI: 123456789}, NavigationStatus: NotDefined})
+	msg, err := Decode(bits)
+	assert.NoError(t, err)
+	assert.Equal(t, PositionReport{
+		Header:           Header{Type: 1, MMSI: 123456789},
+		NavigationStatus: NotDefined,
+		Longitude:        181,
+		Latitude:         91,
+	}, msg)
+}
+
+func TestArmor(t *testing.T) {
+	armored, fill := Armor(payload(t, "!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A"))
+	assert.Equal(t, "15RTgt0PAso;90TKcjM8h6g208CQ", armored)
+	assert.Equal(t, 0, fill)
+
+	armored, fill = Armor(payload(t, "!AIVDM,2,2,1,A,88888888880,2*25"))
+	assert.Equal(t, "88888888880", armored)
+	assert.Equal(t, 2, fill)
+}
@@ -0,0 +1,222 @@
+package ais
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// DefaultFragmentTimeout is the time after which an Assembler drops an
+// incomplete payload. The fragments of a message are sent back to back.
+const DefaultFragmentTimeout = 10 * time.Second
+
+type fragmentKey struct {
+	talker  string
+	source  string
+	channel string
+	id      int64
+}
+
+type fragments struct {
+	payload []byte
+	next    int64
+	started time.Time
+}
+
+// Assembler joins the payloads of multi-fragment VDM and VDO sentences.
+// Fragments are matched by talker, tag block source, channel and message id.
+type Assembler struct {
+	Timeout time.Duration // Time after which incomplete payloads are dropped, 0 disables
+
+	mu      sync.Mutex
+	pending map[fragmentKey]*fragments
+	now     func() time.Time
+}
+
+// NewAssembler constructor
+func NewAssembler() *Assembler {
+	return &Assembler{
+		Timeout: DefaultFragmentTimeout,
+		pending: map[fragmentKey]*fragments{},
+		now:     time.Now,
+	}
+}
+
+// Add adds a fragment. When the fragment completes a payload, the payload
+// is returned along with true. Fragments arriving out of order drop the
+// incomplete payload, as do payloads left incomplete for longer than the
+// timeout.
+func (a *Assembler) Add(m nmea.VDMVDO) ([]byte, bool) {
+	if m.NumFragments <= 1 {
+		return m.Payload, true
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	a.expire(now)
+	key := fragmentKey{m.Talker, m.TagBlock.Source, m.Channel, m.MessageID}
+	if m.FragmentNumber == 1 {
+		a.pending[key] = &fragments{
+			payload: append([]byte(nil), m.Payload...),
+			next:    2,
+			started: now,
+		}
+		return nil, false
+	}
+	f, ok := a.pending[key]
+	if !ok || f.next != m.FragmentNumber {
+		delete(a.pending, key)
+		return nil, false
+	}
+	f.payload = append(f.payload, m.Payload...)
+	if m.FragmentNumber < m.NumFragments {
+		f.next++
+		return nil, false
+	}
+	delete(a.pending, key)
+	return f.payload, true
+}
+
+// expire drops the payloads pending for longer than the timeout.
+func (a *Assembler) expire(now time.Time) {
+	if a.Timeout <= 0 {
+		return
+	}
+	for key, f := range a.pending {
+		if now.Sub(f.started) >= a.Timeout {
+			delete(a.pending, key)
+		}
+	}
+}
+
+// Vessel is the latest known state of a vessel, merged from its position
+// reports and static data.
+type Vessel struct {
+	MMSI                  uint32
+	Class                 string // A or B
+	Name                  string
+	CallSign              string
+	IMO                   uint32
+	ShipType              int
+	Destination           string
+	Draught               float64
+	Length                int
+	Beam                  int
+	NavigationStatus      int
+	Latitude              float64
+	Longitude             float64
+	PositionValid         bool
+	SpeedOverGround       float64
+	SpeedOverGroundValid  bool
+	CourseOverGround      float64
+	CourseOverGroundValid bool
+	TrueHeading           int
+	TrueHeadingValid      bool
+}
+
+// Tracker decodes VDM and VDO sentences and keeps the state of every vessel
+// heard.
+type Tracker struct {
+	mu        sync.Mutex
+	fragments *Assembler
+	vessels   map[uint32]*Vessel
+}
+
+// NewTracker constructor
+func NewTracker() *Tracker {
+	return &Tracker{
+		fragments: NewAssembler(),
+		vessels:   map[uint32]*Vessel{},
+	}
+}
+
+// Add observes a sentence. When a VDM or VDO sentence completes a supported
+// message, the updated vessel is returned along with true.
+func (t *Tracker) Add(s nmea.Sentence) (Vessel, bool) {
+	m, ok := s.(nmea.VDMVDO)
+	if !ok {
+		return Vessel{}, false
+	}
+	payload, ok := t.fragments.Add(m)
+	if !ok {
+		return Vessel{}, false
+	}
+	msg, err := Decode(payload)
+	if err != nil {
+		return Vessel{}, false
+	}
+	return t.Update(msg), true
+}
+
+// Update merges a decoded message into the state of its vessel.
+func (t *Tracker) Update(msg Message) Vessel {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.vessels[msg.UserID()]
+	if !ok {
+		v = &Vessel{MMSI: msg.UserID(), NavigationStatus: NotDefined}
+		t.vessels[msg.UserID()] = v
+	}
+	switch m := msg.(type) {
+	case PositionReport:
+		v.Class = "A"
+		if m.Type == 18 {
+			v.Class = "B"
+		} else {
+			v.NavigationStatus = m.NavigationStatus
+		}
+		v.Latitude, v.Longitude, v.PositionValid = m.Latitude, m.Longitude, m.PositionValid
+		v.SpeedOverGround, v.SpeedOverGroundValid = m.SpeedOverGround, m.SpeedOverGroundValid
+		v.CourseOverGround, v.CourseOverGroundValid = m.CourseOverGround, m.CourseOverGroundValid
+		v.TrueHeading, v.TrueHeadingValid = m.TrueHeading, m.TrueHeadingValid
+	case StaticData:
+		if m.Type == 5 {
+			v.Class = "A"
+			v.IMO = m.IMO
+			v.Destination = m.Destination
+			v.Draught = m.Draught
+		} else if v.Class == "" {
+			v.Class = "B"
+		}
+		if m.Name != "" {
+			v.Name = m.Name
+		}
+		if m.Type == 5 || m.PartNumber == 1 {
+			v.CallSign = m.CallSign
+			v.ShipType = m.ShipType
+			v.Length = m.ToBow + m.ToStern
+			v.Beam = m.ToPort + m.ToStarboard
+		}
+	}
+	return *v
+}
+
+// Vessel returns the state of a vessel.
+func (t *Tracker) Vessel(mmsi uint32) (Vessel, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.vessels[mmsi]
+	if !ok {
+		return Vessel{}, false
+	}
+	return *v, true
+}
+
+// Vessels returns the state of every vessel heard, ordered by MMSI.
+func (t *Tracker) Vessels() []Vessel {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	vessels := make([]Vessel, 0, len(t.vessels))
+	for _, v := range t.vessels {
+		vessels = append(vessels, *v)
+	}
+	sort.Slice(vessels, func(i, j int) bool { return vessels[i].MMSI < vessels[j].MMSI })
+	return vessels
+}
@@ -0,0 +1,61 @@
+package ais
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker()
+	var updates int
+	for _, raw := range []string{
+		"$GPHDT,123.456,T*32",
+		"!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A",
+		"!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C",
+		"!AIVDM,2,2,1,A,88888888880,2*25",
+		"!AIVDM,1,1,,A,H42O55i18tMET00000000000000,2*6D",
+		"!AIVDM,1,1,,A,H42O55lti4hhhilD3nink000?050,0*40",
+		"!AIVDM,1,1,,B,K5DfMB9FLsM?P00d,0*70",
+	} {
+		s, err := nmea.Parse(raw)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if _, ok := tr.Add(s); ok {
+			updates++
+		}
+	}
+	assert.Equal(t, 4, updates)
+
+	vessels := tr.Vessels()
+	if !assert.Len(t, vessels, 3) {
+		return
+	}
+	assert.Equal(t, Vessel{
+		MMSI:             271041815,
+		Class:            "B",
+		Name:             "PROGUY",
+		CallSign:         "TC6163",
+		ShipType:         60,
+		Length:           15,
+		Beam:             5,
+		NavigationStatus: NotDefined,
+	}, vessels[0])
+	assert.Equal(t, uint32(351759000), vessels[1].MMSI)
+	assert.Equal(t, "EVER DIADEM", vessels[1].Name)
+	assert.Equal(t, 295, vessels[1].Length)
+	assert.False(t, vessels[1].PositionValid)
+
+	v, ok := tr.Vessel(371798000)
+	assert.True(t, ok)
+	assert.Equal(t, "A", v.Class)
+	assert.True(t, v.PositionValid)
+	assert.InDelta(t, 48.3816, v.Latitude, 1e-4)
+	assert.Equal(t, 12.3, v.SpeedOverGround)
+
+	_, ok = tr.Vessel(1)
+	assert.False(t, ok)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/csvexport"
+)
+
+// Output formats.
+const (
+	formatRaw   = "raw"
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// Modes of error handling.
+const (
+	// modeDefault reports errors and goes on with the next line.
+	modeDefault = iota
+	// modeStrict stops at the first error.
+	modeStrict
+	// modeLenient prints sentences which fail to decode but have a valid
+	// checksum, and does not report errors.
+	modeLenient
+)
+
+// cat prints the sentences of lines in a format and keeps statistics.
+type cat struct {
+	format  string
+	mode    int
+	types   map[string]bool // Types printed, any when empty
+	talkers map[string]bool // Talkers printed, any when empty
+	out     io.Writer
+	errs    io.Writer
+
+	lines     int
+	sentences map[string]int // Sentences decoded, by type
+	errors    map[string]int // Errors, by kind
+}
+
+// generic is the JSON object of sentences which fail to decode.
+type generic struct {
+	Type   string   `json:"type"`
+	Talker string   `json:"talker"`
+	Fields []string `json:"fields"`
+	Raw    string   `json:"raw"`
+	Error  string   `json:"error"`
+}
+
+func newCat(format string, mode int, out, errs io.Writer) *cat {
+	return &cat{
+		format:    format,
+		mode:      mode,
+		types:     map[string]bool{},
+		talkers:   map[string]bool{},
+		out:       out,
+		errs:      errs,
+		sentences: map[string]int{},
+		errors:    map[string]int{},
+	}
+}
+
+// line processes a line. In strict mode, errors are returned.
+func (c *cat) line(text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	c.lines++
+	s, err := nmea.Parse(text)
+	if err == nil {
+		c.sentences[s.DataType()]++
+		if c.match(s.TalkerID(), s.DataType()) {
+			c.print(s, text)
+		}
+		return nil
+	}
+
+	c.errors[errorKind(err)]++
+	if c.mode == modeLenient {
+		if base, err2 := nmea.ParseBaseSentence(text); err2 == nil && c.match(base.Talker, base.Type) {
+			c.printGeneric(base, text, err)
+		}
+		return nil
+	}
+	err = fmt.Errorf("line %d: %v", c.lines, err)
+	if c.mode == modeStrict {
+		return err
+	}
+	fmt.Fprintf(c.errs, "nmeacat: %v\n", err)
+	return nil
+}
+
+func (c *cat) match(talker, typ string) bool {
+	return (len(c.types) == 0 || c.types[typ]) && (len(c.talkers) == 0 || c.talkers[talker])
+}
+
+func (c *cat) print(s nmea.Sentence, text string) {
+	switch c.format {
+	case formatJSON:
+		b, err := json.Marshal(s)
+		if err != nil {
+			fmt.Fprintf(c.errs, "nmeacat: line %d: %v\n", c.lines, err)
+			return
+		}
+		fmt.Fprintf(c.out, "%s\n", b)
+	case formatTable:
+		cols, ok := csvexport.Columns(s.DataType())
+		if !ok || s.TalkerID() == nmea.TypeMTK {
+			base, _ := nmea.BaseSentenceOf(s)
+			c.row(base.Prefix(), fieldCells(base.Fields))
+			return
+		}
+		row, err := csvexport.Row(s)
+		if err != nil {
+			fmt.Fprintf(c.errs, "nmeacat: line %d: %v\n", c.lines, err)
+		}
+		var cells []string
+		for i, v := range row[1:] {
+			if v != "" {
+				cells = append(cells, cols[i+1]+"="+v)
+			}
+		}
+		c.row(s.TalkerID()+s.DataType(), cells)
+	default:
+		fmt.Fprintln(c.out, text)
+	}
+}
+
+func (c *cat) printGeneric(s nmea.BaseSentence, text string, err error) {
+	switch c.format {
+	case formatJSON:
+		b, _ := json.Marshal(generic{
+			Type:   s.Type,
+			Talker: s.Talker,
+			Fields: s.Fields,
+			Raw:    s.Raw,
+			Error:  err.Error(),
+		})
+		fmt.Fprintf(c.out, "%s\n", b)
+	case formatTable:
+		c.row(s.Prefix(), fieldCells(s.Fields))
+	default:
+		fmt.Fprintln(c.out, text)
+	}
+}
+
+// row prints the prefix of a sentence and its cells.
+func (c *cat) row(prefix string, cells []string) {
+	fmt.Fprintf(c.out, "%-7s %s\n", prefix, strings.Join(cells, "  "))
+}
+
+// fieldCells numbers the fields of a sentence which could not be decoded.
+func fieldCells(fields []string) []string {
+	cells := make([]string, len(fields))
+	for i, f := range fields {
+		cells[i] = fmt.Sprintf("%d=%s", i+1, f)
+	}
+	return cells
+}
+
+// errorKind classifies errors for the statistics.
+func errorKind(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "checksum"):
+		return "checksum"
+	case strings.Contains(msg, "not supported"):
+		return "unsupported"
+	case strings.Contains(msg, "does not start with"), strings.Contains(msg, "tagblock"):
+		return "framing"
+	}
+	return "invalid"
+}
+
+// report writes the statistics.
+func (c *cat) report(w io.Writer) {
+	decoded := 0
+	for _, n := range c.sentences {
+		decoded += n
+	}
+	failed := 0
+	for _, n := range c.errors {
+		failed += n
+	}
+	fmt.Fprintf(w, "lines: %d, decoded: %d, errors: %d\n", c.lines, decoded, failed)
+	if len(c.sentences) > 0 {
+		fmt.Fprintf(w, "sentences: %s\n", counts(c.sentences))
+	}
+	if len(c.errors) > 0 {
+		fmt.Fprintf(w, "errors: %s\n", counts(c.errors))
+	}
+}
+
+// counts formats counts sorted by key, e.g. "GGA 2, RMC 3".
+func counts(m map[string]int) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s %d", k, m[k])
+	}
+	return strings.Join(parts, ", ")
+}
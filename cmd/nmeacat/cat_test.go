@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var input = []string{
+	"$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70",
+	"",
+	"$PXYZ,1,2*08",
+	"$GPHDT,123.456,T*33",
+	"$HEHDT,123.456,T*28",
+}
+
+var cattests = []struct {
+	name    string
+	format  string
+	mode    int
+	types   []string
+	talkers []string
+	out     string
+	errs    string
+	err     string
+}{
+	{
+		name:   "raw",
+		format: formatRaw,
+		out: "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70\n" +
+			"$HEHDT,123.456,T*28\n",
+		errs: "nmeacat: line 2: nmea: sentence prefix 'PXYZ' not supported\n" +
+			"nmeacat: line 3: nmea: sentence checksum mismatch [32 != 33]\n",
+	},
+	{
+		name:   "table",
+		format: formatTable,
+		out: "GPRMC   Time=22:05:16.000  Validity=A  Latitude=51.56366666666666  Longitude=-0.7040000000000001  Speed=173.8  Course=231.8  Date=1994-06-13  Variation=-4.2  DateCorrected=false\n" +
+			"HEHDT   Heading=123.456  True=true\n",
+		errs: "nmeacat: line 2: nmea: sentence prefix 'PXYZ' not supported\n" +
+			"nmeacat: line 3: nmea: sentence checksum mismatch [32 != 33]\n",
+	},
+	{
+		name:   "json",
+		format: formatJSON,
+		types:  []string{"HDT"},
+		out:    `{"type":"HDT","talker":"HE","heading":123.456,"true":true,"raw":"$HEHDT,123.456,T*28"}` + "\n",
+		errs: "nmeacat: line 2: nmea: sentence prefix 'PXYZ' not supported\n" +
+			"nmeacat: line 3: nmea: sentence checksum mismatch [32 != 33]\n",
+	},
+	{
+		name:   "strict",
+		format: formatRaw,
+		mode:   modeStrict,
+		out:    "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70\n",
+		err:    "line 2: nmea: sentence prefix 'PXYZ' not supported",
+	},
+	{
+		name:   "lenient",
+		format: formatJSON,
+		mode:   modeLenient,
+		types:  []string{"XYZ"},
+		out:    `{"type":"XYZ","talker":"P","fields":["1","2"],"raw":"$PXYZ,1,2*08","error":"nmea: sentence prefix 'PXYZ' not supported"}` + "\n",
+	},
+	{
+		name:    "lenient table",
+		format:  formatTable,
+		mode:    modeLenient,
+		talkers: []string{"P"},
+		out:     "PXYZ    1=1  2=2\n",
+	},
+}
+
+func TestCat(t *testing.T) {
+	for _, tt := range cattests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errs bytes.Buffer
+			c := newCat(tt.format, tt.mode, &out, &errs)
+			for _, typ := range tt.types {
+				c.types[typ] = true
+			}
+			for _, talker := range tt.talkers {
+				c.talkers[talker] = true
+			}
+			var err error
+			for _, line := range input {
+				if err = c.line(line); err != nil {
+					break
+				}
+			}
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.out, out.String())
+			assert.Equal(t, tt.errs, errs.String())
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	var out bytes.Buffer
+	c := newCat(formatRaw, modeLenient, &out, &out)
+	for _, line := range input {
+		c.line(line)
+	}
+	c.line("GPRMC")
+	out.Reset()
+	c.report(&out)
+	assert.Equal(t, "lines: 5, decoded: 2, errors: 3\n"+
+		"sentences: HDT 1, RMC 1\n"+
+		"errors: checksum 1, framing 1, unsupported 1\n", out.String())
+}
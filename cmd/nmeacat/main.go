@@ -0,0 +1,182 @@
+// Command nmeacat prints the sentences read from files, the standard input,
+// TCP or UDP, as received, as a table or as JSON.
+//
+// Usage:
+//
+//	nmeacat [flags] [input ...]
+//
+// Inputs are file names, "-" for the standard input, tcp://host:port to
+// connect to a TCP server and udp://host:port to listen for datagrams. The
+// standard input is read when no input is given. Statistics of the
+// sentences and errors are printed on exit.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+)
+
+func main() {
+	var (
+		format  = flag.String("format", formatRaw, "output format: raw, table or json")
+		types   = flag.String("type", "", "comma separated sentence types to print, e.g. RMC,GGA, or GRME for $PGRME")
+		talkers = flag.String("talker", "", "comma separated talkers to print, e.g. GP,GN")
+		strict  = flag.Bool("strict", false, "stop at the first error")
+		lenient = flag.Bool("lenient", false, "print sentences which fail to decode, without reporting errors")
+		quiet   = flag.Bool("quiet", false, "do not print statistics on exit")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file | - | tcp://host:port | udp://host:port ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	mode := modeDefault
+	switch {
+	case *strict && *lenient:
+		fail("-strict and -lenient are exclusive")
+	case *strict:
+		mode = modeStrict
+	case *lenient:
+		mode = modeLenient
+	}
+	switch *format {
+	case formatRaw, formatTable, formatJSON:
+	default:
+		fail("unknown format " + *format)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	c := newCat(*format, mode, out, os.Stderr)
+	for _, t := range split(*types) {
+		c.types[t] = true
+	}
+	for _, t := range split(*talkers) {
+		c.talkers[t] = true
+	}
+
+	inputs := flag.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+	lines := make(chan string)
+	errs := make(chan error, len(inputs))
+	var wg sync.WaitGroup
+	for _, input := range inputs {
+		wg.Add(1)
+		go func(input string) {
+			defer wg.Done()
+			if err := read(input, lines); err != nil {
+				errs <- fmt.Errorf("%s: %v", input, err)
+			}
+		}(input)
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	status := 0
+loop:
+	for {
+		select {
+		case text, ok := <-lines:
+			if !ok {
+				break loop
+			}
+			if err := c.line(text); err != nil {
+				fmt.Fprintf(os.Stderr, "nmeacat: %v\n", err)
+				status = 1
+				break loop
+			}
+			if len(lines) == 0 {
+				out.Flush()
+			}
+		case err := <-errs:
+			fmt.Fprintf(os.Stderr, "nmeacat: %v\n", err)
+			status = 1
+		case <-interrupt:
+			break loop
+		}
+	}
+	out.Flush()
+	for len(errs) > 0 {
+		fmt.Fprintf(os.Stderr, "nmeacat: %v\n", <-errs)
+		status = 1
+	}
+	if !*quiet {
+		c.report(os.Stderr)
+	}
+	os.Exit(status)
+}
+
+// read sends the lines of an input.
+func read(input string, lines chan<- string) error {
+	switch {
+	case input == "-":
+		return scan(os.Stdin, lines)
+	case strings.HasPrefix(input, "tcp://"):
+		conn, err := net.Dial("tcp", strings.TrimPrefix(input, "tcp://"))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		return scan(conn, lines)
+	case strings.HasPrefix(input, "udp://"):
+		conn, err := net.ListenPacket("udp", strings.TrimPrefix(input, "udp://"))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		buf := make([]byte, 64*1024)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return err
+			}
+			for _, text := range bytes.Split(buf[:n], []byte("\n")) {
+				lines <- string(text)
+			}
+		}
+	}
+	f, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return scan(f, lines)
+}
+
+func scan(r io.Reader, lines chan<- string) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines <- scanner.Text()
+	}
+	return scanner.Err()
+}
+
+func split(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func fail(msg string) {
+	fmt.Fprintf(os.Stderr, "nmeacat: %s\n", msg)
+	flag.Usage()
+	os.Exit(2)
+}
@@ -0,0 +1,89 @@
+// Command nmealint checks logs of sentences for checksum errors,
+// over-length sentences, illegal characters, field counts and values out
+// of range, broken multi-part sequences, timestamps running backwards and
+// inconsistent talkers.
+//
+// Usage:
+//
+//	nmealint [flags] [file ...]
+//
+// The standard input is read when no file, or "-", is given. Every issue is
+// printed with its file, line number and severity, followed by a summary.
+// The exit status is 1 when errors are found.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/storskegg/go-nmea/lint"
+)
+
+func main() {
+	var (
+		format   = flag.String("format", formatText, "output format: text or json")
+		severity = flag.String("severity", "info", "least severity printed: info, warning or error")
+		quiet    = flag.Bool("quiet", false, "do not print the summary")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file | - ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	switch *format {
+	case formatText, formatJSON:
+	default:
+		fail("unknown format " + *format)
+	}
+	min, err := lint.ParseSeverity(*severity)
+	if err != nil {
+		fail("unknown severity " + *severity)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	r := newReport(*format, min, out)
+	inputs := flag.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+	status := 0
+	for _, input := range inputs {
+		if err := read(r, input); err != nil {
+			out.Flush()
+			fmt.Fprintf(os.Stderr, "nmealint: %s: %v\n", input, err)
+			status = 1
+		}
+	}
+	out.Flush()
+	if !*quiet {
+		r.summary(os.Stderr)
+	}
+	if r.failed() {
+		status = 1
+	}
+	os.Exit(status)
+}
+
+// read lints an input.
+func read(r *report, input string) error {
+	var in io.Reader = os.Stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	return r.lint(input, in)
+}
+
+func fail(msg string) {
+	fmt.Fprintf(os.Stderr, "nmealint: %s\n", msg)
+	flag.Usage()
+	os.Exit(2)
+}
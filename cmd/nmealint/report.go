@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/storskegg/go-nmea/lint"
+)
+
+// Output formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// report prints the issues of inputs and counts them.
+type report struct {
+	format string
+	min    lint.Severity // Least severity printed
+	out    io.Writer
+
+	inputs int
+	counts map[lint.Severity]int // Issues, by severity, printed or not
+}
+
+// issue is the JSON object of an issue.
+type issue struct {
+	File string `json:"file"`
+	lint.Issue
+}
+
+func newReport(format string, min lint.Severity, out io.Writer) *report {
+	return &report{
+		format: format,
+		min:    min,
+		out:    out,
+		counts: map[lint.Severity]int{},
+	}
+}
+
+// lint checks the lines of an input and prints its issues.
+func (r *report) lint(name string, in io.Reader) error {
+	r.inputs++
+	issues, err := lint.Lint(in)
+	for _, i := range issues {
+		r.counts[i.Severity]++
+		if i.Severity < r.min {
+			continue
+		}
+		switch r.format {
+		case formatJSON:
+			b, _ := json.Marshal(issue{name, i})
+			fmt.Fprintf(r.out, "%s\n", b)
+		default:
+			fmt.Fprintf(r.out, "%s:%d: %s: %s (%s)\n", name, i.Line, i.Severity, i.Message, i.Code)
+		}
+	}
+	return err
+}
+
+// failed reports whether an error was found.
+func (r *report) failed() bool {
+	return r.counts[lint.Error] > 0
+}
+
+// summary prints the counts of issues.
+func (r *report) summary(w io.Writer) {
+	var counts []string
+	for _, s := range []lint.Severity{lint.Error, lint.Warning, lint.Info} {
+		n := r.counts[s]
+		name := s.String()
+		if n != 1 {
+			name += "s"
+		}
+		counts = append(counts, fmt.Sprintf("%d %s", n, name))
+	}
+	inputs := "input"
+	if r.inputs != 1 {
+		inputs += "s"
+	}
+	fmt.Fprintf(w, "%s in %d %s\n", strings.Join(counts, ", "), r.inputs, inputs)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/storskegg/go-nmea/lint"
+)
+
+var input = strings.Join([]string{
+	"$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70",
+	"$GPHDT,123.4,T*32",
+	"$QQHDT,123.4,T*26",
+}, "\n")
+
+func TestReport(t *testing.T) {
+	var tests = []struct {
+		name    string
+		format  string
+		min     lint.Severity
+		out     string
+		summary string
+	}{
+		{
+			name:   "text",
+			format: formatText,
+			min:    lint.Info,
+			out: "log.nmea:2: error: checksum mismatch [31 != 32] (checksum)\n" +
+				"log.nmea:3: warning: unknown talker QQ (talker)\n",
+			summary: "1 error, 1 warning, 0 infos in 1 input\n",
+		},
+		{
+			name:    "errors only",
+			format:  formatText,
+			min:     lint.Error,
+			out:     "log.nmea:2: error: checksum mismatch [31 != 32] (checksum)\n",
+			summary: "1 error, 1 warning, 0 infos in 1 input\n",
+		},
+		{
+			name:   "json",
+			format: formatJSON,
+			min:    lint.Info,
+			out: `{"file":"log.nmea","line":2,"severity":"error","code":"checksum","message":"checksum mismatch [31 != 32]"}` + "\n" +
+				`{"file":"log.nmea","line":3,"severity":"warning","code":"talker","message":"unknown talker QQ"}` + "\n",
+			summary: "1 error, 1 warning, 0 infos in 1 input\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, summary bytes.Buffer
+			r := newReport(tt.format, tt.min, &out)
+			assert.NoError(t, r.lint("log.nmea", strings.NewReader(input)))
+			r.summary(&summary)
+			assert.Equal(t, tt.out, out.String())
+			assert.Equal(t, tt.summary, summary.String())
+			assert.True(t, r.failed())
+		})
+	}
+}
+
+func TestReportClean(t *testing.T) {
+	var out, summary bytes.Buffer
+	r := newReport(formatText, lint.Info, &out)
+	assert.NoError(t, r.lint("a.nmea", strings.NewReader("$HEHDT,123.4,T*2B\n")))
+	assert.NoError(t, r.lint("b.nmea", strings.NewReader("")))
+	r.summary(&summary)
+	assert.Equal(t, "", out.String())
+	assert.Equal(t, "0 errors, 0 warnings, 0 infos in 2 inputs\n", summary.String())
+	assert.False(t, r.failed())
+}
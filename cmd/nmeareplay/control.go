@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/storskegg/go-nmea/replay"
+)
+
+// parseSeek parses the time to seek to, either a time like
+// 2020-01-02T03:04:05Z or an offset from the start of the log like 10m.
+func parseSeek(s string, l replay.Log) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return l.Start().Add(d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time or offset: %s", s)
+	}
+	return t, nil
+}
+
+// control reads commands controlling the player, one per line, until the
+// end of the stream or the quit command:
+//
+//	pause | p         pause the playback
+//	resume | r        resume the playback
+//	seek TIME|OFFSET  go to a time, or an offset from the start of the log
+//	quit | q          stop the playback
+//
+// Replies, like the position of the playback, are written to w.
+func control(r io.Reader, w io.Writer, p *replay.Player, l replay.Log) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "pause", "p":
+			p.Pause()
+			fmt.Fprintf(w, "paused at %s\n", p.Position().Format(time.RFC3339Nano))
+		case "resume", "r":
+			p.Resume()
+			fmt.Fprintln(w, "resumed")
+		case "seek", "s":
+			if len(fields) != 2 {
+				fmt.Fprintln(w, "usage: seek TIME|OFFSET")
+				continue
+			}
+			t, err := parseSeek(fields[1], l)
+			if err != nil {
+				fmt.Fprintln(w, err)
+				continue
+			}
+			p.Seek(t)
+			fmt.Fprintf(w, "seeked to %s\n", t.Format(time.RFC3339Nano))
+		case "quit", "q":
+			p.Stop()
+			return
+		default:
+			fmt.Fprintf(w, "unknown command: %s\n", fields[0])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/storskegg/go-nmea/replay"
+)
+
+var start = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+var testLog = replay.Log{Entries: []replay.Entry{
+	{Line: "$GPHDT,123.456,T*32", Time: start},
+	{Line: "$GPHDT,123.456,T*32", Time: start.Add(time.Hour)},
+}}
+
+var seektests = []struct {
+	name string
+	arg  string
+	time time.Time
+	err  string
+}{
+	{
+		name: "offset",
+		arg:  "1h30m",
+		time: start.Add(90 * time.Minute),
+	},
+	{
+		name: "time",
+		arg:  "2020-01-02T04:00:00Z",
+		time: time.Date(2020, 1, 2, 4, 0, 0, 0, time.UTC),
+	},
+	{
+		name: "invalid",
+		arg:  "yesterday",
+		err:  "invalid time or offset: yesterday",
+	},
+}
+
+func TestParseSeek(t *testing.T) {
+	for _, tt := range seektests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := parseSeek(tt.arg, testLog)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, tt.time.Equal(v))
+			}
+		})
+	}
+}
+
+func TestControl(t *testing.T) {
+	p := replay.NewPlayer(testLog)
+	var out bytes.Buffer
+	control(strings.NewReader("p\n\nseek 30m\nseek\nseek never\nresume\nfoo\nq\npause\n"), &out, p, testLog)
+	assert.Equal(t, "paused at 0001-01-01T00:00:00Z\n"+
+		"seeked to 2020-01-02T03:34:05Z\n"+
+		"usage: seek TIME|OFFSET\n"+
+		"invalid time or offset: never\n"+
+		"resumed\n"+
+		"unknown command: foo\n", out.String())
+	assert.False(t, p.Paused())
+
+	// The player is stopped and the playback ends right away.
+	var w bytes.Buffer
+	assert.NoError(t, p.Play(&w))
+	assert.Empty(t, w.String())
+}
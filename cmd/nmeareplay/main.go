@@ -0,0 +1,113 @@
+// Command nmeareplay replays a recorded log at the pace it was recorded,
+// to the standard output, the clients of a TCP server or UDP datagrams.
+//
+// Usage:
+//
+//	nmeareplay [flags] log
+//
+// The log is a file name, or "-" for the standard input. Times come from
+// the c: parameters of TAG Blocks, or from the times of RMC, ZDA, GGA, GLL
+// and GNS sentences. Unless the log is read from the standard input, the
+// playback is controlled by commands on the standard input: pause, resume,
+// seek TIME|OFFSET and quit.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/storskegg/go-nmea/mux"
+	"github.com/storskegg/go-nmea/replay"
+)
+
+func main() {
+	var (
+		to       = flag.String("to", "-", "destination: - for the standard output, tcp://:port to serve TCP clients or udp://host:port")
+		speed    = flag.Float64("speed", 1, "speed-up factor, or 0 to replay without waiting")
+		loop     = flag.Bool("loop", false, "start over at the end of the log")
+		seek     = flag.String("seek", "", "start at a time like 2020-01-02T03:04:05Z or an offset from the start like 10m")
+		maxDelay = flag.Duration("max-delay", replay.DefaultMaxDelay, "longest wait between two lines, longer gaps are shortened")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] log\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	in := io.Reader(os.Stdin)
+	if name := flag.Arg(0); name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			fail(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	l, err := replay.Read(in)
+	if err != nil {
+		fail(err)
+	}
+
+	w, err := open(*to)
+	if err != nil {
+		fail(err)
+	}
+	p := replay.NewPlayer(l)
+	p.Speed, p.Loop, p.MaxDelay = *speed, *loop, *maxDelay
+	if *seek != "" {
+		t, err := parseSeek(*seek, l)
+		if err != nil {
+			fail(err)
+		}
+		p.Seek(t)
+	}
+	if in != os.Stdin {
+		go control(os.Stdin, os.Stderr, p, l)
+	}
+	if err := p.Play(w); err != nil {
+		fail(err)
+	}
+}
+
+// open returns the writer of a destination.
+func open(to string) (io.Writer, error) {
+	switch {
+	case to == "-":
+		return os.Stdout, nil
+	case strings.HasPrefix(to, "tcp://"):
+		l, err := net.Listen("tcp", strings.TrimPrefix(to, "tcp://"))
+		if err != nil {
+			return nil, err
+		}
+		m := mux.NewMux()
+		// Lines are replayed as recorded, even with a bad checksum.
+		go m.ServeTCP(mux.Output{Name: "tcp", Rules: []mux.Rule{{Invalid: true}}}, l)
+		return routeWriter{m}, nil
+	case strings.HasPrefix(to, "udp://"):
+		return net.Dial("udp", strings.TrimPrefix(to, "udp://"))
+	}
+	return nil, fmt.Errorf("unknown destination: %s", to)
+}
+
+// routeWriter routes the lines written to the clients of a mux.
+type routeWriter struct {
+	m *mux.Mux
+}
+
+func (w routeWriter) Write(p []byte) (int, error) {
+	w.m.Route(mux.Input{Name: "replay"}, string(p))
+	return len(p), nil
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "nmeareplay: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,100 @@
+// Package coord formats latitudes and longitudes for output: the NMEA
+// ddmm.mmmm and dddmm.mmmm fields with a configurable precision, signed
+// degrees minutes seconds (DMS) and degrees decimal minutes (DDM) strings,
+// and UTM and MGRS grid references.
+//
+// Latitudes and longitudes are in decimal degrees, positive north and east.
+package coord
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// DefaultPrecision is the number of decimals of the minutes in NMEA fields.
+const DefaultPrecision = 4
+
+// Direction symbols
+const (
+	North = "N"
+	South = "S"
+	East  = "E"
+	West  = "W"
+)
+
+// LatDir returns the direction of a latitude.
+func LatDir(lat float64) string {
+	if lat < 0 {
+		return South
+	}
+	return North
+}
+
+// LonDir returns the direction of a longitude.
+func LonDir(lon float64) string {
+	if lon < 0 {
+		return West
+	}
+	return East
+}
+
+// NMEALat formats a latitude as the ddmm.mmmm field of a sentence with the
+// given number of decimals of the minutes, and returns its direction.
+func NMEALat(lat float64, precision int) (string, string) {
+	return degreesMinutes(math.Abs(lat), 2, precision), LatDir(lat)
+}
+
+// NMEALon formats a longitude as the dddmm.mmmm field of a sentence with
+// the given number of decimals of the minutes, and returns its direction.
+func NMEALon(lon float64, precision int) (string, string) {
+	return degreesMinutes(math.Abs(lon), 3, precision), LonDir(lon)
+}
+
+// DMS formats a coordinate as signed degrees, minutes and seconds with the
+// given number of decimals of the seconds, e.g. -33° 56' 26.06".
+func DMS(v float64, precision int) string {
+	d, m, s := splitDMS(math.Abs(v), precision)
+	return fmt.Sprintf("%s%d° %d' %s\"", sign(v), d, m, strconv.FormatFloat(s, 'f', precision, 64))
+}
+
+// DDM formats a coordinate as signed degrees and decimal minutes with the
+// given number of decimals of the minutes, e.g. -33° 56.4343'.
+func DDM(v float64, precision int) string {
+	d, m := splitDDM(math.Abs(v), precision)
+	return fmt.Sprintf("%s%d° %s'", sign(v), d, strconv.FormatFloat(m, 'f', precision, 64))
+}
+
+func sign(v float64) string {
+	if v < 0 {
+		return "-"
+	}
+	return ""
+}
+
+func degreesMinutes(v float64, width, precision int) string {
+	d, m := splitDDM(v, precision)
+	mw := 2
+	if precision > 0 {
+		mw += 1 + precision
+	}
+	return fmt.Sprintf("%0*d%0*.*f", width, d, mw, precision, m)
+}
+
+// splitDDM rounds on the minutes so 59.99996 becomes the next degree.
+func splitDDM(v float64, precision int) (int, float64) {
+	scale := math.Pow(10, float64(precision))
+	minutes := math.Round(v*60*scale) / scale
+	degrees := math.Floor(minutes / 60)
+	return int(degrees), minutes - degrees*60
+}
+
+// splitDMS rounds on the seconds so 59.99996 becomes the next minute.
+func splitDMS(v float64, precision int) (int, int, float64) {
+	scale := math.Pow(10, float64(precision))
+	seconds := math.Round(v*3600*scale) / scale
+	degrees := math.Floor(seconds / 3600)
+	seconds -= degrees * 3600
+	minutes := math.Floor(seconds / 60)
+	return int(degrees), int(minutes), seconds - minutes*60
+}
@@ -0,0 +1,62 @@
+package coord
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNMEA(t *testing.T) {
+	tests := []struct {
+		value     float64
+		precision int
+		lat, lon  string
+		latDir    string
+		lonDir    string
+	}{
+		{33.94057166666666, 4, "3356.4343", "03356.4343", North, East},
+		{-33.94057166666666, 2, "3356.43", "03356.43", South, West},
+		{5.5, 4, "0530.0000", "00530.0000", North, East},
+		{-0.5, 0, "0030", "00030", South, West},
+		{151.434367, 6, "15126.062020", "15126.062020", North, East},
+		{0.99999999, 4, "0100.0000", "00100.0000", North, East},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%f/%d", tt.value, tt.precision), func(t *testing.T) {
+			v, dir := NMEALat(tt.value, tt.precision)
+			assert.Equal(t, tt.lat, v)
+			assert.Equal(t, tt.latDir, dir)
+			v, dir = NMEALon(tt.value, tt.precision)
+			assert.Equal(t, tt.lon, v)
+			assert.Equal(t, tt.lonDir, dir)
+		})
+	}
+}
+
+func TestDMS(t *testing.T) {
+	tests := []struct {
+		value     float64
+		precision int
+		dms, ddm  string
+	}{
+		{33.94057166666666, 2, "33° 56' 26.06\"", "33° 56.43'"},
+		{-33.94057166666666, 1, "-33° 56' 26.1\"", "-33° 56.4'"},
+		{151.434367, 4, "151° 26' 3.7212\"", "151° 26.0620'"},
+		{45, 0, "45° 0' 0\"", "45° 0'"},
+		{0.999999999, 2, "1° 0' 0.00\"", "1° 0.00'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dms, func(t *testing.T) {
+			assert.Equal(t, tt.dms, DMS(tt.value, tt.precision))
+			assert.Equal(t, tt.ddm, DDM(tt.value, tt.precision))
+		})
+	}
+}
+
+func TestDirections(t *testing.T) {
+	assert.Equal(t, North, LatDir(10))
+	assert.Equal(t, South, LatDir(-10))
+	assert.Equal(t, East, LonDir(100))
+	assert.Equal(t, West, LonDir(-100))
+}
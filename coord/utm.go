@@ -0,0 +1,187 @@
+package coord
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrOutOfRange is returned for positions outside the UTM grid, which
+// covers latitudes from 80°S to 84°N.
+var ErrOutOfRange = errors.New("coord: position outside the UTM grid")
+
+// WGS84 ellipsoid parameters
+const (
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+
+	utmScale         = 0.9996
+	utmFalseEasting  = 500000.0
+	utmFalseNorthing = 10000000.0
+)
+
+// latitude bands, 8° each from 80°S, with X extended to 84°N
+const bands = "CDEFGHJKLMNPQRSTUVWXX"
+
+// UTM is a Universal Transverse Mercator grid reference.
+type UTM struct {
+	Zone     int     // Longitude zone, 1 to 60
+	Band     byte    // Latitude band letter, C to X
+	Easting  float64 // Meters
+	Northing float64 // Meters, from the equator or from 10000 km south of it
+}
+
+// String formats the grid reference to the meter, e.g. 31U 448251 5411932.
+func (u UTM) String() string {
+	return fmt.Sprintf("%d%c %d %d", u.Zone, u.Band, int(math.Floor(u.Easting)), int(math.Floor(u.Northing)))
+}
+
+// South reports whether the grid reference is in the southern hemisphere.
+func (u UTM) South() bool {
+	return u.Band < 'N'
+}
+
+// ToUTM converts a position to a UTM grid reference. The zone follows the
+// exceptions around Norway and Svalbard.
+func ToUTM(lat, lon float64) (UTM, error) {
+	if lat < -80 || lat > 84 || math.IsNaN(lat) || math.IsNaN(lon) {
+		return UTM{}, ErrOutOfRange
+	}
+	lon = math.Mod(lon+540, 360) - 180
+	zone := int(math.Floor((lon+180)/6)) + 1
+	if zone > 60 {
+		zone = 60
+	}
+	band := bands[int(math.Floor((lat+80)/8))]
+
+	switch {
+	case band == 'V' && zone == 31 && lon >= 3:
+		zone = 32
+	case band == 'X' && zone == 32:
+		zone = 31
+		if lon >= 9 {
+			zone = 33
+		}
+	case band == 'X' && zone == 34:
+		zone = 33
+		if lon >= 21 {
+			zone = 35
+		}
+	case band == 'X' && zone == 36:
+		zone = 35
+		if lon >= 33 {
+			zone = 37
+		}
+	}
+
+	e2 := 2*wgs84F - wgs84F*wgs84F
+	ep2 := e2 / (1 - e2)
+	phi := lat * math.Pi / 180
+	dlambda := (lon - centralMeridian(zone)) * math.Pi / 180
+	sinPhi, cosPhi, tanPhi := math.Sin(phi), math.Cos(phi), math.Tan(phi)
+
+	n := wgs84A / math.Sqrt(1-e2*sinPhi*sinPhi)
+	t := tanPhi * tanPhi
+	c := ep2 * cosPhi * cosPhi
+	a := cosPhi * dlambda
+	m := meridianArc(phi, e2)
+
+	easting := utmScale*n*(a+(1-t+c)*math.Pow(a, 3)/6+
+		(5-18*t+t*t+72*c-58*ep2)*math.Pow(a, 5)/120) + utmFalseEasting
+	northing := utmScale * (m + n*tanPhi*(a*a/2+(5-t+9*c+4*c*c)*math.Pow(a, 4)/24+
+		(61-58*t+t*t+600*c-330*ep2)*math.Pow(a, 6)/720))
+	if lat < 0 {
+		northing += utmFalseNorthing
+	}
+	return UTM{Zone: zone, Band: band, Easting: easting, Northing: northing}, nil
+}
+
+// LatLon converts the grid reference back to a position.
+func (u UTM) LatLon() (float64, float64) {
+	e2 := 2*wgs84F - wgs84F*wgs84F
+	ep2 := e2 / (1 - e2)
+	northing := u.Northing
+	if u.South() {
+		northing -= utmFalseNorthing
+	}
+
+	mu := northing / utmScale / (wgs84A * (1 - e2/4 - 3*e2*e2/64 - 5*e2*e2*e2/256))
+	e1 := (1 - math.Sqrt(1-e2)) / (1 + math.Sqrt(1-e2))
+	phi1 := mu + (3*e1/2-27*math.Pow(e1, 3)/32)*math.Sin(2*mu) +
+		(21*e1*e1/16-55*math.Pow(e1, 4)/32)*math.Sin(4*mu) +
+		151*math.Pow(e1, 3)/96*math.Sin(6*mu) +
+		1097*math.Pow(e1, 4)/512*math.Sin(8*mu)
+
+	sinPhi1, cosPhi1, tanPhi1 := math.Sin(phi1), math.Cos(phi1), math.Tan(phi1)
+	n1 := wgs84A / math.Sqrt(1-e2*sinPhi1*sinPhi1)
+	t1 := tanPhi1 * tanPhi1
+	c1 := ep2 * cosPhi1 * cosPhi1
+	r1 := wgs84A * (1 - e2) / math.Pow(1-e2*sinPhi1*sinPhi1, 1.5)
+	d := (u.Easting - utmFalseEasting) / (n1 * utmScale)
+
+	phi := phi1 - n1*tanPhi1/r1*(d*d/2-
+		(5+3*t1+10*c1-4*c1*c1-9*ep2)*math.Pow(d, 4)/24+
+		(61+90*t1+298*c1+45*t1*t1-252*ep2-3*c1*c1)*math.Pow(d, 6)/720)
+	dlambda := (d - (1+2*t1+c1)*math.Pow(d, 3)/6 +
+		(5-2*c1+28*t1-3*c1*c1+8*ep2+24*t1*t1)*math.Pow(d, 5)/120) / cosPhi1
+
+	return phi * 180 / math.Pi, centralMeridian(u.Zone) + dlambda*180/math.Pi
+}
+
+func centralMeridian(zone int) float64 {
+	return float64(zone-1)*6 - 180 + 3
+}
+
+func meridianArc(phi, e2 float64) float64 {
+	e4, e6 := e2*e2, e2*e2*e2
+	return wgs84A * ((1-e2/4-3*e4/64-5*e6/256)*phi -
+		(3*e2/8+3*e4/32+45*e6/1024)*math.Sin(2*phi) +
+		(15*e4/256+45*e6/1024)*math.Sin(4*phi) -
+		35*e6/3072*math.Sin(6*phi))
+}
+
+// 100 km square letters, by zone set
+var (
+	mgrsColumns = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+	mgrsRows    = [2]string{"ABCDEFGHJKLMNPQRSTUV", "FGHJKLMNPQRSTUVABCDE"}
+)
+
+// ToMGRS converts a position to a Military Grid Reference System grid
+// reference with the given number of digits for the easting and the
+// northing, from 1 (10 km) to 5 (1 m), e.g. 31U DQ 48251 11932.
+func ToMGRS(lat, lon float64, digits int) (string, error) {
+	u, err := ToUTM(lat, lon)
+	if err != nil {
+		return "", err
+	}
+	return u.MGRS(digits)
+}
+
+// MGRS formats the grid reference as a Military Grid Reference System grid
+// reference with the given number of digits for the easting and the
+// northing, from 1 (10 km) to 5 (1 m). An error is returned when the
+// easting lies outside the 100 km squares, below 100 km or from 900 km.
+func (u UTM) MGRS(digits int) (string, error) {
+	if digits < 1 {
+		digits = 1
+	} else if digits > 5 {
+		digits = 5
+	}
+	if u.Zone < 1 || u.Zone > 60 {
+		return "", fmt.Errorf("coord: zone %d out of range", u.Zone)
+	}
+	if !(u.Easting >= 1e5 && u.Easting < 9e5) {
+		return "", fmt.Errorf("coord: easting %v out of range", u.Easting)
+	}
+	if !(u.Northing >= 0) {
+		return "", fmt.Errorf("coord: northing %v out of range", u.Northing)
+	}
+	set := (u.Zone - 1) % 6
+	column := mgrsColumns[set%3][int(math.Floor(u.Easting/1e5))-1]
+	row := mgrsRows[set%2][int(math.Floor(u.Northing/1e5))%20]
+
+	div := math.Pow(10, float64(5-digits))
+	easting := int(math.Floor(math.Mod(u.Easting, 1e5) / div))
+	northing := int(math.Floor(math.Mod(u.Northing, 1e5) / div))
+	return fmt.Sprintf("%d%c %c%c %0*d %0*d", u.Zone, u.Band, column, row, digits, easting, digits, northing), nil
+}
@@ -0,0 +1,63 @@
+package coord
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var utmtests = []struct {
+	name     string
+	lat, lon float64
+	utm      string
+	mgrs     string
+	digits   int
+}{
+	{"Eiffel Tower", 48.8583, 2.2945, "31U 448251 5411943", "31U DQ 48251 11943", 5},
+	{"Sydney Opera House", -33.8568, 151.2153, "56H 334900 6252288", "56H LH 349 522", 3},
+	{"Bergen", 60.3913, 5.3221, "32V 297353 6700648", "32V KN 97 00", 2},
+	{"Longyearbyen", 78.22, 15.65, "33X 514813 8683004", "33X WG 1 8", 1},
+}
+
+func TestUTM(t *testing.T) {
+	for _, tt := range utmtests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := ToUTM(tt.lat, tt.lon)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.utm, u.String())
+			assert.Equal(t, tt.lat < 0, u.South())
+
+			lat, lon := u.LatLon()
+			assert.InDelta(t, tt.lat, lat, 1e-6)
+			assert.InDelta(t, tt.lon, lon, 1e-6)
+
+			mgrs, err := ToMGRS(tt.lat, tt.lon, tt.digits)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.mgrs, mgrs)
+		})
+	}
+}
+
+func TestUTMOutOfRange(t *testing.T) {
+	_, err := ToUTM(85, 0)
+	assert.Equal(t, ErrOutOfRange, err)
+	_, err = ToMGRS(-81, 0, 5)
+	assert.Equal(t, ErrOutOfRange, err)
+}
+
+func TestMGRSOutOfRange(t *testing.T) {
+	for _, u := range []UTM{
+		{Zone: 31, Band: 'U', Easting: 99999, Northing: 5411932},
+		{Zone: 31, Band: 'U', Easting: 900000, Northing: 5411932},
+		{Zone: 31, Band: 'U', Easting: -1, Northing: 5411932},
+		{Zone: 0, Band: 'U', Easting: 448251, Northing: 5411932},
+		{Zone: 31, Band: 'U', Easting: 448251, Northing: -1},
+	} {
+		_, err := u.MGRS(5)
+		assert.Error(t, err, u.String())
+	}
+
+	mgrs, err := UTM{Zone: 31, Band: 'U', Easting: 100000, Northing: 5411932}.MGRS(5)
+	assert.NoError(t, err)
+	assert.Equal(t, "31U AQ 00000 11932", mgrs)
+}
@@ -0,0 +1,273 @@
+// Package csvexport flattens sentence streams into CSV tables. A Writer
+// writes one table per sentence type with a column per struct field, and a
+// WideWriter writes a single table with a row per epoch, aligning the
+// sentences of each fix on their Time field.
+//
+// Lists are expanded into fixed groups of columns, e.g. the satellites of
+// GSV.Info become Info1.SVPRNNumber, Info1.Elevation, ... Info4.SNR.
+// Entries beyond the columns of a list are left out and reported as an
+// error, which ListColumns can be raised to avoid. Times
+// and dates are ISO 8601 and missing values, like an HDOP whose HDOPValid
+// is false, are empty cells.
+package csvexport
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// DefaultListColumns is the number of column groups of lists without an
+// entry in ListColumns.
+const DefaultListColumns = 8
+
+// ListColumns is the number of column groups of the lists of each type,
+// keyed by type and field name.
+var ListColumns = map[string]int{
+	"GSV.Info":  4,
+	"GSA.SV":    12,
+	"GNS.Mode":  6,
+	"RTE.Ident": 16,
+}
+
+// sentences holds a zero value of every sentence type, so the columns of a
+// table can be known before any sentence is seen.
+var sentences = map[string]nmea.Sentence{
+	nmea.TypeRMC:   nmea.RMC{},
+	nmea.TypeGGA:   nmea.GGA{},
+	nmea.TypeGSA:   nmea.GSA{},
+	nmea.TypeGLL:   nmea.GLL{},
+	nmea.TypeVTG:   nmea.VTG{},
+	nmea.TypeZDA:   nmea.ZDA{},
+	nmea.TypePGRME: nmea.PGRME{},
+	nmea.TypePGRMM: nmea.PGRMM{},
+	nmea.TypePCDIN: nmea.PCDIN{},
+	nmea.TypeGSV:   nmea.GSV{},
+	nmea.TypeHDT:   nmea.HDT{},
+	nmea.TypeGNS:   nmea.GNS{},
+	nmea.TypeTHS:   nmea.THS{},
+	nmea.TypeWPL:   nmea.WPL{},
+	nmea.TypeRTE:   nmea.RTE{},
+	nmea.TypeVHW:   nmea.VHW{},
+	nmea.TypeDPT:   nmea.DPT{},
+	nmea.TypeDBT:   nmea.DBT{},
+	nmea.TypeDBS:   nmea.DBS{},
+	nmea.TypeDTM:   nmea.DTM{},
+	nmea.TypeMWV:   nmea.MWV{},
+	nmea.TypeVDM:   nmea.VDMVDO{},
+	nmea.TypeVDO:   nmea.VDMVDO{},
+	nmea.TypeMTK:   nmea.MTK{},
+}
+
+var (
+	baseSentenceType = reflect.TypeOf(nmea.BaseSentence{})
+	timeType         = reflect.TypeOf(nmea.Time{})
+	dateType         = reflect.TypeOf(nmea.Date{})
+)
+
+// typeOf returns the type used to name the table of a sentence.
+func typeOf(s nmea.Sentence) string {
+	if s.TalkerID() == nmea.TypeMTK {
+		return nmea.TypeMTK
+	}
+	return s.DataType()
+}
+
+// Columns returns the column names of the table of a sentence type, or
+// false if the type is not supported.
+func Columns(typ string) ([]string, bool) {
+	s, ok := sentences[typ]
+	if !ok {
+		return nil, false
+	}
+	var cols []string
+	walk(typ, reflect.ValueOf(s), "", func(name string, _ string) {
+		cols = append(cols, name)
+	})
+	return append([]string{"Talker"}, cols...), true
+}
+
+// Row returns the cells of a sentence, matching the Columns of its type.
+// When a list has more entries than columns, the row is returned along
+// with an error.
+func Row(s nmea.Sentence) ([]string, error) {
+	row := []string{s.TalkerID()}
+	err := walk(typeOf(s), reflect.ValueOf(s), "", func(_ string, value string) {
+		row = append(row, value)
+	})
+	return row, err
+}
+
+// walk calls fn with the name and formatted value of every column of a
+// struct, expanding lists into fixed column groups. The first list with
+// more entries than columns is reported as an error.
+func walk(typ string, v reflect.Value, prefix string, fn func(name, value string)) error {
+	var overflow error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Type == baseSentenceType {
+			continue
+		}
+		if name := strings.TrimSuffix(f.Name, "Valid"); name != f.Name && f.Type.Kind() == reflect.Bool {
+			if _, ok := t.FieldByName(name); ok {
+				continue
+			}
+		}
+		field := v.Field(i)
+		valid := true
+		if companion := v.FieldByName(f.Name + "Valid"); companion.IsValid() && companion.Kind() == reflect.Bool {
+			valid = companion.Bool()
+		}
+
+		switch {
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() != reflect.Uint8:
+			n := listColumns(typ, f.Name)
+			if field.Len() > n && overflow == nil {
+				overflow = fmt.Errorf("csvexport: %s.%s%s has %d entries, only %d are written",
+					typ, prefix, f.Name, field.Len(), n)
+			}
+			for j := 0; j < n; j++ {
+				name := fmt.Sprintf("%s%s%d", prefix, strings.TrimSuffix(f.Name, "s"), j+1)
+				var elem reflect.Value
+				if j < field.Len() {
+					elem = field.Index(j)
+				} else {
+					elem = reflect.Zero(f.Type.Elem())
+				}
+				if elem.Kind() == reflect.Struct && elem.Type() != timeType && elem.Type() != dateType {
+					present := j < field.Len()
+					err := walk(typ, elem, name+".", func(n, value string) {
+						if !present {
+							value = ""
+						}
+						fn(n, value)
+					})
+					if overflow == nil {
+						overflow = err
+					}
+					continue
+				}
+				value := ""
+				if j < field.Len() {
+					value = format(elem)
+				}
+				fn(name, value)
+			}
+		case !valid:
+			fn(prefix+f.Name, "")
+		default:
+			fn(prefix+f.Name, format(field))
+		}
+	}
+	return overflow
+}
+
+func listColumns(typ, field string) int {
+	if n, ok := ListColumns[typ+"."+strings.TrimSuffix(field, "s")]; ok {
+		return n
+	}
+	if n, ok := ListColumns[typ+"."+field]; ok {
+		return n
+	}
+	return DefaultListColumns
+}
+
+func format(v reflect.Value) string {
+	switch x := v.Interface().(type) {
+	case nmea.Time:
+		return formatJSON(x.MarshalJSON())
+	case nmea.Date:
+		return formatJSON(x.MarshalJSON())
+	case []byte:
+		return hex.EncodeToString(x)
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.String:
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// formatJSON turns a JSON string or null into a cell.
+func formatJSON(b []byte, err error) string {
+	if err != nil || string(b) == "null" {
+		return ""
+	}
+	s, _ := strconv.Unquote(string(b))
+	return s
+}
+
+// Writer writes one CSV table per sentence type.
+type Writer struct {
+	open   func(typ string) (io.Writer, error)
+	types  map[string]bool
+	tables map[string]*csv.Writer
+}
+
+// NewWriter returns a writer for the given sentence types, or for every
+// supported type when none is given. The open function is called with the
+// type of the first sentence of each table to get its destination.
+func NewWriter(open func(typ string) (io.Writer, error), types ...string) *Writer {
+	w := &Writer{open: open, tables: map[string]*csv.Writer{}}
+	if len(types) > 0 {
+		w.types = map[string]bool{}
+		for _, t := range types {
+			w.types[t] = true
+		}
+	}
+	return w
+}
+
+// Write adds a sentence to the table of its type. Sentences of other types
+// are ignored. A row with lists longer than their columns is written and
+// the error of Row is returned.
+func (w *Writer) Write(s nmea.Sentence) error {
+	typ := typeOf(s)
+	if _, ok := sentences[typ]; !ok || (w.types != nil && !w.types[typ]) {
+		return nil
+	}
+	table, ok := w.tables[typ]
+	if !ok {
+		out, err := w.open(typ)
+		if err != nil {
+			return err
+		}
+		table = csv.NewWriter(out)
+		cols, _ := Columns(typ)
+		if err := table.Write(cols); err != nil {
+			return err
+		}
+		w.tables[typ] = table
+	}
+	row, overflow := Row(s)
+	if err := table.Write(row); err != nil {
+		return err
+	}
+	return overflow
+}
+
+// Flush flushes every table.
+func (w *Writer) Flush() error {
+	for _, table := range w.tables {
+		table.Flush()
+		if err := table.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
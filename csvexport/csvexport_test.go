@@ -0,0 +1,137 @@
+package csvexport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+var stream = []string{
+	"$GPRMC,120000,A,5128.62,N,00027.58,W,5.0,90.0,150320,,*37",
+	"$GPGGA,120000,5128.62,N,00027.58,W,2,08,0.9,25.5,M,47.0,M,,*62",
+	"$GPGSA,A,3,22,19,18,27,14,03,,,,,,,3.1,2.0,2.4*36",
+	"$GPGSV,1,1,02,11,50,310,42,12,10,020,*7A",
+	"$GPGGA,120001,5128.62,N,00027.50,W,1,07,,26.0,M,47.0,M,,*46",
+	"$GPHDT,123.456,T*32",
+}
+
+func parse(t *testing.T, raw string) nmea.Sentence {
+	s, err := nmea.Parse(raw)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestColumns(t *testing.T) {
+	cols, ok := Columns(nmea.TypeGGA)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Talker", "Time", "Latitude", "Longitude", "FixQuality", "NumSatellites",
+		"HDOP", "Altitude", "Separation", "DGPSAge", "DGPSId"}, cols)
+
+	cols, ok = Columns(nmea.TypeGSV)
+	assert.True(t, ok)
+	assert.Len(t, cols, 4+4*4)
+	assert.Equal(t, "Info1.SVPRNNumber", cols[4])
+	assert.Equal(t, "Info4.SNR", cols[19])
+
+	cols, ok = Columns(nmea.TypeGSA)
+	assert.True(t, ok)
+	assert.Equal(t, "SV1", cols[3])
+	assert.Equal(t, "SV12", cols[14])
+
+	_, ok = Columns("XYZ")
+	assert.False(t, ok)
+}
+
+func TestRow(t *testing.T) {
+	row, err := Row(parse(t, stream[4]))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GP", "12:00:01.000", "51.477", "-0.4583333333333333", "1", "7",
+		"", "26", "47", "", ""}, row)
+
+	row, err = Row(parse(t, stream[3]))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GP", "1", "1", "2",
+		"11", "50", "310", "42",
+		"12", "10", "20", "",
+		"", "", "", "",
+		"", "", "", ""}, row)
+
+	row, err = Row(parse(t, stream[0]))
+	assert.NoError(t, err)
+	assert.Equal(t, "2020-03-15", row[7])
+}
+
+func TestRowOverflow(t *testing.T) {
+	idents := []string{"1", "1", "c", "0"}
+	for i := 1; i <= 17; i++ {
+		idents = append(idents, fmt.Sprintf("WP%02d", i))
+	}
+	s := parse(t, nmea.FormatSentence(nmea.SentenceStart, "GPRTE", idents))
+
+	row, err := Row(s)
+	assert.EqualError(t, err, "csvexport: RTE.Idents has 17 entries, only 16 are written")
+	cols, _ := Columns(nmea.TypeRTE)
+	assert.Len(t, row, len(cols))
+	assert.Equal(t, "WP16", row[len(row)-1])
+
+	b := buffers{}
+	w := NewWriter(b.open)
+	assert.EqualError(t, w.Write(s), "csvexport: RTE.Idents has 17 entries, only 16 are written")
+	assert.NoError(t, w.Flush())
+	assert.Contains(t, b[nmea.TypeRTE].String(), "WP16\n")
+}
+
+type buffers map[string]*bytes.Buffer
+
+func (b buffers) open(typ string) (io.Writer, error) {
+	if typ == "HDT" {
+		return nil, errors.New("no table for HDT")
+	}
+	b[typ] = &bytes.Buffer{}
+	return b[typ], nil
+}
+
+func TestWriter(t *testing.T) {
+	tables := buffers{}
+	w := NewWriter(tables.open, nmea.TypeGGA, nmea.TypeRMC)
+	for _, raw := range stream {
+		assert.NoError(t, w.Write(parse(t, raw)))
+	}
+	assert.NoError(t, w.Flush())
+	assert.Len(t, tables, 2)
+	assert.Equal(t, `Talker,Time,Latitude,Longitude,FixQuality,NumSatellites,HDOP,Altitude,Separation,DGPSAge,DGPSId
+GP,12:00:00.000,51.477,-0.4596666666666666,2,8,0.9,25.5,47,,
+GP,12:00:01.000,51.477,-0.4583333333333333,1,7,,26,47,,
+`, tables["GGA"].String())
+
+	w = NewWriter(buffers{}.open)
+	for _, raw := range stream[:5] {
+		assert.NoError(t, w.Write(parse(t, raw)))
+	}
+	assert.EqualError(t, w.Write(parse(t, stream[5])), "no table for HDT")
+}
+
+func TestWideWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWideWriter(&buf, nmea.TypeGGA, nmea.TypeHDT)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, raw := range stream {
+		assert.NoError(t, w.Write(parse(t, raw)))
+	}
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, `Time,GGA.Talker,GGA.Time,GGA.Latitude,GGA.Longitude,GGA.FixQuality,GGA.NumSatellites,GGA.HDOP,GGA.Altitude,GGA.Separation,GGA.DGPSAge,GGA.DGPSId,HDT.Talker,HDT.Heading,HDT.True
+12:00:00.000,GP,12:00:00.000,51.477,-0.4596666666666666,2,8,0.9,25.5,47,,,,,
+12:00:01.000,GP,12:00:01.000,51.477,-0.4583333333333333,1,7,,26,47,,,GP,123.456,true
+`, buf.String())
+
+	_, err = NewWideWriter(&buf, "XYZ")
+	assert.EqualError(t, err, "csvexport: sentence type 'XYZ' not supported")
+}
@@ -0,0 +1,109 @@
+package csvexport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// WideWriter writes a single time-aligned table with a row per epoch. An
+// epoch starts with every sentence whose Time differs from the current
+// one; sentences without a Time field, like GSA or VTG, belong to the
+// current epoch. Columns are prefixed with their type, e.g. GGA.HDOP, and
+// the last sentence of a type in an epoch wins.
+type WideWriter struct {
+	w       *csv.Writer
+	types   []string
+	offsets map[string]int
+	columns int
+	row     []string
+	epoch   nmea.Time
+	pending bool
+	header  bool
+}
+
+// NewWideWriter returns a writer of a wide table of the given types.
+func NewWideWriter(w io.Writer, types ...string) (*WideWriter, error) {
+	ww := &WideWriter{w: csv.NewWriter(w), types: types, offsets: map[string]int{}, columns: 1}
+	for _, typ := range types {
+		cols, ok := Columns(typ)
+		if !ok {
+			return nil, fmt.Errorf("csvexport: sentence type '%s' not supported", typ)
+		}
+		ww.offsets[typ] = ww.columns
+		ww.columns += len(cols)
+	}
+	return ww, nil
+}
+
+// Write adds a sentence to the current epoch, writing the previous epoch
+// out when the sentence starts a new one. The error of Row is returned for
+// lists longer than their columns.
+func (ww *WideWriter) Write(s nmea.Sentence) error {
+	typ := typeOf(s)
+	offset, ok := ww.offsets[typ]
+	if !ok {
+		return nil
+	}
+	if t, ok := sentenceTime(s); ok && t.Valid {
+		if ww.pending && t != ww.epoch {
+			if err := ww.flushRow(); err != nil {
+				return err
+			}
+		}
+		ww.epoch = t
+	}
+	if !ww.pending {
+		ww.row = make([]string, ww.columns)
+		ww.pending = true
+	}
+	row, err := Row(s)
+	copy(ww.row[offset:], row)
+	return err
+}
+
+// Flush writes the current epoch out and flushes the table.
+func (ww *WideWriter) Flush() error {
+	if ww.pending {
+		if err := ww.flushRow(); err != nil {
+			return err
+		}
+	}
+	ww.w.Flush()
+	return ww.w.Error()
+}
+
+func (ww *WideWriter) flushRow() error {
+	if !ww.header {
+		header := []string{"Time"}
+		for _, typ := range ww.types {
+			cols, _ := Columns(typ)
+			for _, c := range cols {
+				header = append(header, typ+"."+c)
+			}
+		}
+		if err := ww.w.Write(header); err != nil {
+			return err
+		}
+		ww.header = true
+	}
+	ww.row[0] = formatJSON(ww.epoch.MarshalJSON())
+	ww.pending = false
+	return ww.w.Write(ww.row)
+}
+
+// sentenceTime returns the Time field of a sentence.
+func sentenceTime(s nmea.Sentence) (nmea.Time, bool) {
+	v := reflect.ValueOf(s)
+	if v.Kind() != reflect.Struct {
+		return nmea.Time{}, false
+	}
+	f := v.FieldByName("Time")
+	if !f.IsValid() || f.Type() != timeType {
+		return nmea.Time{}, false
+	}
+	return f.Interface().(nmea.Time), true
+}
@@ -0,0 +1,106 @@
+package nmea
+
+import (
+	"sync"
+	"time"
+)
+
+// DateTracker infers the date of sentences which only carry a time of day,
+// such as GGA or GLL, from the last date seen on the stream in RMC or ZDA
+// sentences. Crossing midnight between two dated sentences advances the
+// date by one day.
+type DateTracker struct {
+	Pivot int // Century pivot for two-digit years
+	// Reference enables the GPS week rollover correction of the dates seen,
+	// see Date.CorrectRollover. The zero time disables it.
+	Reference time.Time
+
+	mu    sync.Mutex
+	date  time.Time     // Midnight of the current date, in UTC
+	last  time.Duration // Last time of day seen
+	valid bool
+}
+
+// NewDateTracker constructor
+func NewDateTracker() *DateTracker {
+	return &DateTracker{Pivot: DefaultCenturyPivot}
+}
+
+// Update observes a sentence and returns its time, when it can be
+// determined. RMC and ZDA sentences set the current date.
+func (d *DateTracker) Update(s Sentence) (time.Time, bool) {
+	switch m := s.(type) {
+	case RMC:
+		m, _ = m.CorrectRollover(d.Reference)
+		tod := m.timeOfDay(0, m.Time)
+		t, err := dateTime(d.Pivot, m.Date, m.Time, tod)
+		if err != nil {
+			return d.resolve(m.Time, tod)
+		}
+		d.set(t)
+		return t, true
+	case ZDA:
+		m, _ = m.CorrectRollover(d.Reference)
+		t, err := m.DateTime()
+		if err != nil {
+			return d.resolve(m.Time, m.timeOfDay(0, m.Time))
+		}
+		d.set(t)
+		return t, true
+	case GGA:
+		return d.resolve(m.Time, m.timeOfDay(0, m.Time))
+	case GLL:
+		return d.resolve(m.Time, m.timeOfDay(4, m.Time))
+	case GNS:
+		return d.resolve(m.Time, m.timeOfDay(0, m.Time))
+	}
+	return time.Time{}, false
+}
+
+// Resolve combines the time of day with the current date. If the time is
+// more than twelve hours before the last time seen, midnight has passed
+// and the date is advanced. If it is more than twelve hours after it, the
+// time belongs to the previous day.
+func (d *DateTracker) Resolve(t Time) (time.Time, bool) {
+	return d.resolve(t, t.Duration())
+}
+
+// resolve combines the time of day tod, elapsed since midnight, with the
+// current date.
+func (d *DateTracker) resolve(t Time, tod time.Duration) (time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.valid || !t.Valid {
+		return time.Time{}, false
+	}
+	switch {
+	case tod < d.last-12*time.Hour:
+		d.date = d.date.AddDate(0, 0, 1)
+		d.last = tod
+	case tod > d.last+12*time.Hour:
+		return d.date.AddDate(0, 0, -1).Add(tod), true
+	default:
+		if tod > d.last {
+			d.last = tod
+		}
+	}
+	return d.date.Add(tod), true
+}
+
+// Date returns the current date, if one has been seen.
+func (d *DateTracker) Date() (time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.date, d.valid
+}
+
+func (d *DateTracker) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.date = truncateDay(t)
+	d.last = t.Sub(d.date)
+	d.valid = true
+}
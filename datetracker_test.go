@@ -0,0 +1,75 @@
+package nmea
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseFields(t *testing.T, prefix string, fields ...string) Sentence {
+	s, err := Parse(FormatSentence(SentenceStart, prefix, fields))
+	assert.NoError(t, err)
+	return s
+}
+
+func ggaAt(t *testing.T, hhmmss string) Sentence {
+	return mustParseFields(t, "GPGGA", hhmmss, "3356.4650", "S", "15124.5567", "E", "1", "03", "9.7", "-25.0", "M", "21.0", "M", "", "0000")
+}
+
+func TestDateTracker(t *testing.T) {
+	d := NewDateTracker()
+
+	_, ok := d.Update(ggaAt(t, "235958.5"))
+	assert.False(t, ok, "no date seen yet")
+
+	rmc := mustParseFields(t, "GPRMC", "235959", "A", "5133.82", "N", "00042.24", "W", "173.8", "231.8", "311219", "004.2", "W")
+	ts, ok := d.Update(rmc)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2019, 12, 31, 23, 59, 59, 0, time.UTC), ts)
+
+	ts, ok = d.Update(ggaAt(t, "235959.5"))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2019, 12, 31, 23, 59, 59, 5e8, time.UTC), ts)
+
+	// midnight rollover
+	ts, ok = d.Update(ggaAt(t, "000000.5"))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 5e8, time.UTC), ts)
+
+	// late sentence from before midnight
+	ts, ok = d.Update(ggaAt(t, "235959.9"))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2019, 12, 31, 23, 59, 59, 9e8, time.UTC), ts)
+
+	date, ok := d.Date()
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), date)
+
+	zda := mustParseFields(t, "GPZDA", "120000", "15", "03", "2021", "01", "30")
+	ts, ok = d.Update(zda)
+	assert.True(t, ok)
+	assert.True(t, time.Date(2021, 3, 15, 12, 0, 0, 0, time.UTC).Equal(ts))
+
+	ts, ok = d.Update(ggaAt(t, "120001"))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2021, 3, 15, 12, 0, 1, 0, time.UTC), ts)
+
+	_, ok = d.Update(mustParseFields(t, "GPHDT", "123.456", "T"))
+	assert.False(t, ok)
+}
+
+func TestDateTrackerReference(t *testing.T) {
+	d := NewDateTracker()
+	d.Reference = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rmc := mustParseFields(t, "GPRMC", "220516", "A", "5133.82", "N", "00042.24", "W", "173.8", "231.8", "031199", "004.2", "W")
+	ts, ok := d.Update(rmc)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2019, 6, 19, 22, 5, 16, 0, time.UTC), ts)
+
+	zda := mustParseFields(t, "GPZDA", "172809", "03", "11", "1999", "00", "00")
+	ts, ok = d.Update(zda)
+	assert.True(t, ok)
+	assert.True(t, time.Date(2019, 6, 19, 17, 28, 9, 0, time.UTC).Equal(ts))
+}
@@ -16,9 +16,20 @@ var dbstests = []struct {
 		name: "good sentence",
 		raw:  "$23DBS,01.9,f,0.58,M,00.3,F*21",
 		msg: DBS{
-			DepthFeet:    MustParseDecimal("1.9"),
-			DepthMeters:  MustParseDecimal("0.58"),
-			DepthFathoms: MustParseDecimal("0.3"),
+			DepthFeet:         MustParseDecimal("1.9"),
+			DepthFeetValid:    true,
+			DepthMeters:       MustParseDecimal("0.58"),
+			DepthMetersValid:  true,
+			DepthFathoms:      MustParseDecimal("0.3"),
+			DepthFathomsValid: true,
+		},
+	},
+	{
+		name: "good sentence with meters only",
+		raw:  "$23DBS,,f,0.58,M,,F*2A",
+		msg: DBS{
+			DepthMeters:      MustParseDecimal("0.58"),
+			DepthMetersValid: true,
 		},
 	},
 	{
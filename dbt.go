@@ -9,19 +9,34 @@ const (
 // https://gpsd.gitlab.io/gpsd/NMEA.html#_dbt_depth_below_transducer
 type DBT struct {
 	BaseSentence
-	DepthFeet    float64
-	DepthMeters  float64
-	DepthFathoms float64
+	DepthFeet         float64
+	DepthFeetValid    bool // DepthFeet is present in the sentence
+	DepthMeters       float64
+	DepthMetersValid  bool // DepthMeters is present in the sentence
+	DepthFathoms      float64
+	DepthFathomsValid bool // DepthFathoms is present in the sentence
 }
 
 // newDBT constructor
 func newDBT(s BaseSentence) (DBT, error) {
 	p := NewParser(s)
 	p.AssertType(TypeDBT)
-	return DBT{
-		BaseSentence: s,
-		DepthFeet:    p.Float64(0, "depth_feet"),
-		DepthMeters:  p.Float64(2, "depth_meters"),
-		DepthFathoms: p.Float64(4, "depth_fathoms"),
-	}, p.Err()
+	m := DBT{BaseSentence: s}
+	feet := p.NullFloat64(0, "depth_feet")
+	m.DepthFeet, m.DepthFeetValid = feet.Value, feet.Valid
+	meters := p.NullFloat64(2, "depth_meters")
+	m.DepthMeters, m.DepthMetersValid = meters.Value, meters.Valid
+	fathoms := p.NullFloat64(4, "depth_fathoms")
+	m.DepthFathoms, m.DepthFathomsValid = fathoms.Value, fathoms.Valid
+	return m, p.Err()
+}
+
+// MarshalJSON implements json.Marshaler
+func (s DBT) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *DBT) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
 }
@@ -16,11 +16,19 @@ var dbttests = []struct {
 		name: "good sentence",
 		raw:  "$IIDBT,032.93,f,010.04,M,005.42,F*2C",
 		msg: DBT{
-			DepthFeet:    MustParseDecimal("32.93"),
-			DepthMeters:  MustParseDecimal("10.04"),
-			DepthFathoms: MustParseDecimal("5.42"),
+			DepthFeet:         MustParseDecimal("32.93"),
+			DepthFeetValid:    true,
+			DepthMeters:       MustParseDecimal("10.04"),
+			DepthMetersValid:  true,
+			DepthFathoms:      MustParseDecimal("5.42"),
+			DepthFathomsValid: true,
 		},
 	},
+	{
+		name: "good sentence without depth",
+		raw:  "$SDDBT,,f,,M,,F*28",
+		msg:  DBT{},
+	},
 	{
 		name: "bad validity",
 		raw:  "$IIDBT,032.93,f,010.04,M,005.42,F*22",
@@ -20,10 +20,10 @@ var glgsvtests = []struct {
 			MessageNumber:   1,
 			NumberSVsInView: 11,
 			Info: []GLGSVInfo{
-				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0},
-				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0},
-				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12},
-				{SVPRNNumber: 13, Elevation: 6, Azimuth: 292, SNR: 0},
+				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12, SNRValid: true},
+				{SVPRNNumber: 13, Elevation: 6, Azimuth: 292, SNR: 0, SNRValid: true},
 			},
 		},
 	},
@@ -35,9 +35,9 @@ var glgsvtests = []struct {
 			MessageNumber:   1,
 			NumberSVsInView: 11,
 			Info: []GLGSVInfo{
-				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0},
-				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0},
-				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12},
+				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12, SNRValid: true},
 			},
 		},
 	},
@@ -112,15 +112,18 @@ var gnggatests = []struct {
 				Second:      15,
 				Millisecond: 0,
 			},
-			Latitude:      MustParseLatLong("6325.6138 N"),
-			Longitude:     MustParseLatLong("01021.4290 E"),
-			FixQuality:    "1",
-			NumSatellites: 8,
-			HDOP:          2.42,
-			Altitude:      72.5,
-			Separation:    41.5,
-			DGPSAge:       "",
-			DGPSId:        "",
+			Latitude:        MustParseLatLong("6325.6138 N"),
+			Longitude:       MustParseLatLong("01021.4290 E"),
+			FixQuality:      "1",
+			NumSatellites:   8,
+			HDOP:            2.42,
+			Altitude:        72.5,
+			Separation:      41.5,
+			DGPSAge:         "",
+			DGPSId:          "",
+			HDOPValid:       true,
+			AltitudeValid:   true,
+			SeparationValid: true,
 		},
 	},
 	{
@@ -167,48 +170,54 @@ var gngnstests = []struct {
 		name: "good sentence A",
 		raw:  "$GNGNS,014035.00,4332.69262,S,17235.48549,E,RR,13,0.9,25.63,11.24,,*70",
 		msg: GNGNS{
-			Time:       Time{true, 1, 40, 35, 0},
-			Latitude:   MustParseGPS("4332.69262 S"),
-			Longitude:  MustParseGPS("17235.48549 E"),
-			Mode:       []string{"R", "R"},
-			SVs:        13,
-			HDOP:       0.9,
-			Altitude:   25.63,
-			Separation: 11.24,
-			Age:        0,
-			Station:    0,
+			Time:            Time{true, 1, 40, 35, 0},
+			Latitude:        MustParseGPS("4332.69262 S"),
+			Longitude:       MustParseGPS("17235.48549 E"),
+			Mode:            []string{"R", "R"},
+			SVs:             13,
+			HDOP:            0.9,
+			Altitude:        25.63,
+			AltitudeValid:   true,
+			Separation:      11.24,
+			SeparationValid: true,
+			Age:             0,
+			Station:         0,
 		},
 	},
 	{
 		name: "good sentence B",
 		raw:  "$GNGNS,094821.0,4849.931307,N,00216.053323,E,AA,14,0.6,161.5,48.0,,*6D",
 		msg: GNGNS{
-			Time:       Time{true, 9, 48, 21, 0},
-			Latitude:   MustParseGPS("4849.931307 N"),
-			Longitude:  MustParseGPS("00216.053323 E"),
-			Mode:       []string{"A", "A"},
-			SVs:        14,
-			HDOP:       0.6,
-			Altitude:   161.5,
-			Separation: 48.0,
-			Age:        0,
-			Station:    0,
+			Time:            Time{true, 9, 48, 21, 0},
+			Latitude:        MustParseGPS("4849.931307 N"),
+			Longitude:       MustParseGPS("00216.053323 E"),
+			Mode:            []string{"A", "A"},
+			SVs:             14,
+			HDOP:            0.6,
+			Altitude:        161.5,
+			AltitudeValid:   true,
+			Separation:      48.0,
+			SeparationValid: true,
+			Age:             0,
+			Station:         0,
 		},
 	},
 	{
 		name: "good sentence B",
 		raw:  "$GNGNS,094821.0,4849.931307,N,00216.053323,E,AAN,14,0.6,161.5,48.0,,*23",
 		msg: GNGNS{
-			Time:       Time{true, 9, 48, 21, 0},
-			Latitude:   MustParseGPS("4849.931307 N"),
-			Longitude:  MustParseGPS("00216.053323 E"),
-			Mode:       []string{"A", "A", "N"},
-			SVs:        14,
-			HDOP:       0.6,
-			Altitude:   161.5,
-			Separation: 48.0,
-			Age:        0,
-			Station:    0,
+			Time:            Time{true, 9, 48, 21, 0},
+			Latitude:        MustParseGPS("4849.931307 N"),
+			Longitude:       MustParseGPS("00216.053323 E"),
+			Mode:            []string{"A", "A", "N"},
+			SVs:             14,
+			HDOP:            0.6,
+			Altitude:        161.5,
+			AltitudeValid:   true,
+			Separation:      48.0,
+			SeparationValid: true,
+			Age:             0,
+			Station:         0,
 		},
 	},
 	{
@@ -245,42 +254,48 @@ var gnrmctests = []struct {
 		name: "good sentence A",
 		raw:  "$GNRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*6E",
 		msg: GNRMC{
-			Time:      Time{true, 22, 05, 16, 0},
-			Validity:  "A",
-			Speed:     173.8,
-			Course:    231.8,
-			Date:      Date{true, 13, 06, 94},
-			Variation: -4.2,
-			Latitude:  MustParseGPS("5133.82 N"),
-			Longitude: MustParseGPS("00042.24 W"),
+			Time:           Time{true, 22, 05, 16, 0},
+			Validity:       "A",
+			Speed:          173.8,
+			Course:         231.8,
+			Date:           Date{true, 13, 06, 94},
+			Variation:      -4.2,
+			Latitude:       MustParseGPS("5133.82 N"),
+			Longitude:      MustParseGPS("00042.24 W"),
+			SpeedValid:     true,
+			CourseValid:    true,
+			VariationValid: true,
 		},
 	},
 	{
 		name: "good sentence B",
 		raw:  "$GNRMC,142754.0,A,4302.539570,N,07920.379823,W,0.0,,070617,0.0,E,A*21",
 		msg: GNRMC{
-			Time:      Time{true, 14, 27, 54, 0},
-			Validity:  "A",
-			Speed:     0,
-			Course:    0,
-			Date:      Date{true, 7, 6, 17},
-			Variation: 0,
-			Latitude:  MustParseGPS("4302.539570 N"),
-			Longitude: MustParseGPS("07920.379823 W"),
+			Time:           Time{true, 14, 27, 54, 0},
+			Validity:       "A",
+			Speed:          0,
+			Course:         0,
+			Date:           Date{true, 7, 6, 17},
+			Variation:      0,
+			Latitude:       MustParseGPS("4302.539570 N"),
+			Longitude:      MustParseGPS("07920.379823 W"),
+			SpeedValid:     true,
+			VariationValid: true,
 		},
 	},
 	{
 		name: "good sentence C",
 		raw:  "$GNRMC,100538.00,A,5546.27711,N,03736.91144,E,0.061,,260318,,,A*60",
 		msg: GNRMC{
-			Time:      Time{true, 10, 5, 38, 0},
-			Validity:  "A",
-			Speed:     0.061,
-			Course:    0,
-			Date:      Date{true, 26, 3, 18},
-			Variation: 0,
-			Latitude:  MustParseGPS("5546.27711 N"),
-			Longitude: MustParseGPS("03736.91144 E"),
+			Time:       Time{true, 10, 5, 38, 0},
+			Validity:   "A",
+			Speed:      0.061,
+			Course:     0,
+			Date:       Date{true, 26, 3, 18},
+			Variation:  0,
+			Latitude:   MustParseGPS("5546.27711 N"),
+			Longitude:  MustParseGPS("03736.91144 E"),
+			SpeedValid: true,
 		},
 	},
 	{
@@ -317,16 +332,19 @@ var gpggatests = []struct {
 		name: "good sentence",
 		raw:  "$GPGGA,034225.077,3356.4650,S,15124.5567,E,1,03,9.7,-25.0,M,21.0,M,,0000*51",
 		msg: GPGGA{
-			Time:          Time{true, 3, 42, 25, 77},
-			Latitude:      MustParseLatLong("3356.4650 S"),
-			Longitude:     MustParseLatLong("15124.5567 E"),
-			FixQuality:    GPS,
-			NumSatellites: 03,
-			HDOP:          9.7,
-			Altitude:      -25.0,
-			Separation:    21.0,
-			DGPSAge:       "",
-			DGPSId:        "0000",
+			Time:            Time{true, 3, 42, 25, 77},
+			Latitude:        MustParseLatLong("3356.4650 S"),
+			Longitude:       MustParseLatLong("15124.5567 E"),
+			FixQuality:      GPS,
+			NumSatellites:   03,
+			HDOP:            9.7,
+			Altitude:        -25.0,
+			Separation:      21.0,
+			DGPSAge:         "",
+			DGPSId:          "0000",
+			HDOPValid:       true,
+			AltitudeValid:   true,
+			SeparationValid: true,
 		},
 	},
 	{
@@ -470,10 +488,10 @@ var gpgsvtests = []struct {
 			MessageNumber:   1,
 			NumberSVsInView: 11,
 			Info: []GPGSVInfo{
-				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0},
-				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0},
-				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12},
-				{SVPRNNumber: 13, Elevation: 6, Azimuth: 292, SNR: 0},
+				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12, SNRValid: true},
+				{SVPRNNumber: 13, Elevation: 6, Azimuth: 292, SNR: 0, SNRValid: true},
 			},
 		},
 	},
@@ -485,9 +503,9 @@ var gpgsvtests = []struct {
 			MessageNumber:   1,
 			NumberSVsInView: 11,
 			Info: []GPGSVInfo{
-				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0},
-				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0},
-				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12},
+				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12, SNRValid: true},
 			},
 		},
 	},
@@ -598,28 +616,33 @@ var gprmctests = []struct {
 		name: "good sentence A",
 		raw:  "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70",
 		msg: GPRMC{
-			Time:      Time{true, 22, 5, 16, 0},
-			Validity:  "A",
-			Speed:     173.8,
-			Course:    231.8,
-			Date:      Date{true, 13, 6, 94},
-			Variation: -4.2,
-			Latitude:  MustParseGPS("5133.82 N"),
-			Longitude: MustParseGPS("00042.24 W"),
+			Time:           Time{true, 22, 5, 16, 0},
+			Validity:       "A",
+			Speed:          173.8,
+			Course:         231.8,
+			Date:           Date{true, 13, 6, 94},
+			Variation:      -4.2,
+			Latitude:       MustParseGPS("5133.82 N"),
+			Longitude:      MustParseGPS("00042.24 W"),
+			SpeedValid:     true,
+			CourseValid:    true,
+			VariationValid: true,
 		},
 	},
 	{
 		name: "good sentence B",
 		raw:  "$GPRMC,142754.0,A,4302.539570,N,07920.379823,W,0.0,,070617,0.0,E,A*3F",
 		msg: GPRMC{
-			Time:      Time{true, 14, 27, 54, 0},
-			Validity:  "A",
-			Speed:     0,
-			Course:    0,
-			Date:      Date{true, 7, 6, 17},
-			Variation: 0,
-			Latitude:  MustParseGPS("4302.539570 N"),
-			Longitude: MustParseGPS("07920.379823 W"),
+			Time:           Time{true, 14, 27, 54, 0},
+			Validity:       "A",
+			Speed:          0,
+			Course:         0,
+			Date:           Date{true, 7, 6, 17},
+			Variation:      0,
+			Latitude:       MustParseGPS("4302.539570 N"),
+			Longitude:      MustParseGPS("07920.379823 W"),
+			SpeedValid:     true,
+			VariationValid: true,
 		},
 	},
 	{
@@ -656,10 +679,14 @@ var gpvtgtests = []struct {
 		name: "good sentence",
 		raw:  "$GPVTG,45.5,T,67.5,M,30.45,N,56.40,K*4B",
 		msg: GPVTG{
-			TrueTrack:        45.5,
-			MagneticTrack:    67.5,
-			GroundSpeedKnots: 30.45,
-			GroundSpeedKPH:   56.4,
+			TrueTrack:             45.5,
+			TrueTrackValid:        true,
+			MagneticTrack:         67.5,
+			MagneticTrackValid:    true,
+			GroundSpeedKnots:      30.45,
+			GroundSpeedKnotsValid: true,
+			GroundSpeedKPH:        56.4,
+			GroundSpeedKPHValid:   true,
 		},
 	},
 	{
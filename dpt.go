@@ -9,19 +9,32 @@ const (
 // https://gpsd.gitlab.io/gpsd/NMEA.html#_dpt_depth_of_water
 type DPT struct {
 	BaseSentence
-	Depth      float64
-	Offset     float64
-	RangeScale float64
+	Depth       float64
+	DepthValid  bool // Depth is present in the sentence
+	Offset      float64
+	OffsetValid bool // Offset is present in the sentence
+	RangeScale  float64
 }
 
 // newDPT constructor
 func newDPT(s BaseSentence) (DPT, error) {
 	p := NewParser(s)
 	p.AssertType(TypeDPT)
-	return DPT{
-		BaseSentence: s,
-		Depth:        p.Float64(0, "depth"),
-		Offset:       p.Float64(1, "offset"),
-		RangeScale:   p.Float64(2, "range scale"),
-	}, p.Err()
+	m := DPT{BaseSentence: s}
+	depth := p.NullFloat64(0, "depth")
+	m.Depth, m.DepthValid = depth.Value, depth.Valid
+	offset := p.NullFloat64(1, "offset")
+	m.Offset, m.OffsetValid = offset.Value, offset.Valid
+	m.RangeScale = p.Float64(2, "range scale")
+	return m, p.Err()
+}
+
+// MarshalJSON implements json.Marshaler
+func (s DPT) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *DPT) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
 }
@@ -16,20 +16,29 @@ var dpttests = []struct {
 		name: "good sentence",
 		raw:  "$SDDPT,0.5,0.5,*7B",
 		msg: DPT{
-			Depth:      MustParseDecimal("0.5"),
-			Offset:     MustParseDecimal("0.5"),
-			RangeScale: MustParseDecimal("0"),
+			Depth:       MustParseDecimal("0.5"),
+			DepthValid:  true,
+			Offset:      MustParseDecimal("0.5"),
+			RangeScale:  MustParseDecimal("0"),
+			OffsetValid: true,
 		},
 	},
 	{
 		name: "good sentence with scale",
 		raw:  "$SDDPT,0.5,0.5,0.1*54",
 		msg: DPT{
-			Depth:      MustParseDecimal("0.5"),
-			Offset:     MustParseDecimal("0.5"),
-			RangeScale: MustParseDecimal("0.1"),
+			Depth:       MustParseDecimal("0.5"),
+			DepthValid:  true,
+			Offset:      MustParseDecimal("0.5"),
+			RangeScale:  MustParseDecimal("0.1"),
+			OffsetValid: true,
 		},
 	},
+	{
+		name: "good sentence without depth",
+		raw:  "$SDDPT,,,*7B",
+		msg:  DPT{},
+	},
 	{
 		name: "bad validity",
 		raw:  "$SDDPT,0.5,0.5,*AA",
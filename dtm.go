@@ -0,0 +1,62 @@
+package nmea
+
+const (
+	// TypeDTM type for DTM sentences
+	TypeDTM = "DTM"
+
+	// WGS84Datum WGS 84 datum code
+	WGS84Datum = "W84"
+	// WGS72Datum WGS 72 datum code
+	WGS72Datum = "W72"
+	// SGS85Datum SGS 85 datum code
+	SGS85Datum = "S85"
+	// PE90Datum PE 90 datum code
+	PE90Datum = "P90"
+	// UserDefinedDatum user defined datum code
+	UserDefinedDatum = "999"
+)
+
+// DTM is the datum reference. Positions reported by the talker are in the
+// local datum, which is offset from the reference datum.
+// https://gpsd.gitlab.io/gpsd/NMEA.html#_dtm_datum_reference
+type DTM struct {
+	BaseSentence
+	LocalDatumCode        string  // Local datum code, W84, W72, S85, P90, 999 or IHO datum code
+	LocalDatumSubcode     string  // Local datum subdivision code
+	LatitudeOffsetMinute  float64 // Latitude offset of the local datum, in minutes
+	LongitudeOffsetMinute float64 // Longitude offset of the local datum, in minutes
+	AltitudeOffsetMeters  float64 // Altitude offset of the local datum, in meters
+	DatumName             string  // Reference datum code
+}
+
+// newDTM constructor
+func newDTM(s BaseSentence) (DTM, error) {
+	p := NewParser(s)
+	p.AssertType(TypeDTM)
+	m := DTM{
+		BaseSentence:          s,
+		LocalDatumCode:        p.String(0, "local datum code"),
+		LocalDatumSubcode:     p.String(1, "local datum subcode"),
+		LatitudeOffsetMinute:  p.Float64(2, "latitude offset minute"),
+		LongitudeOffsetMinute: p.Float64(4, "longitude offset minute"),
+		AltitudeOffsetMeters:  p.Float64(6, "altitude offset meters"),
+		DatumName:             p.String(7, "datum name"),
+	}
+	if p.EnumString(3, "latitude offset direction", North, South) == South {
+		m.LatitudeOffsetMinute = 0 - m.LatitudeOffsetMinute
+	}
+	if p.EnumString(5, "longitude offset direction", East, West) == West {
+		m.LongitudeOffsetMinute = 0 - m.LongitudeOffsetMinute
+	}
+	return m, p.Err()
+}
+
+// MarshalJSON implements json.Marshaler
+func (s DTM) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *DTM) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
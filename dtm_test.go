@@ -0,0 +1,62 @@
+package nmea
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var dtmtests = []struct {
+	name string
+	raw  string
+	err  string
+	msg  DTM
+}{
+	{
+		name: "good sentence",
+		raw:  "$GPDTM,W84,,0.0,N,0.0,E,0.0,W84*6F",
+		msg: DTM{
+			LocalDatumCode: WGS84Datum,
+			DatumName:      WGS84Datum,
+		},
+	},
+	{
+		name: "good sentence with offsets",
+		raw:  "$GPDTM,999,CH,0.08,N,0.07,W,-47.7,W84*02",
+		msg: DTM{
+			LocalDatumCode:        UserDefinedDatum,
+			LocalDatumSubcode:     "CH",
+			LatitudeOffsetMinute:  0.08,
+			LongitudeOffsetMinute: -0.07,
+			AltitudeOffsetMeters:  -47.7,
+			DatumName:             WGS84Datum,
+		},
+	},
+	{
+		name: "invalid latitude offset direction",
+		raw:  "$GPDTM,W84,,0.0,X,0.0,E,0.0,W84*79",
+		err:  "nmea: GPDTM invalid latitude offset direction: X",
+	},
+	{
+		name: "invalid latitude offset",
+		raw:  "$GPDTM,W84,,A,N,0.0,E,0.0,W84*00",
+		err:  "nmea: GPDTM invalid latitude offset minute: A",
+	},
+}
+
+func TestDTM(t *testing.T) {
+	for _, tt := range dtmtests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(tt.raw)
+			if tt.err != "" {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				dtm := m.(DTM)
+				dtm.BaseSentence = BaseSentence{}
+				assert.Equal(t, tt.msg, dtm)
+			}
+		})
+	}
+}
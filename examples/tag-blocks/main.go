@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"log"
-	"time"
 
 	"github.com/storskegg/go-nmea"
 )
@@ -15,6 +14,6 @@ func main() {
 		log.Fatal(err)
 	}
 	parsed := s.(nmea.VDMVDO)
-	fmt.Printf("TAG Block timestamp: %v\n", time.Unix(parsed.TagBlock.Time, 0))
+	fmt.Printf("TAG Block timestamp: %v\n", parsed.TagBlock.Timestamp())
 	fmt.Printf("TAG Block source:    %v\n", parsed.TagBlock.Source)
 }
@@ -0,0 +1,274 @@
+package geo
+
+import (
+	"math"
+	"strings"
+	"sync"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// Ellipsoid is a reference ellipsoid.
+type Ellipsoid struct {
+	A float64 // Semi-major axis in meters
+	F float64 // Flattening
+}
+
+// Reference ellipsoids
+var (
+	WGS84Ellipsoid              = Ellipsoid{wgs84A, wgs84F}
+	GRS80Ellipsoid              = Ellipsoid{6378137, 1 / 298.257222101}
+	WGS72Ellipsoid              = Ellipsoid{6378135, 1 / 298.26}
+	Airy1830Ellipsoid           = Ellipsoid{6377563.396, 1 / 299.3249646}
+	ModifiedAiryEllipsoid       = Ellipsoid{6377340.189, 1 / 299.3249646}
+	Bessel1841Ellipsoid         = Ellipsoid{6377397.155, 1 / 299.1528128}
+	Clarke1866Ellipsoid         = Ellipsoid{6378206.4, 1 / 294.978698214}
+	International1924Ellipsoid  = Ellipsoid{6378388, 1 / 297}
+	Krassovsky1940Ellipsoid     = Ellipsoid{6378245, 1 / 298.3}
+	AustralianNationalEllipsoid = Ellipsoid{6378160, 1 / 298.25}
+)
+
+func (e Ellipsoid) b() float64 { return e.A * (1 - e.F) }
+
+func (e Ellipsoid) e2() float64 { return 2*e.F - e.F*e.F }
+
+// Helmert holds the parameters of a 7-parameter Helmert transformation
+// using the position vector convention.
+type Helmert struct {
+	Tx, Ty, Tz float64 // Translations in meters
+	Rx, Ry, Rz float64 // Rotations in arc seconds
+	S          float64 // Scale in parts per million
+}
+
+// Datum is a geodetic datum along with the transformation to WGS84.
+type Datum struct {
+	Name      string    // Datum name
+	Codes     []string  // DTM (IHO S-60) codes and receiver names of the datum
+	Ellipsoid Ellipsoid // Reference ellipsoid
+	ToWGS84   Helmert   // Transformation from the datum to WGS84
+}
+
+// Common datums. The transformations are approximate for the whole area
+// covered by a datum and accurate to a few meters.
+var (
+	WGS84 = Datum{"WGS 84", []string{nmea.WGS84Datum, "WGE", "WGS84"}, WGS84Ellipsoid, Helmert{}}
+	WGS72 = Datum{"WGS 72", []string{nmea.WGS72Datum, "WGC", "WGS72"}, WGS72Ellipsoid,
+		Helmert{Tz: 4.5, Rz: 0.554, S: 0.2263}}
+	ETRS89 = Datum{"ETRS89", []string{"EUREF", "ETRS 89"}, GRS80Ellipsoid, Helmert{}}
+	NAD83  = Datum{"NAD83", []string{"NAR", "NAD 83", "NAD83 CONUS"}, GRS80Ellipsoid, Helmert{}}
+	ED50   = Datum{"ED50", []string{"EUR", "ED 50", "European 1950"}, International1924Ellipsoid,
+		Helmert{Tx: -89.5, Ty: -93.8, Tz: -123.1, Rz: -0.156, S: 1.2}}
+	NAD27 = Datum{"NAD27", []string{"NAS", "NAD 27", "NAD27 CONUS", "NAD27 Canada"}, Clarke1866Ellipsoid,
+		Helmert{Tx: -8, Ty: 160, Tz: 176}}
+	OSGB36 = Datum{"OSGB36", []string{"OGB", "Ord Srvy GB", "Ordnance Survey GB"}, Airy1830Ellipsoid,
+		Helmert{Tx: 446.448, Ty: -125.157, Tz: 542.060, Rx: 0.1502, Ry: 0.2470, Rz: 0.8421, S: -20.4894}}
+	Irl1975 = Datum{"Ireland 1975", []string{"IRL", "Ireland 1965"}, ModifiedAiryEllipsoid,
+		Helmert{Tx: 482.530, Ty: -130.596, Tz: 564.557, Rx: -1.042, Ry: -0.214, Rz: -0.631, S: -8.150}}
+	Tokyo = Datum{"Tokyo", []string{"TOY", "Tokyo Japan"}, Bessel1841Ellipsoid,
+		Helmert{Tx: -148, Ty: 507, Tz: 685}}
+	Pulkovo1942 = Datum{"Pulkovo 1942", []string{"SPK", "Pulkovo 1942"}, Krassovsky1940Ellipsoid,
+		Helmert{Tx: 28, Ty: -130, Tz: -95}}
+	AGD66 = Datum{"AGD66", []string{"AUA", "Australian Geod 66"}, AustralianNationalEllipsoid,
+		Helmert{Tx: -133, Ty: -48, Tz: 148}}
+	SAD69 = Datum{"SAD69", []string{"SAN", "S American 1969"}, AustralianNationalEllipsoid,
+		Helmert{Tx: -57, Ty: 1, Tz: -41}}
+)
+
+var (
+	datumsMu = &sync.Mutex{}
+	datums   = []Datum{WGS84, WGS72, ETRS89, NAD83, ED50, NAD27, OSGB36, Irl1975, Tokyo, Pulkovo1942, AGD66, SAD69}
+)
+
+// RegisterDatum adds a datum to the table used by LookupDatum.
+func RegisterDatum(d Datum) {
+	datumsMu.Lock()
+	defer datumsMu.Unlock()
+
+	datums = append(datums, d)
+}
+
+// LookupDatum finds a datum by its name or one of its codes. The
+// comparison is case insensitive.
+func LookupDatum(name string) (Datum, bool) {
+	datumsMu.Lock()
+	defer datumsMu.Unlock()
+
+	name = strings.TrimSpace(name)
+	for _, d := range datums {
+		if strings.EqualFold(d.Name, name) {
+			return d, true
+		}
+		for _, code := range d.Codes {
+			if strings.EqualFold(code, name) {
+				return d, true
+			}
+		}
+	}
+	return Datum{}, false
+}
+
+// IsWGS84 reports whether positions in the datum need no transformation.
+func (d Datum) IsWGS84() bool {
+	return d.ToWGS84 == (Helmert{})
+}
+
+// ToWGS84Helmert transforms a position and height in the datum into WGS84
+// using the 7-parameter Helmert transformation.
+func (d Datum) ToWGS84Helmert(p LatLon, height float64) (LatLon, float64) {
+	x, y, z := toCartesian(p, height, d.Ellipsoid)
+	x, y, z = d.ToWGS84.apply(x, y, z)
+	return fromCartesian(x, y, z, WGS84Ellipsoid)
+}
+
+// ToWGS84Molodensky transforms a position and height in the datum into
+// WGS84 using the standard Molodensky transformation, which only uses the
+// translations of the Helmert parameters.
+func (d Datum) ToWGS84Molodensky(p LatLon, height float64) (LatLon, float64) {
+	return molodensky(p, height, d.Ellipsoid, WGS84Ellipsoid, d.ToWGS84.Tx, d.ToWGS84.Ty, d.ToWGS84.Tz)
+}
+
+// Inverse returns the parameters of the opposite transformation.
+func (h Helmert) Inverse() Helmert {
+	return Helmert{-h.Tx, -h.Ty, -h.Tz, -h.Rx, -h.Ry, -h.Rz, -h.S}
+}
+
+func (h Helmert) apply(x, y, z float64) (float64, float64, float64) {
+	const arcsec = math.Pi / (180 * 3600)
+	rx, ry, rz := h.Rx*arcsec, h.Ry*arcsec, h.Rz*arcsec
+	s := 1 + h.S/1e6
+	return h.Tx + x*s - y*rz + z*ry,
+		h.Ty + x*rz + y*s - z*rx,
+		h.Tz - x*ry + y*rx + z*s
+}
+
+func toCartesian(p LatLon, height float64, e Ellipsoid) (float64, float64, float64) {
+	phi, lambda := radians(p.Lat), radians(p.Lon)
+	e2 := e.e2()
+	nu := e.A / math.Sqrt(1-e2*math.Sin(phi)*math.Sin(phi))
+	return (nu + height) * math.Cos(phi) * math.Cos(lambda),
+		(nu + height) * math.Cos(phi) * math.Sin(lambda),
+		(nu*(1-e2) + height) * math.Sin(phi)
+}
+
+// fromCartesian uses Bowring's method.
+func fromCartesian(x, y, z float64, e Ellipsoid) (LatLon, float64) {
+	a, b, e2 := e.A, e.b(), e.e2()
+	eps2 := e2 / (1 - e2)
+	p := math.Sqrt(x*x + y*y)
+	r := math.Sqrt(p*p + z*z)
+	beta := math.Atan2(b*z*(1+eps2*b/r), a*p)
+	sinBeta, cosBeta := math.Sin(beta), math.Cos(beta)
+	phi := math.Atan2(z+eps2*b*sinBeta*sinBeta*sinBeta, p-e2*a*cosBeta*cosBeta*cosBeta)
+	lambda := math.Atan2(y, x)
+	sinPhi := math.Sin(phi)
+	nu := a / math.Sqrt(1-e2*sinPhi*sinPhi)
+	height := p*math.Cos(phi) + z*sinPhi - a*a/nu
+	return LatLon{degrees(phi), degrees(lambda)}, height
+}
+
+func molodensky(p LatLon, height float64, from, to Ellipsoid, dx, dy, dz float64) (LatLon, float64) {
+	phi, lambda := radians(p.Lat), radians(p.Lon)
+	sinPhi, cosPhi := math.Sin(phi), math.Cos(phi)
+	sinLambda, cosLambda := math.Sin(lambda), math.Cos(lambda)
+	a, b, e2 := from.A, from.b(), from.e2()
+	da, df := to.A-from.A, to.F-from.F
+
+	rn := a / math.Sqrt(1-e2*sinPhi*sinPhi)
+	rm := a * (1 - e2) / math.Pow(1-e2*sinPhi*sinPhi, 1.5)
+
+	dphi := (-dx*sinPhi*cosLambda - dy*sinPhi*sinLambda + dz*cosPhi +
+		da*rn*e2*sinPhi*cosPhi/a +
+		df*(rm*a/b+rn*b/a)*sinPhi*cosPhi) / (rm + height)
+	dlambda := (-dx*sinLambda + dy*cosLambda) / ((rn + height) * cosPhi)
+	dh := dx*cosPhi*cosLambda + dy*cosPhi*sinLambda + dz*sinPhi -
+		da*a/rn + df*b/a*rn*sinPhi*sinPhi
+
+	return LatLon{degrees(phi + dphi), degrees(lambda + dlambda)}, height + dh
+}
+
+// Normalizer converts positions to WGS84 according to the datum announced
+// on the stream by DTM or PGRMM sentences. Until a datum is announced,
+// positions are assumed to be WGS84.
+type Normalizer struct {
+	Molodensky bool // Use the Molodensky transformation instead of Helmert
+
+	mu     sync.Mutex
+	datum  Datum
+	offset LatLon // Offsets announced by DTM, in degrees
+	known  bool
+}
+
+// NewNormalizer constructor
+func NewNormalizer() *Normalizer {
+	return &Normalizer{datum: WGS84, known: true}
+}
+
+// Update observes a sentence, DTM and PGRMM sentences change the datum.
+func (n *Normalizer) Update(s nmea.Sentence) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	switch m := s.(type) {
+	case nmea.DTM:
+		n.offset = LatLon{}
+		if m.DatumName != "" && m.DatumName != nmea.WGS84Datum {
+			n.datum, n.known = Datum{Name: m.LocalDatumCode}, false
+			return
+		}
+		// Offsets announced by the receiver take precedence over the table.
+		if m.LatitudeOffsetMinute != 0 || m.LongitudeOffsetMinute != 0 {
+			n.datum, n.known = Datum{Name: m.LocalDatumCode, Ellipsoid: WGS84Ellipsoid}, true
+			n.offset = LatLon{m.LatitudeOffsetMinute / 60, m.LongitudeOffsetMinute / 60}
+			return
+		}
+		n.datum, n.known = LookupDatum(m.LocalDatumCode)
+		if !n.known {
+			n.datum.Name = m.LocalDatumCode
+		}
+	case nmea.PGRMM:
+		n.offset = LatLon{}
+		n.datum, n.known = LookupDatum(m.Datum)
+		if !n.known {
+			n.datum.Name = m.Datum
+		}
+	}
+}
+
+// Datum returns the current datum, and false if it is not in the table.
+func (n *Normalizer) Datum() (Datum, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.datum, n.known
+}
+
+// Normalize converts a position in the current datum to WGS84. It returns
+// false if the datum is unknown, in which case the position is unchanged.
+func (n *Normalizer) Normalize(p LatLon) (LatLon, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	switch {
+	case !n.known:
+		return p, false
+	case n.offset != (LatLon{}):
+		return LatLon{p.Lat - n.offset.Lat, normalizeLon(p.Lon - n.offset.Lon)}, true
+	case n.datum.IsWGS84():
+		return p, true
+	case n.Molodensky:
+		p, _ = n.datum.ToWGS84Molodensky(p, 0)
+		return p, true
+	default:
+		p, _ = n.datum.ToWGS84Helmert(p, 0)
+		return p, true
+	}
+}
+
+// Position returns the WGS84 position of a sentence carrying one.
+func (n *Normalizer) Position(s nmea.Sentence) (LatLon, bool) {
+	p, ok := FromSentence(s)
+	if !ok {
+		return LatLon{}, false
+	}
+	return n.Normalize(p)
+}
@@ -0,0 +1,115 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// Caister water tower, from the Ordnance Survey guide to coordinate systems.
+var (
+	caisterOSGB36 = LatLon{52.6575703, 1.7179215}
+	caisterWGS84  = LatLon{52.6580078, 1.7160740}
+)
+
+func TestLookupDatum(t *testing.T) {
+	for _, name := range []string{"OGB", "osgb36", "Ord Srvy GB"} {
+		d, ok := LookupDatum(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, "OSGB36", d.Name)
+	}
+	_, ok := LookupDatum("XYZ")
+	assert.False(t, ok)
+
+	RegisterDatum(Datum{Name: "Test", Codes: []string{"TST"}, Ellipsoid: WGS84Ellipsoid})
+	d, ok := LookupDatum("tst")
+	assert.True(t, ok)
+	assert.Equal(t, "Test", d.Name)
+}
+
+func TestHelmert(t *testing.T) {
+	p, _ := OSGB36.ToWGS84Helmert(caisterOSGB36, 0)
+	assert.InDelta(t, caisterWGS84.Lat, p.Lat, 5e-5)
+	assert.InDelta(t, caisterWGS84.Lon, p.Lon, 5e-5)
+
+	// round trip through cartesian coordinates without a transformation
+	p, h := WGS84.ToWGS84Helmert(caisterWGS84, 100)
+	assert.InDelta(t, caisterWGS84.Lat, p.Lat, 1e-9)
+	assert.InDelta(t, caisterWGS84.Lon, p.Lon, 1e-9)
+	assert.InDelta(t, 100, h, 1e-3)
+}
+
+func TestMolodensky(t *testing.T) {
+	tests := []struct {
+		datum Datum
+		p     LatLon
+	}{
+		{ED50, LatLon{40, 10}},
+		{NAD27, LatLon{40, -75}},
+		{Tokyo, LatLon{35, 139}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.datum.Name, func(t *testing.T) {
+			helmert, _ := tt.datum.ToWGS84Helmert(tt.p, 0)
+			molodensky, _ := tt.datum.ToWGS84Molodensky(tt.p, 0)
+			assert.InDelta(t, 0, Distance(helmert, molodensky), 25)
+			assert.True(t, Distance(tt.p, molodensky) > 20)
+		})
+	}
+}
+
+func TestNormalizer(t *testing.T) {
+	n := NewNormalizer()
+	gll, err := nmea.Parse("$GPGLL,5239.4542,N,00143.0753,E,123519,A*2A")
+	assert.NoError(t, err)
+
+	// WGS84 until a datum is announced
+	p, ok := n.Position(gll)
+	assert.True(t, ok)
+	assert.InDelta(t, caisterOSGB36.Lat, p.Lat, 1e-6)
+
+	s, err := nmea.Parse("$PGRMM,Ord Srvy GB*1B")
+	assert.NoError(t, err)
+	n.Update(s)
+	d, ok := n.Datum()
+	assert.True(t, ok)
+	assert.Equal(t, "OSGB36", d.Name)
+	p, ok = n.Position(gll)
+	assert.True(t, ok)
+	assert.InDelta(t, caisterWGS84.Lat, p.Lat, 5e-5)
+	assert.InDelta(t, caisterWGS84.Lon, p.Lon, 5e-5)
+
+	n.Molodensky = true
+	p, ok = n.Position(gll)
+	assert.True(t, ok)
+	assert.InDelta(t, 0, Distance(caisterWGS84, p), 20)
+	n.Molodensky = false
+
+	// offsets announced by DTM are subtracted
+	s, err = nmea.Parse("$GPDTM,999,,0.08,N,0.07,E,-47.7,W84*1B")
+	assert.NoError(t, err)
+	n.Update(s)
+	p, ok = n.Normalize(LatLon{10, 20})
+	assert.True(t, ok)
+	assert.InDelta(t, 10-0.08/60, p.Lat, 1e-9)
+	assert.InDelta(t, 20-0.07/60, p.Lon, 1e-9)
+
+	// DTM without offsets uses the table
+	s, err = nmea.Parse("$GPDTM,W84,,0.0,N,0.0,E,0.0,W84*6F")
+	assert.NoError(t, err)
+	n.Update(s)
+	p, ok = n.Normalize(LatLon{10, 20})
+	assert.True(t, ok)
+	assert.Equal(t, LatLon{10, 20}, p)
+
+	// unknown datum
+	s, err = nmea.Parse("$PGRMM,Bogus*25")
+	assert.NoError(t, err)
+	n.Update(s)
+	_, ok = n.Normalize(LatLon{10, 20})
+	assert.False(t, ok)
+	_, ok = n.Position(s)
+	assert.False(t, ok)
+}
@@ -0,0 +1,231 @@
+// Package geo provides geodesy helpers on positions parsed from sentences:
+// distances, bearings and destination points on the sphere and on the
+// WGS84 ellipsoid, rhumb lines and cross track distances.
+//
+// Latitudes and longitudes are in decimal degrees, distances in meters
+// and bearings in degrees from true north.
+package geo
+
+import (
+	"errors"
+	"math"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+const (
+	// EarthRadius is the mean radius of the earth in meters.
+	EarthRadius = 6371008.8
+
+	// NauticalMile is the length of a nautical mile in meters.
+	NauticalMile = 1852.0
+
+	// WGS84 ellipsoid parameters
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+	wgs84B = wgs84A * (1 - wgs84F)
+)
+
+// ErrNoConvergence is returned when the Vincenty formula fails to converge,
+// which happens for nearly antipodal points.
+var ErrNoConvergence = errors.New("geo: vincenty formula failed to converge")
+
+// LatLon is a position in decimal degrees.
+type LatLon struct {
+	Lat float64 // Latitude, positive north
+	Lon float64 // Longitude, positive east
+}
+
+// FromSentence returns the position of sentences carrying one: RMC, GGA,
+// GLL, GNS and WPL.
+func FromSentence(s nmea.Sentence) (LatLon, bool) {
+	switch m := s.(type) {
+	case nmea.RMC:
+		return LatLon{m.Latitude, m.Longitude}, true
+	case nmea.GGA:
+		return LatLon{m.Latitude, m.Longitude}, true
+	case nmea.GLL:
+		return LatLon{m.Latitude, m.Longitude}, true
+	case nmea.GNS:
+		return LatLon{m.Latitude, m.Longitude}, true
+	case nmea.WPL:
+		return LatLon{m.Latitude, m.Longitude}, true
+	}
+	return LatLon{}, false
+}
+
+func radians(d float64) float64 { return d * math.Pi / 180 }
+
+func degrees(r float64) float64 { return r * 180 / math.Pi }
+
+// normalizeBearing wraps a bearing into [0, 360).
+func normalizeBearing(b float64) float64 {
+	b = math.Mod(b, 360)
+	if b < 0 {
+		b += 360
+	}
+	return b
+}
+
+// normalizeLon wraps a longitude into [-180, 180).
+func normalizeLon(l float64) float64 {
+	return math.Mod(l+540, 360) - 180
+}
+
+// Distance returns the great circle distance between two points using the
+// haversine formula.
+func Distance(a, b LatLon) float64 {
+	phi1, phi2 := radians(a.Lat), radians(b.Lat)
+	dphi := phi2 - phi1
+	dlambda := radians(b.Lon - a.Lon)
+	h := math.Sin(dphi/2)*math.Sin(dphi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dlambda/2)*math.Sin(dlambda/2)
+	return 2 * EarthRadius * math.Atan2(math.Sqrt(h), math.Sqrt(1-h))
+}
+
+// InitialBearing returns the great circle bearing when leaving a towards b.
+func InitialBearing(a, b LatLon) float64 {
+	phi1, phi2 := radians(a.Lat), radians(b.Lat)
+	dlambda := radians(b.Lon - a.Lon)
+	y := math.Sin(dlambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dlambda)
+	return normalizeBearing(degrees(math.Atan2(y, x)))
+}
+
+// FinalBearing returns the great circle bearing when arriving at b from a.
+func FinalBearing(a, b LatLon) float64 {
+	return normalizeBearing(InitialBearing(b, a) + 180)
+}
+
+// Destination returns the point reached travelling the distance along a
+// great circle with the given initial bearing.
+func Destination(a LatLon, bearing, distance float64) LatLon {
+	delta := distance / EarthRadius
+	theta := radians(bearing)
+	phi1, lambda1 := radians(a.Lat), radians(a.Lon)
+	phi2 := math.Asin(math.Sin(phi1)*math.Cos(delta) + math.Cos(phi1)*math.Sin(delta)*math.Cos(theta))
+	lambda2 := lambda1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi1), math.Cos(delta)-math.Sin(phi1)*math.Sin(phi2))
+	return LatLon{degrees(phi2), normalizeLon(degrees(lambda2))}
+}
+
+// CrossTrackDistance returns the distance of p from the great circle path
+// going from start to end. It is positive when p is right of the path.
+func CrossTrackDistance(p, start, end LatLon) float64 {
+	delta13 := Distance(start, p) / EarthRadius
+	theta13 := radians(InitialBearing(start, p))
+	theta12 := radians(InitialBearing(start, end))
+	return math.Asin(math.Sin(delta13)*math.Sin(theta13-theta12)) * EarthRadius
+}
+
+// AlongTrackDistance returns the distance from start to the point of the
+// great circle path from start to end closest to p.
+func AlongTrackDistance(p, start, end LatLon) float64 {
+	delta13 := Distance(start, p) / EarthRadius
+	theta13 := radians(InitialBearing(start, p))
+	theta12 := radians(InitialBearing(start, end))
+	deltaxt := math.Asin(math.Sin(delta13) * math.Sin(theta13-theta12))
+	deltaat := math.Acos(math.Cos(delta13) / math.Abs(math.Cos(deltaxt)))
+	return math.Copysign(deltaat, math.Cos(theta12-theta13)) * EarthRadius
+}
+
+// RhumbDistance returns the distance between two points along a rhumb line
+// (line of constant bearing).
+func RhumbDistance(a, b LatLon) float64 {
+	phi1, phi2 := radians(a.Lat), radians(b.Lat)
+	dphi := phi2 - phi1
+	dlambda := radians(normalizeLon(b.Lon - a.Lon))
+	dpsi := math.Log(math.Tan(math.Pi/4+phi2/2) / math.Tan(math.Pi/4+phi1/2))
+	q := math.Cos(phi1)
+	if math.Abs(dpsi) > 1e-12 {
+		q = dphi / dpsi
+	}
+	return math.Sqrt(dphi*dphi+q*q*dlambda*dlambda) * EarthRadius
+}
+
+// RhumbBearing returns the constant bearing of the rhumb line from a to b.
+func RhumbBearing(a, b LatLon) float64 {
+	phi1, phi2 := radians(a.Lat), radians(b.Lat)
+	dlambda := radians(normalizeLon(b.Lon - a.Lon))
+	dpsi := math.Log(math.Tan(math.Pi/4+phi2/2) / math.Tan(math.Pi/4+phi1/2))
+	return normalizeBearing(degrees(math.Atan2(dlambda, dpsi)))
+}
+
+// RhumbDestination returns the point reached travelling the distance along
+// a rhumb line with the given bearing.
+func RhumbDestination(a LatLon, bearing, distance float64) LatLon {
+	delta := distance / EarthRadius
+	theta := radians(bearing)
+	phi1, lambda1 := radians(a.Lat), radians(a.Lon)
+	dphi := delta * math.Cos(theta)
+	phi2 := phi1 + dphi
+	// going past a pole
+	if math.Abs(phi2) > math.Pi/2 {
+		if phi2 > 0 {
+			phi2 = math.Pi - phi2
+		} else {
+			phi2 = -math.Pi - phi2
+		}
+	}
+	dpsi := math.Log(math.Tan(phi2/2+math.Pi/4) / math.Tan(phi1/2+math.Pi/4))
+	q := math.Cos(phi1)
+	if math.Abs(dpsi) > 1e-12 {
+		q = dphi / dpsi
+	}
+	lambda2 := lambda1 + delta*math.Sin(theta)/q
+	return LatLon{degrees(phi2), normalizeLon(degrees(lambda2))}
+}
+
+// VincentyDistance returns the distance between two points on the WGS84
+// ellipsoid along with the initial and final bearings, using Vincenty's
+// inverse formula.
+func VincentyDistance(a, b LatLon) (distance, initial, final float64, err error) {
+	L := radians(b.Lon - a.Lon)
+	U1 := math.Atan((1 - wgs84F) * math.Tan(radians(a.Lat)))
+	U2 := math.Atan((1 - wgs84F) * math.Tan(radians(b.Lat)))
+	sinU1, cosU1 := math.Sin(U1), math.Cos(U1)
+	sinU2, cosU2 := math.Sin(U2), math.Cos(U2)
+
+	var (
+		lambda                    = L
+		sinlambda, coslambda      float64
+		sinsigma, cossigma, sigma float64
+		cosSqalpha, cos2sigmaM    float64
+		converged                 bool
+	)
+	for i := 0; i < 200; i++ {
+		sinlambda, coslambda = math.Sin(lambda), math.Cos(lambda)
+		sinsigma = math.Sqrt((cosU2*sinlambda)*(cosU2*sinlambda) +
+			(cosU1*sinU2-sinU1*cosU2*coslambda)*(cosU1*sinU2-sinU1*cosU2*coslambda))
+		if sinsigma == 0 {
+			// coincident points
+			return 0, 0, 0, nil
+		}
+		cossigma = sinU1*sinU2 + cosU1*cosU2*coslambda
+		sigma = math.Atan2(sinsigma, cossigma)
+		sinalpha := cosU1 * cosU2 * sinlambda / sinsigma
+		cosSqalpha = 1 - sinalpha*sinalpha
+		cos2sigmaM = 0
+		if cosSqalpha != 0 {
+			// equatorial line
+			cos2sigmaM = cossigma - 2*sinU1*sinU2/cosSqalpha
+		}
+		C := wgs84F / 16 * cosSqalpha * (4 + wgs84F*(4-3*cosSqalpha))
+		prev := lambda
+		lambda = L + (1-C)*wgs84F*sinalpha*(sigma+C*sinsigma*(cos2sigmaM+C*cossigma*(-1+2*cos2sigmaM*cos2sigmaM)))
+		if math.Abs(lambda-prev) < 1e-12 {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return 0, 0, 0, ErrNoConvergence
+	}
+	uSq := cosSqalpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	dsigma := B * sinsigma * (cos2sigmaM + B/4*(cossigma*(-1+2*cos2sigmaM*cos2sigmaM)-
+		B/6*cos2sigmaM*(-3+4*sinsigma*sinsigma)*(-3+4*cos2sigmaM*cos2sigmaM)))
+	distance = wgs84B * A * (sigma - dsigma)
+	initial = normalizeBearing(degrees(math.Atan2(cosU2*sinlambda, cosU1*sinU2-sinU1*cosU2*coslambda)))
+	final = normalizeBearing(degrees(math.Atan2(cosU1*sinlambda, -sinU1*cosU2+cosU1*sinU2*coslambda)))
+	return distance, initial, final, nil
+}
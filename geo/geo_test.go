@@ -0,0 +1,88 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+var (
+	landsEnd    = LatLon{50.0664, -5.7147}
+	johnOGroats = LatLon{58.6439, -3.0700}
+)
+
+func TestDistance(t *testing.T) {
+	assert.InDelta(t, 968900, Distance(landsEnd, johnOGroats), 200)
+	assert.Equal(t, 0.0, Distance(landsEnd, landsEnd))
+}
+
+func TestBearings(t *testing.T) {
+	assert.InDelta(t, 9.1198, InitialBearing(landsEnd, johnOGroats), 0.01)
+	assert.InDelta(t, 11.2752, FinalBearing(landsEnd, johnOGroats), 0.01)
+	assert.InDelta(t, 90, InitialBearing(LatLon{0, 0}, LatLon{0, 1}), 1e-9)
+	assert.InDelta(t, 270, InitialBearing(LatLon{0, 1}, LatLon{0, 0}), 1e-9)
+}
+
+func TestDestination(t *testing.T) {
+	start := LatLon{53.3206, -1.7297}
+	dest := Destination(start, 96.0217, 124800)
+	assert.InDelta(t, 53.1887, dest.Lat, 0.001)
+	assert.InDelta(t, 0.1334, dest.Lon, 0.001)
+
+	// crossing the antimeridian
+	dest = Destination(LatLon{0, 179.9}, 90, 2*NauticalMile*6)
+	assert.InDelta(t, -179.9, dest.Lon, 0.001)
+}
+
+func TestCrossTrack(t *testing.T) {
+	p := LatLon{53.2611, -0.7972}
+	start := LatLon{53.3206, -1.7297}
+	end := LatLon{53.1887, 0.1334}
+	assert.InDelta(t, -307.5, CrossTrackDistance(p, start, end), 1)
+	assert.InDelta(t, 62331, AlongTrackDistance(p, start, end), 20)
+}
+
+func TestRhumb(t *testing.T) {
+	a := LatLon{51.127, 1.338}
+	b := LatLon{50.964, 1.853}
+	assert.InDelta(t, 40310, RhumbDistance(a, b), 20)
+	assert.InDelta(t, 116.72, RhumbBearing(a, b), 0.01)
+
+	dest := RhumbDestination(a, 116.7, 40310)
+	assert.InDelta(t, 50.9642, dest.Lat, 0.001)
+	assert.InDelta(t, 1.8530, dest.Lon, 0.001)
+
+	// due east along a parallel
+	assert.InDelta(t, 90, RhumbBearing(LatLon{10, 0}, LatLon{10, 1}), 1e-9)
+}
+
+func TestVincentyDistance(t *testing.T) {
+	d, initial, final, err := VincentyDistance(landsEnd, johnOGroats)
+	assert.NoError(t, err)
+	assert.InDelta(t, 969954, d, 50)
+	assert.InDelta(t, 9.1418, initial, 0.01)
+	assert.InDelta(t, 11.2973, final, 0.01)
+
+	d, _, _, err = VincentyDistance(landsEnd, landsEnd)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, d)
+
+	_, _, _, err = VincentyDistance(LatLon{0, 0}, LatLon{0.5, 179.7})
+	assert.Equal(t, ErrNoConvergence, err)
+}
+
+func TestFromSentence(t *testing.T) {
+	s, err := nmea.Parse("$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70")
+	assert.NoError(t, err)
+	p, ok := FromSentence(s)
+	assert.True(t, ok)
+	assert.InDelta(t, 51.5637, p.Lat, 1e-4)
+	assert.InDelta(t, -0.704, p.Lon, 1e-4)
+
+	s, err = nmea.Parse("$GPHDT,123.456,T*32")
+	assert.NoError(t, err)
+	_, ok = FromSentence(s)
+	assert.False(t, ok)
+}
@@ -0,0 +1,109 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/coord"
+)
+
+// Waypoint is a named position.
+type Waypoint struct {
+	ID string
+	LatLon
+}
+
+// Navigation holds the steering information of a vessel following the leg
+// between two waypoints, as carried by the RMB, XTE and BWC sentences.
+type Navigation struct {
+	Position        LatLon
+	Origin          Waypoint
+	Destination     Waypoint
+	CrossTrack      float64 // Distance from the leg in meters, positive right of it
+	Range           float64 // Distance to the destination in meters
+	Bearing         float64 // Great circle bearing to the destination
+	ClosingVelocity float64 // Speed towards the destination in knots
+	Arrived         bool    // Inside the arrival radius or past the destination
+}
+
+// Navigate computes the steering information for a vessel at the position
+// moving with the given course and speed over ground (in knots) along the
+// leg from origin to destination. The vessel has arrived when it is within
+// the arrival radius (in meters) or has passed the destination.
+func Navigate(position LatLon, course, speed float64, origin, destination Waypoint, arrivalRadius float64) Navigation {
+	n := Navigation{
+		Position:    position,
+		Origin:      origin,
+		Destination: destination,
+		CrossTrack:  CrossTrackDistance(position, origin.LatLon, destination.LatLon),
+		Range:       Distance(position, destination.LatLon),
+		Bearing:     InitialBearing(position, destination.LatLon),
+	}
+	n.ClosingVelocity = speed * math.Cos(radians(course-n.Bearing))
+	legLength := Distance(origin.LatLon, destination.LatLon)
+	n.Arrived = n.Range <= arrivalRadius ||
+		AlongTrackDistance(position, origin.LatLon, destination.LatLon) >= legLength
+	return n
+}
+
+// steer returns the direction to steer to get back on the leg.
+func (n Navigation) steer() string {
+	if n.CrossTrack > 0 {
+		return "L"
+	}
+	return "R"
+}
+
+// XTE formats the cross track error sentence.
+func (n Navigation) XTE(talker string) string {
+	return nmea.FormatSentence(nmea.SentenceStart, talker+"XTE", []string{
+		"A", "A",
+		formatFloat(math.Abs(n.CrossTrack)/NauticalMile, 3), n.steer(), "N",
+		"A",
+	})
+}
+
+// RMB formats the recommended minimum navigation information sentence.
+func (n Navigation) RMB(talker string) string {
+	arrived := "V"
+	if n.Arrived {
+		arrived = "A"
+	}
+	lat, latDir := coord.NMEALat(n.Destination.Lat, coord.DefaultPrecision)
+	lon, lonDir := coord.NMEALon(n.Destination.Lon, coord.DefaultPrecision)
+	return nmea.FormatSentence(nmea.SentenceStart, talker+"RMB", []string{
+		"A",
+		formatFloat(math.Abs(n.CrossTrack)/NauticalMile, 3), n.steer(),
+		n.Origin.ID, n.Destination.ID,
+		lat, latDir, lon, lonDir,
+		formatFloat(n.Range/NauticalMile, 3),
+		formatFloat(n.Bearing, 1),
+		formatFloat(n.ClosingVelocity, 1),
+		arrived,
+		"A",
+	})
+}
+
+// BWC formats the bearing and distance to waypoint sentence for the given
+// UTC time. The magnetic bearing is left empty.
+func (n Navigation) BWC(talker string, t time.Time) string {
+	lat, latDir := coord.NMEALat(n.Destination.Lat, coord.DefaultPrecision)
+	lon, lonDir := coord.NMEALon(n.Destination.Lon, coord.DefaultPrecision)
+	t = t.UTC()
+	return nmea.FormatSentence(nmea.SentenceStart, talker+"BWC", []string{
+		fmt.Sprintf("%02d%02d%02d.%02d", t.Hour(), t.Minute(), t.Second(), t.Nanosecond()/1e7),
+		lat, latDir, lon, lonDir,
+		formatFloat(n.Bearing, 1), "T",
+		"", "M",
+		formatFloat(n.Range/NauticalMile, 3), "N",
+		n.Destination.ID,
+		"A",
+	})
+}
+
+func formatFloat(v float64, precision int) string {
+	return strconv.FormatFloat(v, 'f', precision, 64)
+}
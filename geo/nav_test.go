@@ -0,0 +1,33 @@
+package geo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNavigate(t *testing.T) {
+	origin := Waypoint{"A", LatLon{0, 0}}
+	dest := Waypoint{"B", LatLon{0, 1}}
+
+	n := Navigate(LatLon{0.01, 0.5}, 90, 6, origin, dest, 100)
+	assert.InDelta(t, -1112, n.CrossTrack, 1)
+	assert.InDelta(t, 55608, n.Range, 10)
+	assert.InDelta(t, 91.15, n.Bearing, 0.01)
+	assert.InDelta(t, 6, n.ClosingVelocity, 0.01)
+	assert.False(t, n.Arrived)
+
+	assert.Equal(t, "$GPXTE,A,A,0.600,R,N,A*2B", n.XTE("GP"))
+	assert.Equal(t, "$GPRMB,A,0.600,R,A,B,0000.0000,N,00100.0000,E,30.026,91.1,6.0,V,A*79", n.RMB("GP"))
+	assert.Equal(t, "$GPBWC,120000.50,0000.0000,N,00100.0000,E,91.1,T,,M,30.026,N,B,A*25",
+		n.BWC("GP", time.Date(2020, 1, 1, 12, 0, 0, 5e8, time.UTC)))
+
+	n = Navigate(LatLon{-0.001, 1.0001}, 90, 6, origin, dest, 100)
+	assert.True(t, n.Arrived)
+	assert.Equal(t, "L", n.steer())
+
+	n = Navigate(LatLon{0, 1.1}, 90, 6, origin, dest, 100)
+	assert.True(t, n.Arrived, "past the destination")
+	assert.InDelta(t, -6, n.ClosingVelocity, 0.01)
+}
@@ -0,0 +1,229 @@
+// Package geojson converts sentences and AIS targets into GeoJSON features
+// for web maps. Waypoints become points, positions from RMC and GGA become
+// a track line string and vessels decoded from VDM/VDO sentences become
+// points with their static and dynamic data as properties.
+package geojson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/ais"
+)
+
+// Geometry types
+const (
+	TypePoint      = "Point"
+	TypeLineString = "LineString"
+)
+
+// Geometry is a GeoJSON geometry. Coordinates are [longitude, latitude]
+// pairs.
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// Feature is a GeoJSON feature.
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// FeatureCollection is a GeoJSON feature collection.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// NewPoint returns a point geometry.
+func NewPoint(lat, lon float64) *Geometry {
+	return &Geometry{Type: TypePoint, Coordinates: [2]float64{lon, lat}}
+}
+
+// NewLineString returns a line string geometry from [longitude, latitude]
+// pairs.
+func NewLineString(coordinates [][2]float64) *Geometry {
+	return &Geometry{Type: TypeLineString, Coordinates: coordinates}
+}
+
+// NewFeature returns a feature with the geometry and properties.
+func NewFeature(g *Geometry, properties map[string]interface{}) Feature {
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	return Feature{Type: "Feature", Geometry: g, Properties: properties}
+}
+
+// NewFeatureCollection returns a collection of the features.
+func NewFeatureCollection(features ...Feature) FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// Properties returns the exported fields of a struct as feature properties,
+// keyed by field name. Embedded structs are flattened, except the
+// BaseSentence of sentences whose prefix is kept as the "Sentence"
+// property. A field followed by a boolean field of the same name suffixed
+// with Valid, like HDOP and HDOPValid, is nil when the value is not valid.
+// Struct values implementing fmt.Stringer, like Time and Date, are kept as
+// their string.
+func Properties(v interface{}) map[string]interface{} {
+	props := map[string]interface{}{}
+	if s, ok := v.(nmea.Sentence); ok {
+		props["Sentence"] = s.Prefix()
+	}
+	addProperties(props, reflect.ValueOf(v))
+	return props
+}
+
+var baseSentenceType = reflect.TypeOf(nmea.BaseSentence{})
+
+func addProperties(props map[string]interface{}, v reflect.Value) {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Type == baseSentenceType {
+			continue
+		}
+		if f.Anonymous {
+			addProperties(props, v.Field(i))
+			continue
+		}
+		if strings.HasSuffix(f.Name, "Valid") {
+			if _, ok := t.FieldByName(strings.TrimSuffix(f.Name, "Valid")); ok {
+				continue
+			}
+		}
+		value := v.Field(i).Interface()
+		if str, ok := value.(fmt.Stringer); ok && f.Type.Kind() == reflect.Struct {
+			value = str.String()
+		}
+		if valid := v.FieldByName(f.Name + "Valid"); valid.IsValid() && valid.Kind() == reflect.Bool && !valid.Bool() {
+			value = nil
+		}
+		props[f.Name] = value
+	}
+}
+
+// FromSentence returns a point feature for sentences carrying a position:
+// WPL, RMC, GGA, GLL and GNS.
+func FromSentence(s nmea.Sentence) (Feature, bool) {
+	var lat, lon float64
+	switch m := s.(type) {
+	case nmea.WPL:
+		lat, lon = m.Latitude, m.Longitude
+	case nmea.RMC:
+		lat, lon = m.Latitude, m.Longitude
+	case nmea.GGA:
+		lat, lon = m.Latitude, m.Longitude
+	case nmea.GLL:
+		lat, lon = m.Latitude, m.Longitude
+	case nmea.GNS:
+		lat, lon = m.Latitude, m.Longitude
+	default:
+		return Feature{}, false
+	}
+	f := NewFeature(NewPoint(lat, lon), Properties(s))
+	if w, ok := s.(nmea.WPL); ok {
+		f.ID = w.Ident
+	}
+	return f, true
+}
+
+// FromVessel returns a point feature for a vessel with a valid position.
+func FromVessel(v ais.Vessel) (Feature, bool) {
+	if !v.PositionValid {
+		return Feature{}, false
+	}
+	f := NewFeature(NewPoint(v.Latitude, v.Longitude), Properties(v))
+	f.ID = v.MMSI
+	return f, true
+}
+
+// Collector builds a feature collection from a stream of sentences.
+type Collector struct {
+	mu        sync.Mutex
+	track     [][2]float64
+	waypoints []Feature
+	index     map[string]int
+	vessels   *ais.Tracker
+}
+
+// NewCollector constructor
+func NewCollector() *Collector {
+	return &Collector{
+		index:   map[string]int{},
+		vessels: ais.NewTracker(),
+	}
+}
+
+// Add observes a sentence. WPL sentences add or move a waypoint, valid RMC
+// and GGA positions extend the track and VDM/VDO sentences update vessels.
+func (c *Collector) Add(s nmea.Sentence) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch m := s.(type) {
+	case nmea.WPL:
+		f, _ := FromSentence(m)
+		if i, ok := c.index[m.Ident]; ok {
+			c.waypoints[i] = f
+		} else {
+			c.index[m.Ident] = len(c.waypoints)
+			c.waypoints = append(c.waypoints, f)
+		}
+	case nmea.RMC:
+		if m.Validity == nmea.ValidRMC {
+			c.extend(m.Latitude, m.Longitude)
+		}
+	case nmea.GGA:
+		if m.FixQuality != nmea.Invalid {
+			c.extend(m.Latitude, m.Longitude)
+		}
+	case nmea.VDMVDO:
+		c.vessels.Add(m)
+	}
+}
+
+// extend adds a position to the track, skipping repeats of the last one
+// reported by another sentence of the same fix.
+func (c *Collector) extend(lat, lon float64) {
+	p := [2]float64{lon, lat}
+	if n := len(c.track); n > 0 && c.track[n-1] == p {
+		return
+	}
+	c.track = append(c.track, p)
+}
+
+// FeatureCollection returns the waypoints, the track when it has at least
+// two positions, and the vessels with a known position.
+func (c *Collector) FeatureCollection() FeatureCollection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	features := append([]Feature(nil), c.waypoints...)
+	if len(c.track) > 1 {
+		track := append([][2]float64(nil), c.track...)
+		features = append(features, NewFeature(NewLineString(track), map[string]interface{}{"Name": "Track"}))
+	}
+	for _, v := range c.vessels.Vessels() {
+		if f, ok := FromVessel(v); ok {
+			features = append(features, f)
+		}
+	}
+	return NewFeatureCollection(features...)
+}
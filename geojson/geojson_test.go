@@ -0,0 +1,81 @@
+package geojson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+func parse(t *testing.T, raw string) nmea.Sentence {
+	s, err := nmea.Parse(raw)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestFromSentence(t *testing.T) {
+	f, ok := FromSentence(parse(t, "$GPWPL,5128.62,N,00027.58,W,EGLL*59"))
+	assert.True(t, ok)
+	assert.Equal(t, "EGLL", f.ID)
+	assert.Equal(t, TypePoint, f.Geometry.Type)
+	assert.Equal(t, map[string]interface{}{
+		"Sentence":  "GPWPL",
+		"Latitude":  51.477,
+		"Longitude": -0.4596666666666666,
+		"Ident":     "EGLL",
+	}, f.Properties)
+
+	f, ok = FromSentence(parse(t, "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,,130694,,*29"))
+	assert.True(t, ok)
+	assert.Equal(t, "22:05:16.0000", f.Properties["Time"])
+	assert.Equal(t, "13/06/94", f.Properties["Date"])
+	assert.Equal(t, 173.8, f.Properties["Speed"])
+	assert.Nil(t, f.Properties["Course"])
+	assert.NotContains(t, f.Properties, "CourseValid")
+
+	_, ok = FromSentence(parse(t, "$GPHDT,123.456,T*32"))
+	assert.False(t, ok)
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	for _, raw := range []string{
+		"$GPWPL,5128.62,N,00027.58,W,EGLL*59",
+		"$GPWPL,4900.58,N,00233.00,E,LFPG*54",
+		"$GPRMC,120000,A,5128.62,N,00027.58,W,5.0,90.0,150320,,*37",
+		"$GPGGA,120000,5128.62,N,00027.58,W,2,08,0.9,25.5,M,47.0,M,,*62",
+		"$GPGGA,120001,5128.62,N,00027.50,W,1,07,1.1,26.0,M,47.0,M,,*68",
+		"!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A",
+		"!AIVDM,1,1,,A,H42O55i18tMET00000000000000,2*6D",
+	} {
+		c.Add(parse(t, raw))
+	}
+	fc := c.FeatureCollection()
+	if !assert.Len(t, fc.Features, 4) {
+		return
+	}
+	assert.Equal(t, "EGLL", fc.Features[0].ID)
+	assert.Equal(t, "LFPG", fc.Features[1].ID)
+
+	track := fc.Features[2].Geometry
+	assert.Equal(t, TypeLineString, track.Type)
+	assert.Equal(t, [][2]float64{{-0.4596666666666666, 51.477}, {-0.4583333333333333, 51.477}}, track.Coordinates)
+
+	vessel := fc.Features[3]
+	assert.Equal(t, uint32(371798000), vessel.ID)
+	assert.Equal(t, 12.3, vessel.Properties["SpeedOverGround"])
+	assert.Equal(t, 215, vessel.Properties["TrueHeading"])
+	assert.NotContains(t, vessel.Properties, "TrueHeadingValid")
+
+	b, err := json.Marshal(fc)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `{"type":"FeatureCollection","features":[{"type":"Feature","id":"EGLL","geometry":{"type":"Point","coordinates":[-0.4596666666666666,51.477]}`)
+}
+
+func TestEmptyCollection(t *testing.T) {
+	b, err := json.Marshal(NewCollector().FeatureCollection())
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"FeatureCollection","features":[]}`, string(b))
+}
@@ -22,33 +22,50 @@ const (
 // GGA is the Time, position, and fix related data of the receiver.
 type GGA struct {
 	BaseSentence
-	Time          Time    // Time of fix.
-	Latitude      float64 // Latitude.
-	Longitude     float64 // Longitude.
-	FixQuality    string  // Quality of fix.
-	NumSatellites int64   // Number of satellites in use.
-	HDOP          float64 // Horizontal dilution of precision.
-	Altitude      float64 // Altitude.
-	Separation    float64 // Geoidal separation
-	DGPSAge       string  // Age of differential GPD data.
-	DGPSId        string  // DGPS reference station ID.
+	Time            Time    // Time of fix.
+	Latitude        float64 // Latitude.
+	Longitude       float64 // Longitude.
+	FixQuality      string  // Quality of fix.
+	NumSatellites   int64   // Number of satellites in use.
+	HDOP            float64 // Horizontal dilution of precision.
+	HDOPValid       bool    // HDOP is present in the sentence.
+	Altitude        float64 // Altitude.
+	AltitudeValid   bool    // Altitude is present in the sentence.
+	Separation      float64 // Geoidal separation
+	SeparationValid bool    // Separation is present in the sentence.
+	DGPSAge         string  // Age of differential GPD data.
+	DGPSId          string  // DGPS reference station ID.
 }
 
 // newGGA constructor
 func newGGA(s BaseSentence) (GGA, error) {
 	p := NewParser(s)
 	p.AssertType(TypeGGA)
-	return GGA{
+	m := GGA{
 		BaseSentence:  s,
 		Time:          p.Time(0, "time"),
 		Latitude:      p.LatLong(1, 2, "latitude"),
 		Longitude:     p.LatLong(3, 4, "longitude"),
 		FixQuality:    p.EnumString(5, "fix quality", Invalid, GPS, DGPS, PPS, RTK, FRTK, EST),
 		NumSatellites: p.Int64(6, "number of satellites"),
-		HDOP:          p.Float64(7, "hdop"),
-		Altitude:      p.Float64(8, "altitude"),
-		Separation:    p.Float64(10, "separation"),
-		DGPSAge:       p.String(12, "dgps age"),
-		DGPSId:        p.String(13, "dgps id"),
-	}, p.Err()
+	}
+	hdop := p.NullFloat64(7, "hdop")
+	m.HDOP, m.HDOPValid = hdop.Value, hdop.Valid
+	altitude := p.NullFloat64(8, "altitude")
+	m.Altitude, m.AltitudeValid = altitude.Value, altitude.Valid
+	separation := p.NullFloat64(10, "separation")
+	m.Separation, m.SeparationValid = separation.Value, separation.Valid
+	m.DGPSAge = p.String(12, "dgps age")
+	m.DGPSId = p.String(13, "dgps id")
+	return m, p.Err()
+}
+
+// MarshalJSON implements json.Marshaler
+func (s GGA) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *GGA) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
 }
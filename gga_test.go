@@ -23,15 +23,30 @@ var ggatests = []struct {
 				Second:      15,
 				Millisecond: 0,
 			},
-			Latitude:      MustParseLatLong("6325.6138 N"),
-			Longitude:     MustParseLatLong("01021.4290 E"),
-			FixQuality:    "1",
-			NumSatellites: 8,
-			HDOP:          2.42,
-			Altitude:      72.5,
-			Separation:    41.5,
-			DGPSAge:       "",
-			DGPSId:        "",
+			Latitude:        MustParseLatLong("6325.6138 N"),
+			Longitude:       MustParseLatLong("01021.4290 E"),
+			FixQuality:      "1",
+			NumSatellites:   8,
+			HDOP:            2.42,
+			Altitude:        72.5,
+			Separation:      41.5,
+			DGPSAge:         "",
+			DGPSId:          "",
+			HDOPValid:       true,
+			AltitudeValid:   true,
+			SeparationValid: true,
+		},
+	},
+	{
+		name: "missing altitude",
+		raw:  "$GPGGA,034225.077,3356.4650,S,15124.5567,E,1,03,,,M,,M,,0000*58",
+		msg: GGA{
+			Time:          Time{true, 3, 42, 25, 77},
+			Latitude:      MustParseLatLong("3356.4650 S"),
+			Longitude:     MustParseLatLong("15124.5567 E"),
+			FixQuality:    GPS,
+			NumSatellites: 3,
+			DGPSId:        "0000",
 		},
 	},
 	{
@@ -53,16 +68,19 @@ var ggatests = []struct {
 		name: "good sentence",
 		raw:  "$GPGGA,034225.077,3356.4650,S,15124.5567,E,1,03,9.7,-25.0,M,21.0,M,,0000*51",
 		msg: GGA{
-			Time:          Time{true, 3, 42, 25, 77},
-			Latitude:      MustParseLatLong("3356.4650 S"),
-			Longitude:     MustParseLatLong("15124.5567 E"),
-			FixQuality:    GPS,
-			NumSatellites: 03,
-			HDOP:          9.7,
-			Altitude:      -25.0,
-			Separation:    21.0,
-			DGPSAge:       "",
-			DGPSId:        "0000",
+			Time:            Time{true, 3, 42, 25, 77},
+			Latitude:        MustParseLatLong("3356.4650 S"),
+			Longitude:       MustParseLatLong("15124.5567 E"),
+			FixQuality:      GPS,
+			NumSatellites:   03,
+			HDOP:            9.7,
+			Altitude:        -25.0,
+			Separation:      21.0,
+			DGPSAge:         "",
+			DGPSId:          "0000",
+			HDOPValid:       true,
+			AltitudeValid:   true,
+			SeparationValid: true,
 		},
 	},
 	{
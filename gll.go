@@ -31,3 +31,13 @@ func newGLL(s BaseSentence) (GLL, error) {
 		Validity:     p.EnumString(5, "validity", ValidGLL, InvalidGLL),
 	}, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s GLL) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *GLL) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
@@ -26,16 +26,18 @@ const (
 // GNS is standard GNSS sentance that combined multiple constellations
 type GNS struct {
 	BaseSentence
-	Time       Time
-	Latitude   float64
-	Longitude  float64
-	Mode       []string
-	SVs        int64
-	HDOP       float64
-	Altitude   float64
-	Separation float64
-	Age        float64
-	Station    int64
+	Time            Time
+	Latitude        float64
+	Longitude       float64
+	Mode            []string
+	SVs             int64
+	HDOP            float64
+	Altitude        float64
+	AltitudeValid   bool // Altitude is present in the sentence
+	Separation      float64
+	SeparationValid bool // Separation is present in the sentence
+	Age             float64
+	Station         int64
 }
 
 // newGNS Constructor
@@ -50,10 +52,22 @@ func newGNS(s BaseSentence) (GNS, error) {
 		Mode:         p.EnumChars(5, "mode", NoFixGNS, AutonomousGNS, DifferentialGNS, PreciseGNS, RealTimeKinematicGNS, FloatRTKGNS, EstimatedGNS, ManualGNS, SimulatorGNS),
 		SVs:          p.Int64(6, "SVs"),
 		HDOP:         p.Float64(7, "HDOP"),
-		Altitude:     p.Float64(8, "altitude"),
-		Separation:   p.Float64(9, "separation"),
-		Age:          p.Float64(10, "age"),
-		Station:      p.Int64(11, "station"),
 	}
+	altitude := p.NullFloat64(8, "altitude")
+	m.Altitude, m.AltitudeValid = altitude.Value, altitude.Valid
+	separation := p.NullFloat64(9, "separation")
+	m.Separation, m.SeparationValid = separation.Value, separation.Valid
+	m.Age = p.Float64(10, "age")
+	m.Station = p.Int64(11, "station")
 	return m, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s GNS) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *GNS) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
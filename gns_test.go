@@ -16,48 +16,64 @@ var gnstests = []struct {
 		name: "good sentence A",
 		raw:  "$GNGNS,014035.00,4332.69262,S,17235.48549,E,RR,13,0.9,25.63,11.24,,*70",
 		msg: GNS{
-			Time:       Time{true, 1, 40, 35, 0},
-			Latitude:   MustParseGPS("4332.69262 S"),
-			Longitude:  MustParseGPS("17235.48549 E"),
-			Mode:       []string{"R", "R"},
-			SVs:        13,
-			HDOP:       0.9,
-			Altitude:   25.63,
-			Separation: 11.24,
-			Age:        0,
-			Station:    0,
+			Time:            Time{true, 1, 40, 35, 0},
+			Latitude:        MustParseGPS("4332.69262 S"),
+			Longitude:       MustParseGPS("17235.48549 E"),
+			Mode:            []string{"R", "R"},
+			SVs:             13,
+			HDOP:            0.9,
+			Altitude:        25.63,
+			AltitudeValid:   true,
+			Separation:      11.24,
+			SeparationValid: true,
+			Age:             0,
+			Station:         0,
 		},
 	},
 	{
 		name: "good sentence B",
 		raw:  "$GNGNS,094821.0,4849.931307,N,00216.053323,E,AA,14,0.6,161.5,48.0,,*6D",
 		msg: GNS{
-			Time:       Time{true, 9, 48, 21, 0},
-			Latitude:   MustParseGPS("4849.931307 N"),
-			Longitude:  MustParseGPS("00216.053323 E"),
-			Mode:       []string{"A", "A"},
-			SVs:        14,
-			HDOP:       0.6,
-			Altitude:   161.5,
-			Separation: 48.0,
-			Age:        0,
-			Station:    0,
+			Time:            Time{true, 9, 48, 21, 0},
+			Latitude:        MustParseGPS("4849.931307 N"),
+			Longitude:       MustParseGPS("00216.053323 E"),
+			Mode:            []string{"A", "A"},
+			SVs:             14,
+			HDOP:            0.6,
+			Altitude:        161.5,
+			AltitudeValid:   true,
+			Separation:      48.0,
+			SeparationValid: true,
+			Age:             0,
+			Station:         0,
 		},
 	},
 	{
 		name: "good sentence B",
 		raw:  "$GNGNS,094821.0,4849.931307,N,00216.053323,E,AAN,14,0.6,161.5,48.0,,*23",
 		msg: GNS{
-			Time:       Time{true, 9, 48, 21, 0},
-			Latitude:   MustParseGPS("4849.931307 N"),
-			Longitude:  MustParseGPS("00216.053323 E"),
-			Mode:       []string{"A", "A", "N"},
-			SVs:        14,
-			HDOP:       0.6,
-			Altitude:   161.5,
-			Separation: 48.0,
-			Age:        0,
-			Station:    0,
+			Time:            Time{true, 9, 48, 21, 0},
+			Latitude:        MustParseGPS("4849.931307 N"),
+			Longitude:       MustParseGPS("00216.053323 E"),
+			Mode:            []string{"A", "A", "N"},
+			SVs:             14,
+			HDOP:            0.6,
+			Altitude:        161.5,
+			AltitudeValid:   true,
+			Separation:      48.0,
+			SeparationValid: true,
+			Age:             0,
+			Station:         0,
+		},
+	},
+	{
+		name: "good sentence without fix",
+		raw:  "$GNGNS,014035.00,4332.69262,S,17235.48549,E,NN,00,,,,,*51",
+		msg: GNS{
+			Time:      Time{true, 1, 40, 35, 0},
+			Latitude:  MustParseGPS("4332.69262 S"),
+			Longitude: MustParseGPS("17235.48549 E"),
+			Mode:      []string{"N", "N"},
 		},
 	},
 	{
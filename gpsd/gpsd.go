@@ -0,0 +1,364 @@
+// Package gpsd serves a sentence stream to clients of the gpsd JSON
+// protocol, such as cgps, OpenCPN or Navit.
+//
+// Sentences are folded into a fix state, reported as TPV objects on every
+// position sentence and as SKY objects on every complete GSV cycle.
+// Clients enable reports with ?WATCH, either as JSON objects or as the raw
+// sentences, and query the server with ?VERSION and ?DEVICES.
+package gpsd
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+const (
+	// DefaultAddress is the address gpsd listens on.
+	DefaultAddress = "127.0.0.1:2947"
+
+	// DefaultDevice is the path of the device reported to clients.
+	DefaultDevice = "nmea0183"
+
+	// Driver is the driver of the device reported to clients.
+	Driver = "NMEA0183"
+
+	// Release is the release reported in VERSION objects.
+	Release = "go-nmea"
+
+	// ProtoMajor is the major version of the protocol spoken by the server.
+	ProtoMajor = 3
+	// ProtoMinor is the minor version of the protocol spoken by the server.
+	ProtoMinor = 14
+
+	// timeLayout is the layout of the times of reports.
+	timeLayout = "2006-01-02T15:04:05.000Z"
+
+	// knot is a knot in meters per second.
+	knot = 1852.0 / 3600
+)
+
+// Classes of the objects of the protocol.
+const (
+	ClassTPV     = "TPV"
+	ClassSKY     = "SKY"
+	ClassVersion = "VERSION"
+	ClassDevices = "DEVICES"
+	ClassDevice  = "DEVICE"
+	ClassWatch   = "WATCH"
+	ClassError   = "ERROR"
+)
+
+// Fix modes of TPV objects.
+const (
+	ModeUnknown = 0
+	ModeNoFix   = 1
+	Mode2D      = 2
+	Mode3D      = 3
+)
+
+// TPV is a time-position-velocity report. Fields missing from the fix are
+// left out.
+type TPV struct {
+	Class  string   `json:"class"`
+	Device string   `json:"device,omitempty"`
+	Mode   int      `json:"mode"`
+	Time   string   `json:"time,omitempty"`
+	Lat    *float64 `json:"lat,omitempty"`
+	Lon    *float64 `json:"lon,omitempty"`
+	Alt    *float64 `json:"alt,omitempty"`    // Altitude above mean sea level, in meters
+	AltMSL *float64 `json:"altMSL,omitempty"` // Altitude above mean sea level, in meters
+	AltHAE *float64 `json:"altHAE,omitempty"` // Altitude above the ellipsoid, in meters
+	Track  *float64 `json:"track,omitempty"`  // Course over ground, in degrees from true north
+	Speed  *float64 `json:"speed,omitempty"`  // Speed over ground, in meters per second
+}
+
+// SKY is a report of the satellites in view.
+type SKY struct {
+	Class      string      `json:"class"`
+	Device     string      `json:"device,omitempty"`
+	Time       string      `json:"time,omitempty"`
+	HDOP       float64     `json:"hdop,omitempty"`
+	VDOP       float64     `json:"vdop,omitempty"`
+	PDOP       float64     `json:"pdop,omitempty"`
+	NSat       int         `json:"nSat"`
+	USat       int         `json:"uSat"`
+	Satellites []Satellite `json:"satellites"`
+}
+
+// Satellite is a satellite in view.
+type Satellite struct {
+	PRN  int64   `json:"PRN"`
+	El   int64   `json:"el"`   // Elevation, in degrees
+	Az   int64   `json:"az"`   // Azimuth, in degrees from true north
+	SS   float64 `json:"ss"`   // Signal to noise ratio, in dB, or 0 when not tracked
+	Used bool    `json:"used"` // Used in the fix
+}
+
+// Version is the greeting of the server, and the reply to ?VERSION.
+type Version struct {
+	Class      string `json:"class"`
+	Release    string `json:"release"`
+	Rev        string `json:"rev"`
+	ProtoMajor int    `json:"proto_major"`
+	ProtoMinor int    `json:"proto_minor"`
+}
+
+// Devices is the reply to ?DEVICES.
+type Devices struct {
+	Class   string   `json:"class"`
+	Devices []Device `json:"devices"`
+}
+
+// Device describes the device sentences are read from.
+type Device struct {
+	Class     string `json:"class"`
+	Path      string `json:"path"`
+	Driver    string `json:"driver"`
+	Activated string `json:"activated,omitempty"`
+}
+
+// Watch is the watch policy of a client, set with ?WATCH.
+type Watch struct {
+	Class  string `json:"class"`
+	Enable bool   `json:"enable"`
+	JSON   bool   `json:"json"`   // Send reports as JSON objects
+	NMEA   bool   `json:"nmea"`   // Send the sentences as received
+	Raw    int    `json:"raw"`    // Send the sentences as received, when above 0
+	Scaled bool   `json:"scaled"` // Accepted for compatibility, values are always scaled
+	Device string `json:"device,omitempty"`
+}
+
+// Error is the reply to a malformed or unknown request.
+type Error struct {
+	Class   string `json:"class"`
+	Message string `json:"message"`
+}
+
+// State folds sentences into the current fix and satellites in view.
+// Times of sentences carrying only a time of day are resolved from the
+// dates of RMC and ZDA sentences.
+type State struct {
+	mu        sync.Mutex
+	dates     *nmea.DateTracker
+	tpv       TPV
+	sky       SKY
+	fix       int                    // Fix mode of the last GSA sentence
+	cycles    map[string][]Satellite // GSV cycles in progress, by talker
+	visible   map[string][]Satellite // Last complete GSV cycle, by talker
+	used      map[int64]bool         // Satellites used in the fix
+	usedStale bool                   // A SKY was reported since the last GSA
+}
+
+// NewState constructor
+func NewState() *State {
+	return &State{
+		dates:   nmea.NewDateTracker(),
+		tpv:     TPV{Class: ClassTPV},
+		sky:     SKY{Class: ClassSKY, Satellites: []Satellite{}},
+		cycles:  map[string][]Satellite{},
+		visible: map[string][]Satellite{},
+		used:    map[int64]bool{},
+	}
+}
+
+// Update folds a sentence into the state and returns the reports it
+// completes: a TPV for RMC, GGA, GNS and GLL sentences, and a SKY for the
+// last GSV sentence of a cycle.
+func (st *State) Update(s nmea.Sentence) []interface{} {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	t, dated := st.dates.Update(s)
+	if dated {
+		st.tpv.Time = t.UTC().Format(timeLayout)
+	}
+	switch m := s.(type) {
+	case nmea.RMC:
+		if m.Validity != nmea.ValidRMC {
+			st.noFix()
+			break
+		}
+		st.position(m.Latitude, m.Longitude)
+		if m.SpeedValid {
+			st.tpv.Speed = float(m.Speed * knot)
+		}
+		if m.CourseValid {
+			st.tpv.Track = float(m.Course)
+		}
+	case nmea.GGA:
+		if m.FixQuality == nmea.Invalid {
+			st.noFix()
+			break
+		}
+		if m.AltitudeValid {
+			st.altitude(m.Altitude, m.Separation, m.SeparationValid)
+		}
+		st.position(m.Latitude, m.Longitude)
+	case nmea.GNS:
+		if !gnsFix(m.Mode) {
+			st.noFix()
+			break
+		}
+		if m.AltitudeValid {
+			st.altitude(m.Altitude, m.Separation, m.SeparationValid)
+		}
+		st.position(m.Latitude, m.Longitude)
+	case nmea.GLL:
+		if m.Validity != nmea.ValidGLL {
+			st.noFix()
+			break
+		}
+		st.position(m.Latitude, m.Longitude)
+	case nmea.VTG:
+		if st.tpv.Mode <= ModeNoFix || m.Mode == nmea.NotValidVTG {
+			return nil
+		}
+		if m.TrueTrackValid {
+			st.tpv.Track = float(m.TrueTrack)
+		}
+		if m.GroundSpeedKnotsValid {
+			st.tpv.Speed = float(m.GroundSpeedKnots * knot)
+		}
+		return nil
+	case nmea.GSA:
+		st.gsa(m)
+		return nil
+	case nmea.GSV:
+		if st.gsv(m) {
+			return []interface{}{st.skyReport()}
+		}
+		return nil
+	default:
+		return nil
+	}
+	return []interface{}{st.tpv}
+}
+
+// TPV returns the current fix.
+func (st *State) TPV() TPV {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.tpv
+}
+
+// SKY returns the satellites in view.
+func (st *State) SKY() SKY {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.sky
+}
+
+func (st *State) position(lat, lon float64) {
+	st.tpv.Lat, st.tpv.Lon = float(lat), float(lon)
+	switch {
+	case st.fix >= Mode2D:
+		st.tpv.Mode = st.fix
+	case st.tpv.AltMSL != nil:
+		st.tpv.Mode = Mode3D
+	default:
+		st.tpv.Mode = Mode2D
+	}
+}
+
+func (st *State) altitude(msl, separation float64, separationValid bool) {
+	st.tpv.Alt, st.tpv.AltMSL = float(msl), float(msl)
+	if separationValid {
+		st.tpv.AltHAE = float(msl + separation)
+	}
+}
+
+// noFix clears the fix after a sentence reporting it lost.
+func (st *State) noFix() {
+	st.tpv = TPV{Class: ClassTPV, Mode: ModeNoFix, Time: st.tpv.Time}
+}
+
+func (st *State) gsa(m nmea.GSA) {
+	switch m.FixType {
+	case nmea.FixNone:
+		st.fix = ModeNoFix
+	case nmea.Fix2D:
+		st.fix = Mode2D
+	case nmea.Fix3D:
+		st.fix = Mode3D
+	}
+	st.sky.PDOP, st.sky.HDOP, st.sky.VDOP = m.PDOP, m.HDOP, m.VDOP
+
+	// Receivers tracking several constellations send a GSA per
+	// constellation, so the satellites used add up until the next SKY.
+	if st.usedStale {
+		st.used = map[int64]bool{}
+		st.usedStale = false
+	}
+	for _, sv := range m.SV {
+		if prn, err := strconv.ParseInt(sv, 10, 64); err == nil {
+			st.used[prn] = true
+		}
+	}
+}
+
+// gsv adds the satellites of a GSV sentence to the cycle of its talker and
+// reports whether the cycle is complete.
+func (st *State) gsv(m nmea.GSV) bool {
+	talker := m.TalkerID()
+	if m.MessageNumber == 1 {
+		st.cycles[talker] = nil
+	}
+	for _, info := range m.Info {
+		sat := Satellite{PRN: info.SVPRNNumber, El: info.Elevation, Az: info.Azimuth}
+		if info.SNRValid {
+			sat.SS = float64(info.SNR)
+		}
+		st.cycles[talker] = append(st.cycles[talker], sat)
+	}
+	if m.MessageNumber < m.TotalMessages {
+		return false
+	}
+	st.visible[talker] = st.cycles[talker]
+	delete(st.cycles, talker)
+	return true
+}
+
+// skyReport updates the satellites in view from the last complete cycles of
+// every talker and returns them.
+func (st *State) skyReport() SKY {
+	talkers := make([]string, 0, len(st.visible))
+	for talker := range st.visible {
+		talkers = append(talkers, talker)
+	}
+	sort.Strings(talkers)
+
+	st.sky.Time = st.tpv.Time
+	st.sky.Satellites = []Satellite{}
+	st.sky.USat = 0
+	for _, talker := range talkers {
+		for _, sat := range st.visible[talker] {
+			sat.Used = st.used[sat.PRN]
+			if sat.Used {
+				st.sky.USat++
+			}
+			st.sky.Satellites = append(st.sky.Satellites, sat)
+		}
+	}
+	st.sky.NSat = len(st.sky.Satellites)
+	st.usedStale = true
+	return st.sky
+}
+
+// gnsFix reports whether any system of the mode has a fix.
+func gnsFix(modes []string) bool {
+	for _, m := range modes {
+		if m != nmea.NoFixGNS {
+			return true
+		}
+	}
+	return false
+}
+
+func float(v float64) *float64 {
+	return &v
+}
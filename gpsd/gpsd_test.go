@@ -0,0 +1,123 @@
+package gpsd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+var statetests = []struct {
+	name    string
+	raw     string
+	reports []string
+}{
+	{
+		name:    "GSA",
+		raw:     "$GPGSA,A,3,04,05,,09,12,,,24,,,,,2.5,1.3,2.1*39",
+		reports: []string{},
+	},
+	{
+		name:    "RMC",
+		raw:     "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70",
+		reports: []string{`{"class":"TPV","mode":3,"time":"1994-06-13T22:05:16.000Z","lat":51.56366666666666,"lon":-0.7040000000000001,"track":231.8,"speed":89.41044444444445}`},
+	},
+	{
+		name:    "GGA",
+		raw:     "$GPGGA,220516,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*56",
+		reports: []string{`{"class":"TPV","mode":3,"time":"1994-06-13T22:05:16.000Z","lat":51.56366666666666,"lon":-0.7040000000000001,"alt":545.4,"altMSL":545.4,"altHAE":592.3,"track":231.8,"speed":89.41044444444445}`},
+	},
+	{
+		name:    "VTG",
+		raw:     "$GPVTG,231.8,T,,M,10.0,N,18.5,K*65",
+		reports: []string{},
+	},
+	{
+		name:    "first GSV",
+		raw:     "$GPGSV,2,1,05,04,45,180,42,05,30,090,38,09,10,045,,12,60,270,40*7C",
+		reports: []string{},
+	},
+	{
+		name:    "last GSV",
+		raw:     "$GPGSV,2,2,05,24,05,300,20*4E",
+		reports: []string{`{"class":"SKY","time":"1994-06-13T22:05:16.000Z","hdop":1.3,"vdop":2.1,"pdop":2.5,"nSat":5,"uSat":5,"satellites":[{"PRN":4,"el":45,"az":180,"ss":42,"used":true},{"PRN":5,"el":30,"az":90,"ss":38,"used":true},{"PRN":9,"el":10,"az":45,"ss":0,"used":true},{"PRN":12,"el":60,"az":270,"ss":40,"used":true},{"PRN":24,"el":5,"az":300,"ss":20,"used":true}]}`},
+	},
+	{
+		name:    "GLONASS GSV",
+		raw:     "$GLGSV,1,1,01,65,20,100,30*57",
+		reports: []string{`{"class":"SKY","time":"1994-06-13T22:05:16.000Z","hdop":1.3,"vdop":2.1,"pdop":2.5,"nSat":6,"uSat":5,"satellites":[{"PRN":65,"el":20,"az":100,"ss":30,"used":false},{"PRN":4,"el":45,"az":180,"ss":42,"used":true},{"PRN":5,"el":30,"az":90,"ss":38,"used":true},{"PRN":9,"el":10,"az":45,"ss":0,"used":true},{"PRN":12,"el":60,"az":270,"ss":40,"used":true},{"PRN":24,"el":5,"az":300,"ss":20,"used":true}]}`},
+	},
+	{
+		name:    "lost fix",
+		raw:     "$GPRMC,220517,V,,,,,,,130694,,*3B",
+		reports: []string{`{"class":"TPV","mode":1,"time":"1994-06-13T22:05:17.000Z"}`},
+	},
+	{
+		name:    "unsupported",
+		raw:     "$GPHDT,123.456,T*32",
+		reports: []string{},
+	},
+}
+
+func TestState(t *testing.T) {
+	st := NewState()
+	for _, tt := range statetests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := nmea.Parse(tt.raw)
+			if !assert.NoError(t, err) {
+				return
+			}
+			reports := []string{}
+			for _, r := range st.Update(s) {
+				b, err := json.Marshal(r)
+				assert.NoError(t, err)
+				reports = append(reports, string(b))
+			}
+			assert.Equal(t, tt.reports, reports)
+		})
+	}
+	assert.Equal(t, ModeNoFix, st.TPV().Mode)
+	assert.Equal(t, 6, st.SKY().NSat)
+}
+
+func update(t *testing.T, st *State, raw string) []interface{} {
+	s, err := nmea.Parse(raw)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	return st.Update(s)
+}
+
+func TestStateVTG(t *testing.T) {
+	st := NewState()
+
+	// no fix yet
+	update(t, st, "$GPVTG,100.0,T,,M,20.0,N,37.0,K,A*0A")
+	assert.Nil(t, st.TPV().Track)
+
+	update(t, st, "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70")
+	update(t, st, "$GPVTG,,T,,M,10.0,N,18.5,K,A*2E")
+	tpv := st.TPV()
+	assert.Equal(t, 231.8, *tpv.Track)
+	assert.InDelta(t, 5.144, *tpv.Speed, 0.001)
+
+	// not valid
+	update(t, st, "$GPVTG,100.0,T,,M,20.0,N,37.0,K,N*05")
+	tpv = st.TPV()
+	assert.Equal(t, 231.8, *tpv.Track)
+	assert.InDelta(t, 5.144, *tpv.Speed, 0.001)
+}
+
+func TestStateGNSWithoutAltitude(t *testing.T) {
+	st := NewState()
+	reports := update(t, st, "$GNGNS,220518,5133.82,N,00042.24,W,AA,08,0.9,,,,*57")
+	if !assert.Len(t, reports, 1) {
+		return
+	}
+	tpv := reports[0].(TPV)
+	assert.Equal(t, Mode2D, tpv.Mode)
+	assert.Nil(t, tpv.Alt)
+	assert.Nil(t, tpv.AltHAE)
+}
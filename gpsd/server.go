@@ -0,0 +1,247 @@
+package gpsd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// clientBuffer is the number of lines queued for a client before lines are
+// dropped.
+const clientBuffer = 256
+
+// version is the greeting of the server.
+var version = Version{
+	Class:      ClassVersion,
+	Release:    Release,
+	Rev:        Release,
+	ProtoMajor: ProtoMajor,
+	ProtoMinor: ProtoMinor,
+}
+
+// Server serves the state of a sentence stream over TCP.
+type Server struct {
+	Device string // Path of the device reported to clients
+
+	state *State
+	now   func() time.Time
+
+	mu        sync.Mutex
+	activated time.Time
+	listeners map[net.Listener]bool
+	clients   map[*client]bool
+}
+
+// client is a connection to the server. Lines are queued and written by a
+// goroutine of their own, so that slow clients do not hold up the others.
+type client struct {
+	conn  net.Conn
+	out   chan []byte
+	watch Watch
+}
+
+// NewServer constructor
+func NewServer() *Server {
+	return &Server{
+		Device:    DefaultDevice,
+		state:     NewState(),
+		now:       time.Now,
+		listeners: map[net.Listener]bool{},
+		clients:   map[*client]bool{},
+	}
+}
+
+// ListenAndServe listens on the TCP address and serves clients.
+func (srv *Server) ListenAndServe(address string) error {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return srv.Serve(l)
+}
+
+// Serve accepts clients on the listener until it is closed.
+func (srv *Server) Serve(l net.Listener) error {
+	srv.mu.Lock()
+	srv.listeners[l] = true
+	srv.mu.Unlock()
+
+	defer func() {
+		srv.mu.Lock()
+		delete(srv.listeners, l)
+		srv.mu.Unlock()
+	}()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go srv.handle(conn)
+	}
+}
+
+// Close closes the listeners and the connections of all clients.
+func (srv *Server) Close() error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	var firstErr error
+	for l := range srv.listeners {
+		if err := l.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for c := range srv.clients {
+		c.conn.Close()
+	}
+	return firstErr
+}
+
+// State returns the state of the stream.
+func (srv *Server) State() *State {
+	return srv.state
+}
+
+// Publish updates the state with a sentence and sends it, or the reports
+// it completes, to the clients watching.
+func (srv *Server) Publish(s nmea.Sentence) {
+	reports := srv.state.Update(s)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if srv.activated.IsZero() {
+		srv.activated = srv.now()
+	}
+	for c := range srv.clients {
+		if !c.watch.Enable || (c.watch.Device != "" && c.watch.Device != srv.Device) {
+			continue
+		}
+		if c.watch.NMEA || c.watch.Raw > 0 {
+			c.send([]byte(s.String() + "\r\n"))
+		}
+		if c.watch.JSON {
+			for _, r := range reports {
+				c.send(srv.encode(r))
+			}
+		}
+	}
+}
+
+// Feed publishes the sentences read from r, one per line, until the end of
+// the stream. Lines which fail to parse are skipped.
+func (srv *Server) Feed(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		s, err := nmea.Parse(strings.TrimSpace(scanner.Text()))
+		if err != nil {
+			continue
+		}
+		srv.Publish(s)
+	}
+	return scanner.Err()
+}
+
+func (srv *Server) handle(conn net.Conn) {
+	c := &client{conn: conn, out: make(chan []byte, clientBuffer)}
+	go c.write()
+
+	srv.mu.Lock()
+	srv.clients[c] = true
+	c.send(srv.encode(version))
+	srv.mu.Unlock()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		for _, request := range strings.Split(scanner.Text(), ";") {
+			if request = strings.TrimSpace(request); request != "" {
+				srv.request(c, request)
+			}
+		}
+	}
+
+	srv.mu.Lock()
+	delete(srv.clients, c)
+	close(c.out)
+	srv.mu.Unlock()
+	conn.Close()
+}
+
+// request replies to a request of a client, e.g. ?WATCH={"enable":true}.
+func (srv *Server) request(c *client, request string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	command, arg := request, ""
+	if i := strings.IndexByte(request, '='); i >= 0 {
+		command, arg = request[:i], request[i+1:]
+	}
+	switch command {
+	case "?VERSION":
+		c.send(srv.encode(version))
+	case "?DEVICES":
+		c.send(srv.encode(srv.devices()))
+	case "?WATCH":
+		if arg != "" {
+			watch := c.watch
+			if err := json.Unmarshal([]byte(arg), &watch); err != nil {
+				c.send(srv.encode(Error{Class: ClassError, Message: fmt.Sprintf("Invalid WATCH: %s", arg)}))
+				return
+			}
+			if watch.Enable && !watch.JSON && !watch.NMEA && watch.Raw == 0 {
+				watch.JSON = true
+			}
+			c.watch = watch
+		}
+		c.watch.Class = ClassWatch
+		c.send(srv.encode(srv.devices()))
+		c.send(srv.encode(c.watch))
+	default:
+		c.send(srv.encode(Error{Class: ClassError, Message: fmt.Sprintf("Unrecognized request '%s'", strings.TrimPrefix(command, "?"))}))
+	}
+}
+
+func (srv *Server) devices() Devices {
+	d := Device{Class: ClassDevice, Path: srv.Device, Driver: Driver}
+	if !srv.activated.IsZero() {
+		d.Activated = srv.activated.UTC().Format(timeLayout)
+	}
+	return Devices{Class: ClassDevices, Devices: []Device{d}}
+}
+
+// encode returns the line of a report, stamped with the device.
+func (srv *Server) encode(v interface{}) []byte {
+	switch r := v.(type) {
+	case TPV:
+		r.Device = srv.Device
+		v = r
+	case SKY:
+		r.Device = srv.Device
+		v = r
+	}
+	b, _ := json.Marshal(v)
+	return append(b, '\r', '\n')
+}
+
+// send queues a line, dropping it when the client is too slow.
+func (c *client) send(line []byte) {
+	select {
+	case c.out <- line:
+	default:
+	}
+}
+
+func (c *client) write() {
+	for line := range c.out {
+		if _, err := c.conn.Write(line); err != nil {
+			c.conn.Close()
+		}
+	}
+}
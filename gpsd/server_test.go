@@ -0,0 +1,100 @@
+package gpsd
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(t *testing.T) (*Server, net.Conn, *bufio.Reader) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer()
+	srv.Device = "/dev/ttyUSB0"
+	srv.now = func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }
+	go srv.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return srv, conn, bufio.NewReader(conn)
+}
+
+func readLine(t *testing.T, r *bufio.Reader) string {
+	line, err := r.ReadString('\n')
+	assert.NoError(t, err)
+	return strings.TrimRight(line, "\r\n")
+}
+
+func request(t *testing.T, conn net.Conn, r *bufio.Reader, req string, n int) []string {
+	_, err := fmt.Fprintf(conn, "%s\n", req)
+	assert.NoError(t, err)
+	var lines []string
+	for i := 0; i < n; i++ {
+		lines = append(lines, readLine(t, r))
+	}
+	return lines
+}
+
+func TestServer(t *testing.T) {
+	srv, conn, r := serve(t)
+	defer srv.Close()
+
+	assert.Equal(t, `{"class":"VERSION","release":"go-nmea","rev":"go-nmea","proto_major":3,"proto_minor":14}`, readLine(t, r))
+	assert.Equal(t, []string{
+		`{"class":"VERSION","release":"go-nmea","rev":"go-nmea","proto_major":3,"proto_minor":14}`,
+		`{"class":"DEVICES","devices":[{"class":"DEVICE","path":"/dev/ttyUSB0","driver":"NMEA0183"}]}`,
+	}, request(t, conn, r, "?VERSION;?DEVICES;", 2))
+	assert.Equal(t, []string{
+		`{"class":"ERROR","message":"Unrecognized request 'FOO'"}`,
+	}, request(t, conn, r, "?FOO;", 1))
+	assert.Equal(t, []string{
+		`{"class":"ERROR","message":"Invalid WATCH: {enable}"}`,
+	}, request(t, conn, r, "?WATCH={enable};", 1))
+
+	// Sentences published before the client watches are not sent.
+	assert.NoError(t, srv.Feed(strings.NewReader("$GPGSA,A,3,04,05,,09,12,,,24,,,,,2.5,1.3,2.1*39\r\n")))
+
+	assert.Equal(t, []string{
+		`{"class":"DEVICES","devices":[{"class":"DEVICE","path":"/dev/ttyUSB0","driver":"NMEA0183","activated":"2020-01-02T03:04:05.000Z"}]}`,
+		`{"class":"WATCH","enable":true,"json":true,"nmea":false,"raw":0,"scaled":false}`,
+	}, request(t, conn, r, `?WATCH={"enable":true};`, 2))
+
+	assert.NoError(t, srv.Feed(strings.NewReader(strings.Join([]string{
+		"$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70",
+		"garbage",
+		"$GPGSV,1,1,01,04,45,180,42*42",
+	}, "\r\n"))))
+	assert.Equal(t, `{"class":"TPV","device":"/dev/ttyUSB0","mode":3,"time":"1994-06-13T22:05:16.000Z","lat":51.56366666666666,"lon":-0.7040000000000001,"track":231.8,"speed":89.41044444444445}`, readLine(t, r))
+	assert.Equal(t, `{"class":"SKY","device":"/dev/ttyUSB0","time":"1994-06-13T22:05:16.000Z","hdop":1.3,"vdop":2.1,"pdop":2.5,"nSat":1,"uSat":1,"satellites":[{"PRN":4,"el":45,"az":180,"ss":42,"used":true}]}`, readLine(t, r))
+
+	assert.Equal(t, []string{
+		`{"class":"DEVICES","devices":[{"class":"DEVICE","path":"/dev/ttyUSB0","driver":"NMEA0183","activated":"2020-01-02T03:04:05.000Z"}]}`,
+		`{"class":"WATCH","enable":true,"json":false,"nmea":true,"raw":0,"scaled":false}`,
+	}, request(t, conn, r, `?WATCH={"json":false,"nmea":true};`, 2))
+	assert.NoError(t, srv.Feed(strings.NewReader("$GPHDT,123.456,T*32\r\n")))
+	assert.Equal(t, "$GPHDT,123.456,T*32", readLine(t, r))
+
+	assert.Equal(t, []string{
+		`{"class":"DEVICES","devices":[{"class":"DEVICE","path":"/dev/ttyUSB0","driver":"NMEA0183","activated":"2020-01-02T03:04:05.000Z"}]}`,
+		`{"class":"WATCH","enable":true,"json":false,"nmea":true,"raw":0,"scaled":false}`,
+	}, request(t, conn, r, "?WATCH;", 2))
+}
+
+func TestServerClose(t *testing.T) {
+	srv, conn, r := serve(t)
+	readLine(t, r)
+	assert.NoError(t, srv.Close())
+	_, err := r.ReadString('\n')
+	assert.Error(t, err)
+	conn.Close()
+}
@@ -0,0 +1,156 @@
+// Package gpx converts between sentences and GPX 1.1 documents. A Recorder
+// turns a stream of position, route and waypoint sentences into tracks,
+// routes and waypoints, and a document read back can be turned into WPL
+// and RTE sentences for uploading routes to a chartplotter.
+package gpx
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/coord"
+)
+
+// Namespace of GPX 1.1 documents
+const Namespace = "http://www.topografix.com/GPX/1/1"
+
+// Fix types
+const (
+	FixNone = "none"
+	Fix2D   = "2d"
+	Fix3D   = "3d"
+	FixDGPS = "dgps"
+	FixPPS  = "pps"
+)
+
+// GPX is a GPX document.
+type GPX struct {
+	XMLName   xml.Name `xml:"http://www.topografix.com/GPX/1/1 gpx"`
+	Version   string   `xml:"version,attr"`
+	Creator   string   `xml:"creator,attr"`
+	Waypoints []Point  `xml:"wpt"`
+	Routes    []Route  `xml:"rte"`
+	Tracks    []Track  `xml:"trk"`
+}
+
+// Point is a waypoint, route point or track point.
+type Point struct {
+	Lat       float64    `xml:"lat,attr"`
+	Lon       float64    `xml:"lon,attr"`
+	Elevation *float64   `xml:"ele,omitempty"`
+	Time      *time.Time `xml:"time,omitempty"`
+	Name      string     `xml:"name,omitempty"`
+	Fix       string     `xml:"fix,omitempty"`
+	Sat       *int64     `xml:"sat,omitempty"`
+	HDOP      *float64   `xml:"hdop,omitempty"`
+}
+
+// Route is an ordered list of route points.
+type Route struct {
+	Name   string  `xml:"name,omitempty"`
+	Points []Point `xml:"rtept"`
+}
+
+// Track is an ordered list of track segments.
+type Track struct {
+	Name     string    `xml:"name,omitempty"`
+	Segments []Segment `xml:"trkseg"`
+}
+
+// Segment is a continuous span of track points.
+type Segment struct {
+	Points []Point `xml:"trkpt"`
+}
+
+// Read decodes a GPX document.
+func Read(r io.Reader) (GPX, error) {
+	var g GPX
+	err := xml.NewDecoder(r).Decode(&g)
+	return g, err
+}
+
+// Write encodes a GPX document, indented and with the XML header.
+func Write(w io.Writer, g GPX) error {
+	if g.Version == "" {
+		g.Version = "1.1"
+	}
+	if g.Creator == "" {
+		g.Creator = "go-nmea"
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(g); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Sentences formats the waypoints and routes of the document as WPL and
+// RTE sentences with the given talker. Every waypoint and route point is
+// sent as a WPL sentence, followed by the RTE sentences of each route,
+// numbered from 1 and split to fit the maximum sentence length. Points
+// without a name are named after their route and position in it.
+func (g GPX) Sentences(talker string) []string {
+	var lines []string
+	for _, p := range g.Waypoints {
+		lines = append(lines, wpl(talker, p.Name, p))
+	}
+	for i, r := range g.Routes {
+		name := r.Name
+		if name == "" {
+			name = strconv.Itoa(i + 1)
+		}
+		idents := make([]string, len(r.Points))
+		for j, p := range r.Points {
+			idents[j] = p.Name
+			if idents[j] == "" {
+				idents[j] = name + "-" + strconv.Itoa(j+1)
+			}
+			lines = append(lines, wpl(talker, idents[j], p))
+		}
+		lines = append(lines, rte(talker, name, idents)...)
+	}
+	return lines
+}
+
+func wpl(talker, ident string, p Point) string {
+	lat, latDir := coord.NMEALat(p.Lat, coord.DefaultPrecision)
+	lon, lonDir := coord.NMEALon(p.Lon, coord.DefaultPrecision)
+	return nmea.FormatSentence(nmea.SentenceStart, talker+nmea.TypeWPL, []string{lat, latDir, lon, lonDir, ident})
+}
+
+func rte(talker, name string, idents []string) []string {
+	// split the idents so every sentence fits, leaving room for the
+	// sentence counts which are at most two digits each
+	overhead := len(nmea.FormatSentence(nmea.SentenceStart, talker+nmea.TypeRTE,
+		[]string{"99", "99", nmea.WaypointList, name}))
+	var parts [][]string
+	var part []string
+	length := overhead
+	for _, ident := range idents {
+		if len(part) > 0 && length+1+len(ident)+len(nmea.CRLF) > nmea.MaxSentenceLength {
+			parts = append(parts, part)
+			part, length = nil, overhead
+		}
+		part = append(part, ident)
+		length += 1 + len(ident)
+	}
+	if len(part) > 0 || len(parts) == 0 {
+		parts = append(parts, part)
+	}
+
+	total := strconv.Itoa(len(parts))
+	lines := make([]string, len(parts))
+	for i, part := range parts {
+		fields := append([]string{total, strconv.Itoa(i + 1), nmea.WaypointList, name}, part...)
+		lines[i] = nmea.FormatSentence(nmea.SentenceStart, talker+nmea.TypeRTE, fields)
+	}
+	return lines
+}
@@ -0,0 +1,108 @@
+package gpx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+const document = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx xmlns="http://www.topografix.com/GPX/1/1" version="1.1" creator="test">
+  <wpt lat="51.477" lon="-0.4597">
+    <name>EGLL</name>
+  </wpt>
+  <rte>
+    <name>Channel</name>
+    <rtept lat="51.477" lon="-0.4597"><name>EGLL</name></rtept>
+    <rtept lat="49.0097" lon="2.55"></rtept>
+  </rte>
+  <trk>
+    <trkseg>
+      <trkpt lat="51.477" lon="-0.4597"><ele>25.5</ele><time>2020-03-15T12:00:00Z</time><fix>3d</fix><sat>8</sat></trkpt>
+    </trkseg>
+  </trk>
+</gpx>
+`
+
+func TestRead(t *testing.T) {
+	g, err := Read(strings.NewReader(document))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "test", g.Creator)
+	assert.Equal(t, []Point{{Lat: 51.477, Lon: -0.4597, Name: "EGLL"}}, g.Waypoints)
+	assert.Len(t, g.Routes, 1)
+	assert.Len(t, g.Routes[0].Points, 2)
+	p := g.Tracks[0].Segments[0].Points[0]
+	assert.Equal(t, 25.5, *p.Elevation)
+	assert.Equal(t, time.Date(2020, 3, 15, 12, 0, 0, 0, time.UTC), *p.Time)
+	assert.Equal(t, int64(8), *p.Sat)
+	assert.Nil(t, p.HDOP)
+}
+
+func TestWriteRead(t *testing.T) {
+	ele := 10.0
+	ts := time.Date(2020, 3, 15, 12, 0, 0, 0, time.UTC)
+	g := GPX{
+		Waypoints: []Point{{Lat: 1, Lon: 2, Name: "A"}},
+		Tracks: []Track{{Segments: []Segment{{Points: []Point{
+			{Lat: 1, Lon: 2, Elevation: &ele, Time: &ts, Fix: FixDGPS},
+		}}}}},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, g))
+	assert.True(t, strings.HasPrefix(buf.String(), `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<gpx xmlns="http://www.topografix.com/GPX/1/1" version="1.1" creator="go-nmea">`))
+	assert.Contains(t, buf.String(), "<time>2020-03-15T12:00:00Z</time>")
+
+	read, err := Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, g.Waypoints, read.Waypoints)
+	assert.Equal(t, g.Tracks, read.Tracks)
+}
+
+func TestSentences(t *testing.T) {
+	g, err := Read(strings.NewReader(document))
+	if !assert.NoError(t, err) {
+		return
+	}
+	lines := g.Sentences("GP")
+	assert.Equal(t, []string{
+		"$GPWPL,5128.6200,N,00027.5820,W,EGLL*5B",
+		"$GPWPL,5128.6200,N,00027.5820,W,EGLL*5B",
+		"$GPWPL,4900.5820,N,00233.0000,E,Channel-2*17",
+		"$GPRTE,1,1,w,Channel,EGLL,Channel-2*3E",
+	}, lines)
+	for _, line := range lines {
+		_, err := nmea.Parse(line)
+		assert.NoError(t, err)
+	}
+}
+
+func TestSentencesSplit(t *testing.T) {
+	var r Route
+	for i := 0; i < 20; i++ {
+		r.Points = append(r.Points, Point{Name: "WAYPT" + string(rune('A'+i))})
+	}
+	lines := GPX{Routes: []Route{r}}.Sentences("GP")
+	var idents []string
+	var rtes int
+	for _, line := range lines {
+		s, err := nmea.Parse(line)
+		assert.NoError(t, err)
+		if rte, ok := s.(nmea.RTE); ok {
+			rtes++
+			assert.True(t, len(line)+2 <= nmea.MaxSentenceLength)
+			assert.Equal(t, int64(rtes), rte.SentenceNumber)
+			assert.Equal(t, "1", rte.Name)
+			idents = append(idents, rte.Idents...)
+		}
+	}
+	assert.Equal(t, 3, rtes)
+	assert.Len(t, idents, 20)
+	assert.Equal(t, "WAYPTT", idents[19])
+}
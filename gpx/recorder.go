@@ -0,0 +1,192 @@
+package gpx
+
+import (
+	"sync"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/route"
+)
+
+// Recorder builds a GPX document from a stream of sentences. RMC, GGA, GNS
+// and GLL sentences with a valid fix become track points; sentences sharing
+// the same time of day are merged into a single point, so an RMC and GGA
+// pair yields one point with both the date and the elevation. Routes are
+// assembled from RTE and WPL sentences and every WPL sentence is kept as a
+// waypoint.
+type Recorder struct {
+	Name string // Name of the recorded track
+
+	mu        sync.Mutex
+	dates     *nmea.DateTracker
+	routes    *route.Assembler
+	waypoints []string
+	segments  []Segment
+	last      nmea.Time
+}
+
+// NewRecorder constructor
+func NewRecorder() *Recorder {
+	return &Recorder{
+		dates:    nmea.NewDateTracker(),
+		routes:   route.NewAssembler(),
+		segments: []Segment{{}},
+	}
+}
+
+// Add records a sentence.
+func (r *Recorder) Add(s nmea.Sentence) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if w, ok := s.(nmea.WPL); ok {
+		if _, seen := r.routes.Waypoint(w.Ident); !seen {
+			r.waypoints = append(r.waypoints, w.Ident)
+		}
+	}
+	r.routes.Add(s)
+
+	t, dated := r.dates.Update(s)
+	var p Point
+	var tod nmea.Time
+	switch m := s.(type) {
+	case nmea.RMC:
+		if m.Validity != nmea.ValidRMC {
+			return
+		}
+		p = Point{Lat: m.Latitude, Lon: m.Longitude}
+		tod = m.Time
+	case nmea.GGA:
+		if m.FixQuality == nmea.Invalid {
+			return
+		}
+		p = Point{Lat: m.Latitude, Lon: m.Longitude, Fix: ggaFix(m.FixQuality)}
+		sat := m.NumSatellites
+		p.Sat = &sat
+		if m.HDOPValid {
+			hdop := m.HDOP
+			p.HDOP = &hdop
+		}
+		if m.AltitudeValid {
+			ele := m.Altitude
+			p.Elevation = &ele
+		}
+		tod = m.Time
+	case nmea.GNS:
+		fix := gnsFix(m.Mode)
+		if fix == FixNone {
+			return
+		}
+		sat, hdop, ele := m.SVs, m.HDOP, m.Altitude
+		p = Point{Lat: m.Latitude, Lon: m.Longitude, Fix: fix, Sat: &sat, HDOP: &hdop, Elevation: &ele}
+		tod = m.Time
+	case nmea.GLL:
+		if m.Validity != nmea.ValidGLL {
+			return
+		}
+		p = Point{Lat: m.Latitude, Lon: m.Longitude}
+		tod = m.Time
+	default:
+		return
+	}
+	if dated {
+		t = t.UTC()
+		p.Time = &t
+	}
+
+	seg := &r.segments[len(r.segments)-1]
+	if n := len(seg.Points); n > 0 && tod.Valid && tod == r.last {
+		merge(&seg.Points[n-1], p)
+		return
+	}
+	seg.Points = append(seg.Points, p)
+	r.last = tod
+}
+
+// NewSegment starts a new track segment, e.g. after a loss of fix.
+func (r *Recorder) NewSegment() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.segments[len(r.segments)-1].Points) > 0 {
+		r.segments = append(r.segments, Segment{})
+	}
+	r.last = nmea.Time{}
+}
+
+// GPX returns the document recorded so far.
+func (r *Recorder) GPX() GPX {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g := GPX{Version: "1.1", Creator: "go-nmea"}
+	for _, ident := range r.waypoints {
+		w, _ := r.routes.Waypoint(ident)
+		g.Waypoints = append(g.Waypoints, Point{Lat: w.Lat, Lon: w.Lon, Name: w.ID})
+	}
+	for _, rt := range r.routes.Routes() {
+		gr := Route{Name: rt.Name}
+		for _, w := range rt.Waypoints {
+			gr.Points = append(gr.Points, Point{Lat: w.Lat, Lon: w.Lon, Name: w.ID})
+		}
+		g.Routes = append(g.Routes, gr)
+	}
+	var segments []Segment
+	for _, seg := range r.segments {
+		if len(seg.Points) > 0 {
+			segments = append(segments, Segment{Points: append([]Point(nil), seg.Points...)})
+		}
+	}
+	if len(segments) > 0 {
+		g.Tracks = []Track{{Name: r.Name, Segments: segments}}
+	}
+	return g
+}
+
+// merge fills the fields of a point missing from the other point.
+func merge(dst *Point, src Point) {
+	if dst.Elevation == nil {
+		dst.Elevation = src.Elevation
+	}
+	if dst.Time == nil {
+		dst.Time = src.Time
+	}
+	if dst.Fix == "" {
+		dst.Fix = src.Fix
+	}
+	if dst.Sat == nil {
+		dst.Sat = src.Sat
+	}
+	if dst.HDOP == nil {
+		dst.HDOP = src.HDOP
+	}
+}
+
+func ggaFix(quality string) string {
+	switch quality {
+	case nmea.DGPS, nmea.RTK, nmea.FRTK:
+		return FixDGPS
+	case nmea.PPS:
+		return FixPPS
+	case nmea.GPS, nmea.EST:
+		return Fix3D
+	}
+	return FixNone
+}
+
+// gnsFix returns the best fix of the systems in the mode.
+func gnsFix(modes []string) string {
+	fix := FixNone
+	for _, m := range modes {
+		switch m {
+		case nmea.DifferentialGNS, nmea.RealTimeKinematicGNS, nmea.FloatRTKGNS:
+			return FixDGPS
+		case nmea.PreciseGNS:
+			fix = FixPPS
+		case nmea.AutonomousGNS, nmea.EstimatedGNS:
+			if fix == FixNone {
+				fix = Fix3D
+			}
+		}
+	}
+	return fix
+}
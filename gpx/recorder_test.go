@@ -0,0 +1,80 @@
+package gpx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+func record(t *testing.T, r *Recorder, lines ...string) {
+	for _, line := range lines {
+		s, err := nmea.Parse(line)
+		if !assert.NoError(t, err, line) {
+			return
+		}
+		r.Add(s)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	r.Name = "test"
+	record(t, r,
+		"$GPWPL,5128.62,N,00027.58,W,EGLL*59",
+		"$GPWPL,4900.58,N,00233.00,E,LFPG*54",
+		"$GPRTE,1,1,c,0,EGLL,LFPG*18",
+		"$GPRMC,120000,A,5128.62,N,00027.58,W,5.0,90.0,150320,,*37",
+		"$GPGGA,120000,5128.62,N,00027.58,W,2,08,0.9,25.5,M,47.0,M,,*62",
+		"$GPGGA,120001,5128.62,N,00027.50,W,1,07,1.1,26.0,M,47.0,M,,*68",
+		"$GPGLL,5128.62,N,00027.40,W,120002,V*25",
+	)
+	r.NewSegment()
+	record(t, r, "$GNGNS,120003,5128.62,N,00027.30,W,AN,09,1.0,27.0,47.0,,*59")
+
+	g := r.GPX()
+	assert.Len(t, g.Waypoints, 2)
+	assert.Equal(t, "EGLL", g.Waypoints[0].Name)
+
+	assert.Len(t, g.Routes, 1)
+	assert.Equal(t, "0", g.Routes[0].Name)
+	assert.Len(t, g.Routes[0].Points, 2)
+	assert.Equal(t, "LFPG", g.Routes[0].Points[1].Name)
+
+	if !assert.Len(t, g.Tracks, 1) || !assert.Len(t, g.Tracks[0].Segments, 2) {
+		return
+	}
+	assert.Equal(t, "test", g.Tracks[0].Name)
+	seg := g.Tracks[0].Segments[0]
+	if !assert.Len(t, seg.Points, 2) {
+		return
+	}
+	// RMC and GGA of the same fix are merged
+	p := seg.Points[0]
+	assert.Equal(t, time.Date(2020, 3, 15, 12, 0, 0, 0, time.UTC), *p.Time)
+	assert.Equal(t, 25.5, *p.Elevation)
+	assert.Equal(t, int64(8), *p.Sat)
+	assert.Equal(t, 0.9, *p.HDOP)
+	assert.Equal(t, FixDGPS, p.Fix)
+
+	// the date of the GGA is inferred from the RMC
+	p = seg.Points[1]
+	assert.Equal(t, time.Date(2020, 3, 15, 12, 0, 1, 0, time.UTC), *p.Time)
+	assert.Equal(t, Fix3D, p.Fix)
+
+	p = g.Tracks[0].Segments[1].Points[0]
+	assert.Equal(t, Fix3D, p.Fix)
+	assert.Equal(t, 27.0, *p.Elevation)
+}
+
+func TestRecorderEmpty(t *testing.T) {
+	r := NewRecorder()
+	record(t, r, "$GPGLL,5128.62,N,00027.40,W,120002,V*25")
+	r.NewSegment()
+	g := r.GPX()
+	assert.Empty(t, g.Tracks)
+	assert.Empty(t, g.Routes)
+	assert.Empty(t, g.Waypoints)
+}
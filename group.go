@@ -0,0 +1,152 @@
+package nmea
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Group is a set of sentences bound together by the tag block -g parameter,
+// e.g. the lines of a multi-line AIS message and its companion sentences.
+type Group struct {
+	Source    string     // Tag block source of the sentences
+	ID        int64      // Group identification
+	Total     int64      // Total number of sentences in the group
+	Sentences []Sentence // Received sentences, ordered by their number
+}
+
+// Complete reports whether all sentences of the group were received.
+func (g Group) Complete() bool {
+	return int64(len(g.Sentences)) == g.Total
+}
+
+type groupKey struct {
+	source string
+	id     int64
+}
+
+type pendingGroup struct {
+	group   Group
+	slots   []Sentence
+	started time.Time
+}
+
+// GroupAssembler collects the sentences of tag block groups so they can be
+// handled as a whole. Groups are identified by their source and group id.
+type GroupAssembler struct {
+	Timeout time.Duration // Time after which incomplete groups expire, 0 disables
+
+	mu      sync.Mutex
+	pending map[groupKey]*pendingGroup
+	now     func() time.Time
+}
+
+// NewGroupAssembler constructor
+func NewGroupAssembler(timeout time.Duration) *GroupAssembler {
+	return &GroupAssembler{
+		Timeout: timeout,
+		pending: map[groupKey]*pendingGroup{},
+		now:     time.Now,
+	}
+}
+
+// Add adds a sentence to its group. When the sentence completes the group,
+// the group is returned along with true. Sentences without a valid tag block
+// grouping are returned immediately as a group of one. Incomplete groups are
+// kept until they complete or are removed by Expire or Flush, which should
+// be called periodically.
+func (a *GroupAssembler) Add(s Sentence) (Group, bool) {
+	base, _ := BaseSentenceOf(s)
+	tags := base.TagBlock
+	if !tags.Group.Valid() {
+		return Group{Source: tags.Source, Total: 1, Sentences: []Sentence{s}}, true
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := groupKey{tags.Source, tags.Group.ID}
+	p, ok := a.pending[key]
+	index := tags.Group.Number - 1
+	// A group id is reused once the previous group is finished, so a
+	// mismatching total or an already received number starts a new group.
+	if ok && (p.group.Total != tags.Group.Total || p.slots[index] != nil) {
+		ok = false
+	}
+	if !ok {
+		p = &pendingGroup{
+			group: Group{
+				Source: tags.Source,
+				ID:     tags.Group.ID,
+				Total:  tags.Group.Total,
+			},
+			slots:   make([]Sentence, tags.Group.Total),
+			started: a.now(),
+		}
+		a.pending[key] = p
+	}
+	p.slots[index] = s
+
+	g := p.collect()
+	if !g.Complete() {
+		return Group{}, false
+	}
+	delete(a.pending, key)
+	return g, true
+}
+
+// Expire removes and returns the incomplete groups which have been pending
+// for longer than the timeout. Groups never expire with a zero timeout.
+func (a *GroupAssembler) Expire() []Group {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.Timeout <= 0 {
+		return nil
+	}
+	now := a.now()
+	var expired []Group
+	for key, p := range a.pending {
+		if now.Sub(p.started) >= a.Timeout {
+			expired = append(expired, p.collect())
+			delete(a.pending, key)
+		}
+	}
+	sortGroups(expired)
+	return expired
+}
+
+// Flush removes and returns all incomplete groups.
+func (a *GroupAssembler) Flush() []Group {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var groups []Group
+	for key, p := range a.pending {
+		groups = append(groups, p.collect())
+		delete(a.pending, key)
+	}
+	sortGroups(groups)
+	return groups
+}
+
+// collect returns the group with the sentences received so far.
+func (p *pendingGroup) collect() Group {
+	g := p.group
+	g.Sentences = nil
+	for _, s := range p.slots {
+		if s != nil {
+			g.Sentences = append(g.Sentences, s)
+		}
+	}
+	return g
+}
+
+func sortGroups(groups []Group) {
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Source != groups[j].Source {
+			return groups[i].Source < groups[j].Source
+		}
+		return groups[i].ID < groups[j].ID
+	})
+}
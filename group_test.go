@@ -0,0 +1,169 @@
+package nmea
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func groupSentence(t *testing.T, source string, group SentenceGroup, raw string) Sentence {
+	s, err := Parse(TagBlock{Source: source, Group: group}.String() + raw)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestSentenceGroupParsing(t *testing.T) {
+	tags, err := parseTagBlock("g:1-3-1234,s:r003669945*7E")
+	assert.NoError(t, err)
+	assert.Equal(t, "1-3-1234", tags.Grouping)
+	assert.Equal(t, SentenceGroup{Number: 1, Total: 3, ID: 1234}, tags.Group)
+	assert.True(t, tags.Group.Valid())
+
+	tags, err = parseTagBlock("g:bulk*4D")
+	assert.NoError(t, err)
+	assert.Equal(t, "bulk", tags.Grouping)
+	assert.False(t, tags.Group.Valid())
+
+	assert.Equal(t, "\\g:2-3-1234*58\\", TagBlock{Group: SentenceGroup{2, 3, 1234}}.String())
+	assert.Equal(t, "\\g:2-3-1234*58\\", TagBlock{Grouping: "1-3-1234", Group: SentenceGroup{2, 3, 1234}}.String())
+}
+
+func TestSentenceGroupOutOfRange(t *testing.T) {
+	var tests = []struct {
+		name string
+		raw  string
+	}{
+		{name: "oversized total", raw: "g:1-9223372036854775807-1*67"},
+		{name: "total above maximum", raw: "g:1-100-1*6C"},
+		{name: "number above total", raw: "g:3-2-1*6D"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := parseTagBlock(tt.raw)
+			assert.NoError(t, err)
+			assert.Equal(t, SentenceGroup{}, tags.Group)
+			assert.False(t, tags.Group.Valid())
+
+			s, err := Parse("\\" + tt.raw + "\\$GPHDT,123.456,T*32")
+			if !assert.NoError(t, err) {
+				return
+			}
+			g, ok := NewGroupAssembler(time.Second).Add(s)
+			assert.True(t, ok)
+			assert.Equal(t, []Sentence{s}, g.Sentences)
+		})
+	}
+}
+
+func TestGroupAssembler(t *testing.T) {
+	a := NewGroupAssembler(time.Second)
+
+	first := groupSentence(t, "r1", SentenceGroup{1, 2, 42}, "$GPHDT,123.456,T*32")
+	second := groupSentence(t, "r1", SentenceGroup{2, 2, 42}, "$IIDBT,032.93,f,010.04,M,005.42,F*2C")
+	other := groupSentence(t, "r2", SentenceGroup{2, 2, 42}, "$GPHDT,123.456,T*32")
+
+	_, ok := a.Add(second)
+	assert.False(t, ok)
+	_, ok = a.Add(other)
+	assert.False(t, ok)
+
+	g, ok := a.Add(first)
+	assert.True(t, ok)
+	assert.True(t, g.Complete())
+	assert.Equal(t, "r1", g.Source)
+	assert.Equal(t, int64(42), g.ID)
+	assert.Equal(t, []Sentence{first, second}, g.Sentences)
+
+	assert.Equal(t, []Group{{Source: "r2", ID: 42, Total: 2, Sentences: []Sentence{other}}}, a.Flush())
+	assert.Empty(t, a.Flush())
+}
+
+func TestGroupAssemblerUngrouped(t *testing.T) {
+	a := NewGroupAssembler(time.Second)
+	s := groupSentence(t, "", SentenceGroup{}, "$GPHDT,123.456,T*32")
+	g, ok := a.Add(s)
+	assert.True(t, ok)
+	assert.True(t, g.Complete())
+	assert.Equal(t, []Sentence{s}, g.Sentences)
+}
+
+func TestGroupAssemblerExpire(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewGroupAssembler(time.Second)
+	a.now = func() time.Time { return now }
+
+	first := groupSentence(t, "r1", SentenceGroup{1, 3, 7}, "$GPHDT,123.456,T*32")
+	_, ok := a.Add(first)
+	assert.False(t, ok)
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Empty(t, a.Expire())
+
+	now = now.Add(500 * time.Millisecond)
+	expired := a.Expire()
+	assert.Len(t, expired, 1)
+	assert.False(t, expired[0].Complete())
+	assert.Equal(t, []Sentence{first}, expired[0].Sentences)
+}
+
+func TestGroupAssemblerKeepsStaleGroups(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewGroupAssembler(time.Second)
+	a.now = func() time.Time { return now }
+
+	first := groupSentence(t, "r1", SentenceGroup{1, 2, 7}, "$GPHDT,123.456,T*32")
+	other := groupSentence(t, "r2", SentenceGroup{1, 2, 8}, "$GPHDT,123.456,T*32")
+	_, ok := a.Add(first)
+	assert.False(t, ok)
+
+	// Add leaves stale groups to Expire
+	now = now.Add(2 * time.Second)
+	_, ok = a.Add(other)
+	assert.False(t, ok)
+	expired := a.Expire()
+	assert.Len(t, expired, 1)
+	assert.Equal(t, []Sentence{first}, expired[0].Sentences)
+}
+
+func TestGroupAssemblerNoTimeout(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewGroupAssembler(0)
+	a.now = func() time.Time { return now }
+
+	first := groupSentence(t, "r1", SentenceGroup{1, 2, 7}, "$GPHDT,123.456,T*32")
+	second := groupSentence(t, "r1", SentenceGroup{2, 2, 7}, "$GPHDT,123.456,T*32")
+	_, ok := a.Add(first)
+	assert.False(t, ok)
+	now = now.Add(time.Hour)
+	assert.Empty(t, a.Expire())
+	g, ok := a.Add(second)
+	assert.True(t, ok)
+	assert.Equal(t, []Sentence{first, second}, g.Sentences)
+}
+
+func TestGroupAssemblerRestart(t *testing.T) {
+	a := NewGroupAssembler(time.Second)
+
+	stale := groupSentence(t, "r1", SentenceGroup{1, 2, 9}, "$GPHDT,123.456,T*32")
+	first := groupSentence(t, "r1", SentenceGroup{1, 2, 9}, "$IIDBT,032.93,f,010.04,M,005.42,F*2C")
+	second := groupSentence(t, "r1", SentenceGroup{2, 2, 9}, "$GPHDT,123.456,T*32")
+
+	_, ok := a.Add(stale)
+	assert.False(t, ok)
+	_, ok = a.Add(first)
+	assert.False(t, ok)
+	g, ok := a.Add(second)
+	assert.True(t, ok)
+	assert.Equal(t, []Sentence{first, second}, g.Sentences)
+}
+
+func TestBaseSentenceOf(t *testing.T) {
+	s := groupSentence(t, "", SentenceGroup{}, "$GPHDT,123.456,T*32")
+	base, ok := BaseSentenceOf(s)
+	assert.True(t, ok)
+	assert.Equal(t, "GPHDT", base.Prefix())
+
+	_, ok = BaseSentenceOf(nil)
+	assert.False(t, ok)
+}
@@ -48,3 +48,13 @@ func newGSA(s BaseSentence) (GSA, error) {
 	m.VDOP = p.Float64(16, "vdop")
 	return m, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s GSA) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *GSA) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
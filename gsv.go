@@ -21,6 +21,7 @@ type GSVInfo struct {
 	Elevation   int64 // Elevation in degrees, 90 maximum
 	Azimuth     int64 // Azimuth, degrees from true north, 000 to 359
 	SNR         int64 // SNR, 00-99 dB (null when not tracking)
+	SNRValid    bool  // SNR is present, false when not tracking
 }
 
 // newGSV constructor
@@ -37,12 +38,24 @@ func newGSV(s BaseSentence) (GSV, error) {
 		if 5*i+4 > len(m.Fields) {
 			break
 		}
-		m.Info = append(m.Info, GSVInfo{
+		info := GSVInfo{
 			SVPRNNumber: p.Int64(3+i*4, "SV prn number"),
 			Elevation:   p.Int64(4+i*4, "elevation"),
 			Azimuth:     p.Int64(5+i*4, "azimuth"),
-			SNR:         p.Int64(6+i*4, "SNR"),
-		})
+		}
+		snr := p.NullInt64(6+i*4, "SNR")
+		info.SNR, info.SNRValid = snr.Value, snr.Valid
+		m.Info = append(m.Info, info)
 	}
 	return m, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s GSV) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *GSV) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
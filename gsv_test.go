@@ -20,24 +20,37 @@ var gsvtests = []struct {
 			MessageNumber:   1,
 			NumberSVsInView: 11,
 			Info: []GSVInfo{
-				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0},
-				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0},
-				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12},
-				{SVPRNNumber: 13, Elevation: 6, Azimuth: 292, SNR: 0},
+				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12, SNRValid: true},
+				{SVPRNNumber: 13, Elevation: 6, Azimuth: 292, SNR: 0, SNRValid: true},
 			},
 		},
 	},
 	{
 		name: "short sentence",
 		raw:  "$GLGSV,3,1,11,03,03,111,00,04,15,270,00,06,01,010,12*56",
+		msg: GSV{
+			TotalMessages:   3,
+			MessageNumber:   1,
+			NumberSVsInView: 11,
+			Info: []GSVInfo{
+				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12, SNRValid: true},
+			},
+		},
+	},
+	{
+		name: "satellite not tracked",
+		raw:  "$GPGSV,3,1,11,03,03,111,,04,15,270,00*7F",
 		msg: GSV{
 			TotalMessages:   3,
 			MessageNumber:   1,
 			NumberSVsInView: 11,
 			Info: []GSVInfo{
 				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0},
-				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0},
-				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12},
+				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0, SNRValid: true},
 			},
 		},
 	},
@@ -84,10 +97,10 @@ var gsvtests = []struct {
 			MessageNumber:   1,
 			NumberSVsInView: 11,
 			Info: []GSVInfo{
-				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0},
-				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0},
-				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12},
-				{SVPRNNumber: 13, Elevation: 6, Azimuth: 292, SNR: 0},
+				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12, SNRValid: true},
+				{SVPRNNumber: 13, Elevation: 6, Azimuth: 292, SNR: 0, SNRValid: true},
 			},
 		},
 	},
@@ -99,9 +112,9 @@ var gsvtests = []struct {
 			MessageNumber:   1,
 			NumberSVsInView: 11,
 			Info: []GSVInfo{
-				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0},
-				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0},
-				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12},
+				{SVPRNNumber: 3, Elevation: 3, Azimuth: 111, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 4, Elevation: 15, Azimuth: 270, SNR: 0, SNRValid: true},
+				{SVPRNNumber: 6, Elevation: 1, Azimuth: 10, SNR: 12, SNRValid: true},
 			},
 		},
 	},
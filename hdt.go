@@ -24,3 +24,13 @@ func newHDT(s BaseSentence) (HDT, error) {
 	}
 	return m, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s HDT) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *HDT) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
@@ -0,0 +1,280 @@
+// Package iec450 implements the IEC 61162-450 transport of NMEA sentences
+// over UDP multicast.
+//
+// Every datagram starts with the "UdPbC" header followed by one or more
+// sentences, each one prefixed with a tag block holding at least the
+// source (s:) and line count (n:) parameters.
+package iec450
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+const (
+	// Header is the token starting every datagram carrying sentences.
+	Header = "UdPbC\x00"
+
+	// MaxLineCount is the largest line count, after which it wraps to 1.
+	MaxLineCount = 999
+
+	// MaxDatagramSize is the largest datagram allowed by the standard.
+	MaxDatagramSize = 1472
+
+	// maxLineGap is the largest step between line counts taken as lost or
+	// out of order lines. Larger steps are taken as a restarted source.
+	maxLineGap = 100
+
+	lineSep = "\r\n"
+)
+
+// Multicast groups defined by the standard for the different traffic types.
+const (
+	GroupMISC = "239.192.0.1:60001" // Miscellaneous
+	GroupTGTD = "239.192.0.2:60002" // Target data (AIS, radar tracked targets)
+	GroupSATD = "239.192.0.3:60003" // High update rate data (heading, rate of turn)
+	GroupNAVD = "239.192.0.4:60004" // Navigation data
+	GroupVDRD = "239.192.0.5:60005" // Voyage data recorder
+	GroupRCOM = "239.192.0.6:60006" // Radar communications
+	GroupTIME = "239.192.0.7:60007" // Time sources
+	GroupPROP = "239.192.0.8:60008" // Proprietary messages
+)
+
+// ErrNoHeader is returned when a datagram does not start with Header.
+var ErrNoHeader = errors.New("iec450: datagram does not start with UdPbC header")
+
+// Decode parses the sentences of a datagram. Lines which fail to parse are
+// skipped and the first error is returned along with the parsed sentences.
+func Decode(datagram []byte) ([]nmea.Sentence, error) {
+	if !bytes.HasPrefix(datagram, []byte(Header)) {
+		return nil, ErrNoHeader
+	}
+	var (
+		sentences []nmea.Sentence
+		firstErr  error
+	)
+	for _, line := range splitLines(datagram[len(Header):]) {
+		s, err := nmea.Parse(line)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		sentences = append(sentences, s)
+	}
+	return sentences, firstErr
+}
+
+// Encode wraps the sentences into a datagram. The source and line count
+// tags must already be set on the sentences.
+func Encode(lines ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(Header)
+	for _, line := range lines {
+		buf.WriteString(line)
+		buf.WriteString(lineSep)
+	}
+	return buf.Bytes()
+}
+
+func splitLines(payload []byte) []string {
+	var lines []string
+	for _, line := range strings.Split(string(payload), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Stats holds the counters of a single source.
+type Stats struct {
+	Received   int64 // Number of lines received
+	Lost       int64 // Number of lines missing according to the line count
+	OutOfOrder int64 // Number of lines repeating or preceding the last line count
+}
+
+// Listener receives datagrams and decodes their sentences.
+type Listener struct {
+	conn net.PacketConn
+	buf  []byte
+
+	mu        sync.Mutex
+	lineCount map[string]int64
+	stats     map[string]Stats
+}
+
+// Listen joins the multicast group of address on the given interface. A
+// nil interface uses the system default. If address is not a multicast
+// address a regular UDP listener is used.
+func Listen(address string, ifi *net.Interface) (*Listener, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	var conn *net.UDPConn
+	if addr.IP != nil && addr.IP.IsMulticast() {
+		conn, err = net.ListenMulticastUDP("udp", ifi, addr)
+	} else {
+		conn, err = net.ListenUDP("udp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadBuffer(MaxDatagramSize * 64)
+	return NewListener(conn), nil
+}
+
+// NewListener constructor
+func NewListener(conn net.PacketConn) *Listener {
+	return &Listener{
+		conn:      conn,
+		buf:       make([]byte, 64*1024),
+		lineCount: map[string]int64{},
+		stats:     map[string]Stats{},
+	}
+}
+
+// Addr returns the local address of the listener.
+func (l *Listener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
+
+// Read waits for the next datagram and returns its sentences. Datagrams
+// without the header are ignored. Parse errors are returned along with
+// the sentences which could be parsed. Read reuses its buffer and must not
+// be called concurrently.
+func (l *Listener) Read() ([]nmea.Sentence, error) {
+	for {
+		n, _, err := l.conn.ReadFrom(l.buf)
+		if err != nil {
+			return nil, err
+		}
+		datagram := l.buf[:n]
+		if !bytes.HasPrefix(datagram, []byte(Header)) {
+			continue
+		}
+		l.count(datagram[len(Header):])
+		return Decode(datagram)
+	}
+}
+
+// count updates the line counters of every source in the payload.
+func (l *Listener) count(payload []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, line := range splitLines(payload) {
+		tags, _, err := nmea.SplitTagBlock(line)
+		if err != nil || tags.Source == "" {
+			continue
+		}
+		stats := l.stats[tags.Source]
+		stats.Received++
+		if last, ok := l.lineCount[tags.Source]; ok && tags.LineCount != 0 {
+			ahead := (tags.LineCount - last + MaxLineCount) % MaxLineCount
+			switch {
+			case ahead == 0 || ahead > MaxLineCount-maxLineGap:
+				// Repeated or behind the last line, keep the last count.
+				stats.OutOfOrder++
+				l.stats[tags.Source] = stats
+				continue
+			case ahead <= maxLineGap:
+				stats.Lost += ahead - 1
+			}
+		}
+		if tags.LineCount != 0 {
+			l.lineCount[tags.Source] = tags.LineCount
+		}
+		l.stats[tags.Source] = stats
+	}
+}
+
+// Stats returns the counters of every source seen so far.
+func (l *Listener) Stats() map[string]Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := make(map[string]Stats, len(l.stats))
+	for k, v := range l.stats {
+		stats[k] = v
+	}
+	return stats
+}
+
+// Close closes the underlying connection.
+func (l *Listener) Close() error {
+	return l.conn.Close()
+}
+
+// Transmitter sends sentences wrapped into datagrams.
+type Transmitter struct {
+	Source string // Source identification, e.g. GP0001
+
+	w         io.Writer
+	mu        sync.Mutex
+	lineCount int64
+}
+
+// Dial creates a transmitter sending to the given address.
+func Dial(address, source string) (*Transmitter, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransmitter(conn, source), nil
+}
+
+// NewTransmitter constructor. Every write on w must result in one datagram.
+func NewTransmitter(w io.Writer, source string) *Transmitter {
+	return &Transmitter{Source: source, w: w}
+}
+
+// Send sends the sentences in a single datagram. The source and line count
+// of the tag block are set, other tag block parameters are preserved.
+func (t *Transmitter) Send(sentences ...nmea.Sentence) error {
+	if len(sentences) == 0 {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lineCount := t.lineCount
+	lines := make([]string, len(sentences))
+	for i, s := range sentences {
+		base, ok := nmea.BaseSentenceOf(s)
+		if !ok {
+			return fmt.Errorf("iec450: unsupported sentence %T", s)
+		}
+		lineCount = lineCount%MaxLineCount + 1
+		tags := base.TagBlock
+		tags.Source = t.Source
+		tags.LineCount = lineCount
+		base.SetTagBlock(tags)
+		lines[i] = base.Line()
+	}
+	datagram := Encode(lines...)
+	if len(datagram) > MaxDatagramSize {
+		return fmt.Errorf("iec450: datagram exceeds %d bytes", MaxDatagramSize)
+	}
+	t.lineCount = lineCount
+	_, err := t.w.Write(datagram)
+	return err
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (t *Transmitter) Close() error {
+	if c, ok := t.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
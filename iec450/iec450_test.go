@@ -0,0 +1,155 @@
+package iec450
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+func mustParse(t *testing.T, raw string) nmea.Sentence {
+	s, err := nmea.Parse(raw)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestDecode(t *testing.T) {
+	datagram := []byte("UdPbC\x00\\s:GP0001,n:1*16\\$GPHDT,123.456,T*32\r\n" +
+		"\\s:GP0001,n:2*15\\$IIDBT,032.93,f,010.04,M,005.42,F*2C\r\n")
+	sentences, err := Decode(datagram)
+	assert.NoError(t, err)
+	assert.Len(t, sentences, 2)
+
+	hdt := sentences[0].(nmea.HDT)
+	assert.Equal(t, 123.456, hdt.Heading)
+	assert.Equal(t, nmea.TagBlock{Source: "GP0001", LineCount: 1}, hdt.TagBlock)
+	assert.Equal(t, "DBT", sentences[1].DataType())
+
+	_, err = Decode([]byte("$GPHDT,123.456,T*32\r\n"))
+	assert.Equal(t, ErrNoHeader, err)
+
+	sentences, err = Decode([]byte("UdPbC\x00$GPHDT,123.456,T*00\r\n$GPHDT,123.456,T*32\r\n"))
+	assert.EqualError(t, err, "nmea: sentence checksum mismatch [32 != 00]")
+	assert.Len(t, sentences, 1)
+}
+
+func TestTransmitter(t *testing.T) {
+	var buf bytes.Buffer
+	tx := NewTransmitter(&buf, "GP0001")
+
+	hdt := mustParse(t, "\\c:1553390539*5E\\$GPHDT,123.456,T*32")
+	assert.NoError(t, tx.Send(hdt, mustParse(t, "$IIDBT,032.93,f,010.04,M,005.42,F*2C")))
+	assert.Equal(t, "UdPbC\x00"+
+		"\\c:1553390539,n:1,s:GP0001*64\\$GPHDT,123.456,T*32\r\n"+
+		"\\n:2,s:GP0001*15\\$IIDBT,032.93,f,010.04,M,005.42,F*2C\r\n", buf.String())
+
+	tx.lineCount = MaxLineCount
+	buf.Reset()
+	assert.NoError(t, tx.Send(hdt))
+	assert.Contains(t, buf.String(), "n:1,")
+}
+
+func TestTransmitterOversized(t *testing.T) {
+	var buf bytes.Buffer
+	tx := NewTransmitter(&buf, "GP0001")
+
+	hdt := mustParse(t, "$GPHDT,123.456,T*32")
+	sentences := make([]nmea.Sentence, 50)
+	for i := range sentences {
+		sentences[i] = hdt
+	}
+	assert.EqualError(t, tx.Send(sentences...), "iec450: datagram exceeds 1472 bytes")
+	assert.Equal(t, 0, buf.Len())
+
+	assert.NoError(t, tx.Send(hdt))
+	assert.Contains(t, buf.String(), "n:1,")
+}
+
+func TestListenerCount(t *testing.T) {
+	var tests = []struct {
+		name   string
+		counts []int64
+		stats  Stats
+	}{
+		{name: "in sequence", counts: []int64{1, 2, 3}, stats: Stats{Received: 3}},
+		{name: "gap", counts: []int64{1, 4, 5}, stats: Stats{Received: 3, Lost: 2}},
+		{name: "wrap", counts: []int64{998, 999, 1}, stats: Stats{Received: 3}},
+		{name: "gap over wrap", counts: []int64{998, 2}, stats: Stats{Received: 2, Lost: 2}},
+		{name: "duplicate", counts: []int64{1, 2, 2, 3}, stats: Stats{Received: 4, OutOfOrder: 1}},
+		{name: "reordered", counts: []int64{1, 3, 2, 4}, stats: Stats{Received: 4, Lost: 1, OutOfOrder: 1}},
+		{name: "restarted", counts: []int64{500, 501, 1, 2}, stats: Stats{Received: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListener(nil)
+			for _, n := range tt.counts {
+				tags := nmea.TagBlock{Source: "GP0001", LineCount: n}
+				l.count([]byte(tags.String() + "$GPHDT,123.456,T*32\r\n"))
+			}
+			assert.Equal(t, map[string]Stats{"GP0001": tt.stats}, l.Stats())
+		})
+	}
+}
+
+func TestLoopback(t *testing.T) {
+	l, err := Listen("127.0.0.1:0", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer l.Close()
+
+	tx, err := Dial(l.Addr().String(), "GP0001")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer tx.Close()
+
+	hdt := mustParse(t, "$GPHDT,123.456,T*32")
+	assert.NoError(t, tx.Send(hdt))
+	// Simulate two lost datagrams.
+	tx.lineCount += 2
+	assert.NoError(t, tx.Send(hdt, hdt))
+
+	l.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	sentences, err := l.Read()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, sentences, 1)
+	assert.Equal(t, int64(1), sentences[0].(nmea.HDT).TagBlock.LineCount)
+
+	sentences, err = l.Read()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, sentences, 2)
+	assert.Equal(t, map[string]Stats{"GP0001": {Received: 3, Lost: 2}}, l.Stats())
+}
+
+func TestListenerIgnoresForeignDatagrams(t *testing.T) {
+	l, err := Listen("127.0.0.1:0", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer l.Close()
+
+	conn, err := net.Dial("udp", l.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	conn.Write([]byte("RaUdP\x00binary"))
+	conn.Write(Encode("\\s:AI0001,n:7*0F\\$GPHDT,123.456,T*32"))
+
+	l.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	sentences, err := l.Read()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, sentences, 1)
+	assert.Equal(t, map[string]Stats{"AI0001": {Received: 1}}, l.Stats())
+}
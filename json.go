@@ -0,0 +1,319 @@
+package nmea
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Sentences are marshalled to JSON objects with a stable schema:
+//
+//   - "type" and "talker" hold the data type and talker of the sentence and
+//     discriminate the object, e.g. {"type":"RMC","talker":"GP",...}.
+//   - "tagBlock" holds the tag block as sent on the wire, when there is one.
+//   - every field of the sentence struct follows with its name in lower
+//     camel case, e.g. NumSatellites becomes "numSatellites" and HDOP
+//     becomes "hdop".
+//   - Time values are ISO 8601 times ("22:05:16.123") and Date values ISO
+//     8601 dates ("1994-06-13"), or null when not valid.
+//   - a field with a companion Valid field, like HDOP and HDOPValid, is null
+//     when the value is missing from the sentence and the Valid field is
+//     not included.
+//   - "raw" holds the sentence as received, without the tag block.
+//
+// UnmarshalSentence rebuilds the typed sentence from such an object.
+
+// MarshalJSON implements json.Marshaler
+func (t Time) MarshalJSON() ([]byte, error) {
+	if !t.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(fmt.Sprintf("%02d:%02d:%02d.%03d", t.Hour, t.Minute, t.Second, t.Millisecond))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Time) UnmarshalJSON(b []byte) error {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*t = Time{}
+		return nil
+	}
+	v, err := time.Parse("15:04:05.999999999", *s)
+	if err != nil {
+		return fmt.Errorf("nmea: invalid time: %s", *s)
+	}
+	*t = Time{true, v.Hour(), v.Minute(), v.Second(), v.Nanosecond() / 1e6}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. Two-digit years are expanded
+// with the DefaultCenturyPivot.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(fmt.Sprintf("%04d-%02d-%02d", d.FullYear(DefaultCenturyPivot), d.MM, d.DD))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*d = Date{}
+		return nil
+	}
+	v, err := time.Parse("2006-01-02", *s)
+	if err != nil {
+		return fmt.Errorf("nmea: invalid date: %s", *s)
+	}
+	*d = Date{true, v.Day(), int(v.Month()), v.Year() % 100}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (s GSVInfo) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	if err := writeJSONFields(&buf, reflect.ValueOf(s), true); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *GSVInfo) UnmarshalJSON(b []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	return readJSONFields(obj, reflect.ValueOf(s).Elem())
+}
+
+// UnmarshalSentence rebuilds a sentence from its JSON object, using the
+// "type" discriminator to pick the sentence struct.
+func UnmarshalSentence(b []byte) (Sentence, error) {
+	var header struct {
+		Type   string `json:"type"`
+		Talker string `json:"talker"`
+	}
+	if err := json.Unmarshal(b, &header); err != nil {
+		return nil, err
+	}
+	var s Sentence
+	switch {
+	case header.Talker == TypeMTK:
+		s = &MTK{}
+	case header.Type == TypeRMC:
+		s = &RMC{}
+	case header.Type == TypeGGA:
+		s = &GGA{}
+	case header.Type == TypeGSA:
+		s = &GSA{}
+	case header.Type == TypeGLL:
+		s = &GLL{}
+	case header.Type == TypeVTG:
+		s = &VTG{}
+	case header.Type == TypeZDA:
+		s = &ZDA{}
+	case header.Type == TypePGRME:
+		s = &PGRME{}
+	case header.Type == TypePGRMM:
+		s = &PGRMM{}
+	case header.Type == TypePCDIN:
+		s = &PCDIN{}
+	case header.Type == TypeGSV:
+		s = &GSV{}
+	case header.Type == TypeHDT:
+		s = &HDT{}
+	case header.Type == TypeGNS:
+		s = &GNS{}
+	case header.Type == TypeTHS:
+		s = &THS{}
+	case header.Type == TypeWPL:
+		s = &WPL{}
+	case header.Type == TypeRTE:
+		s = &RTE{}
+	case header.Type == TypeVHW:
+		s = &VHW{}
+	case header.Type == TypeDPT:
+		s = &DPT{}
+	case header.Type == TypeDBT:
+		s = &DBT{}
+	case header.Type == TypeDBS:
+		s = &DBS{}
+	case header.Type == TypeDTM:
+		s = &DTM{}
+	case header.Type == TypeMWV:
+		s = &MWV{}
+	case header.Type == TypeVDM, header.Type == TypeVDO:
+		s = &VDMVDO{}
+	default:
+		return nil, fmt.Errorf("nmea: sentence type '%s' not supported", header.Type)
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(s).Elem().Interface().(Sentence), nil
+}
+
+// marshalSentence writes the JSON object of a sentence struct.
+func marshalSentence(s Sentence) ([]byte, error) {
+	base, _ := BaseSentenceOf(s)
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	writeJSONValue(&buf, "type", base.Type)
+	buf.WriteByte(',')
+	writeJSONValue(&buf, "talker", base.Talker)
+	if !base.TagBlock.IsEmpty() {
+		buf.WriteByte(',')
+		writeJSONValue(&buf, "tagBlock", base.TagBlock.String())
+	}
+	if err := writeJSONFields(&buf, reflect.ValueOf(s), false); err != nil {
+		return nil, err
+	}
+	buf.WriteByte(',')
+	writeJSONValue(&buf, "raw", base.Raw)
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalSentence fills a sentence struct from its JSON object. When the
+// object has the raw sentence, the fields and checksum of the BaseSentence
+// are restored from it.
+func unmarshalSentence(b []byte, s interface{}) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	var header struct {
+		Type     string `json:"type"`
+		Talker   string `json:"talker"`
+		TagBlock string `json:"tagBlock"`
+		Raw      string `json:"raw"`
+	}
+	if err := json.Unmarshal(b, &header); err != nil {
+		return err
+	}
+	base := BaseSentence{Talker: header.Talker, Type: header.Type, Raw: header.Raw}
+	if header.Raw != "" {
+		parsed, err := parseSentence(header.TagBlock + header.Raw)
+		if err != nil {
+			return err
+		}
+		base = parsed
+	} else if header.TagBlock != "" {
+		tags, _, err := SplitTagBlock(header.TagBlock)
+		if err != nil {
+			return err
+		}
+		base.TagBlock = tags
+	}
+	v := reflect.ValueOf(s).Elem()
+	v.FieldByName("BaseSentence").Set(reflect.ValueOf(base))
+	return readJSONFields(obj, v)
+}
+
+var (
+	baseSentenceType = reflect.TypeOf(BaseSentence{})
+	jsonNull         = []byte("null")
+)
+
+func writeJSONValue(buf *bytes.Buffer, key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	k, _ := json.Marshal(key)
+	buf.Write(k)
+	buf.WriteByte(':')
+	buf.Write(b)
+	return nil
+}
+
+// jsonFields returns the exported fields of a struct to marshal, skipping
+// the BaseSentence and the Valid companions of other fields.
+func jsonFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Type == baseSentenceType {
+			continue
+		}
+		if name := strings.TrimSuffix(f.Name, "Valid"); name != f.Name && f.Type.Kind() == reflect.Bool {
+			if _, ok := t.FieldByName(name); ok {
+				continue
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func writeJSONFields(buf *bytes.Buffer, v reflect.Value, first bool) error {
+	for _, f := range jsonFields(v.Type()) {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		var value interface{} = v.FieldByIndex(f.Index).Interface()
+		if valid := v.FieldByName(f.Name + "Valid"); valid.IsValid() && valid.Kind() == reflect.Bool && !valid.Bool() {
+			value = nil
+		} else if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() != reflect.Uint8 && v.FieldByIndex(f.Index).IsNil() {
+			value = reflect.MakeSlice(f.Type, 0, 0).Interface()
+		}
+		if err := writeJSONValue(buf, jsonName(f.Name), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readJSONFields(obj map[string]json.RawMessage, v reflect.Value) error {
+	for _, f := range jsonFields(v.Type()) {
+		raw, ok := obj[jsonName(f.Name)]
+		if !ok {
+			continue
+		}
+		valid := v.FieldByName(f.Name + "Valid")
+		if valid.IsValid() && valid.Kind() == reflect.Bool {
+			valid.SetBool(!bytes.Equal(raw, jsonNull))
+			if !valid.Bool() {
+				continue
+			}
+		}
+		if err := json.Unmarshal(raw, v.FieldByIndex(f.Index).Addr().Interface()); err != nil {
+			return fmt.Errorf("nmea: invalid %s: %s", jsonName(f.Name), raw)
+		}
+	}
+	return nil
+}
+
+// jsonName returns the lower camel case name of a field. A leading acronym
+// is lowered as a whole, so HDOP becomes hdop, DGPSAge becomes dgpsAge and
+// SVs becomes svs.
+func jsonName(name string) string {
+	r := []rune(name)
+	n := 0
+	for n < len(r) && unicode.IsUpper(r[n]) {
+		n++
+	}
+	switch {
+	case n == len(r), n > 1 && string(r[n:]) == "s":
+		return strings.ToLower(name)
+	case n > 1:
+		n--
+	}
+	return strings.ToLower(string(r[:n])) + string(r[n:])
+}
@@ -0,0 +1,146 @@
+package nmea
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var jsontests = []struct {
+	name string
+	raw  string
+	json string
+}{
+	{
+		name: "RMC",
+		raw:  "$GPRMC,220516.123,A,5133.82,N,00042.24,W,173.8,,130694,004.2,W*48",
+		json: `{"type":"RMC","talker":"GP","time":"22:05:16.123","validity":"A","latitude":51.56366666666666,"longitude":-0.7040000000000001,"speed":173.8,"course":null,"date":"1994-06-13","variation":-4.2,"dateCorrected":false,"raw":"$GPRMC,220516.123,A,5133.82,N,00042.24,W,173.8,,130694,004.2,W*48"}`,
+	},
+	{
+		name: "GGA with tag block",
+		raw:  "\\s:somebody,c:1693824000*3B\\$GNGGA,,,,,,0,,,,M,,M,,*78",
+		json: `{"type":"GGA","talker":"GN","tagBlock":"\\c:1693824000,s:somebody*3B\\","time":null,"latitude":0,"longitude":0,"fixQuality":"0","numSatellites":0,"hdop":null,"altitude":null,"separation":null,"dgpsAge":"","dgpsId":"","raw":"$GNGGA,,,,,,0,,,,M,,M,,*78"}`,
+	},
+	{
+		name: "GSV",
+		raw:  "$GPGSV,1,1,02,11,50,310,42,12,10,020,*7A",
+		json: `{"type":"GSV","talker":"GP","totalMessages":1,"messageNumber":1,"numberSVsInView":2,"info":[{"svprnNumber":11,"elevation":50,"azimuth":310,"snr":42},{"svprnNumber":12,"elevation":10,"azimuth":20,"snr":null}],"raw":"$GPGSV,1,1,02,11,50,310,42,12,10,020,*7A"}`,
+	},
+	{
+		name: "RTE",
+		raw:  "$IIRTE,4,1,c,Rte 1,411,412,413,414,415*6F",
+		json: `{"type":"RTE","talker":"II","numberOfSentences":4,"sentenceNumber":1,"activeRouteOrWaypointList":"c","name":"Rte 1","idents":["411","412","413","414","415"],"raw":"$IIRTE,4,1,c,Rte 1,411,412,413,414,415*6F"}`,
+	},
+}
+
+func TestMarshalJSON(t *testing.T) {
+	for _, tt := range jsontests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.raw)
+			if !assert.NoError(t, err) {
+				return
+			}
+			b, err := json.Marshal(s)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.json, string(b))
+
+			u, err := UnmarshalSentence(b)
+			assert.NoError(t, err)
+			assert.Equal(t, s, u)
+		})
+	}
+}
+
+func TestUnmarshalSentenceRoundTrip(t *testing.T) {
+	for _, raw := range []string{
+		"$GPGGA,034225.077,3356.4650,S,15124.5567,E,1,03,9.7,-25.0,M,21.0,M,,0000*51",
+		"$GPGLL,3926.7952,N,12000.5947,W,022732,A,A*58",
+		"$GNGNS,014035.00,4332.69262,S,17235.48549,E,RR,13,0.9,25.63,11.24,,*70",
+		"$GPGSA,A,3,22,19,18,27,14,03,,,,,,,3.1,2.0,2.4*36",
+		"$GPVTG,45.5,T,67.5,M,30.45,N,56.40,K*4B",
+		"$GPZDA,172809.456,12,07,1996,00,00*57",
+		"$PGRME,3.3,M,4.9,M,6.0,M*25",
+		"$GPHDT,123.456,T*32",
+		"$INTHS,0.0,V*30",
+		"$IIWPL,5503.4530,N,01037.2742,E,411*6F",
+		"$VWVHW,45.0,T,43.0,M,3.5,N,6.4,K*56",
+		"$SDDPT,0.5,0.5,*7B",
+		"$SDDBT,00.1,f,00.0,M,00.0,F*37",
+		"$SDDBS,00.1,f,00.0,M,00.0,F*30",
+		"$WIMWV,214.8,R,0.1,K,A*28",
+		"$PCDIN,01F112,000C72EA,09,002B54FF7FFF7FFC*2A",
+		"$PMTK001,604,3*32",
+		"!AIVDM,1,1,,B,15M67FC000G?ufbE`FepT@3n00Sa,0*5C",
+	} {
+		t.Run(raw, func(t *testing.T) {
+			s, err := Parse(raw)
+			if !assert.NoError(t, err) {
+				return
+			}
+			b, err := json.Marshal(s)
+			assert.NoError(t, err)
+			u, err := UnmarshalSentence(b)
+			assert.NoError(t, err)
+			assert.Equal(t, s, u)
+		})
+	}
+}
+
+func TestUnmarshalSentenceWithoutRaw(t *testing.T) {
+	s, err := UnmarshalSentence([]byte(`{"type":"GGA","talker":"GP","time":"12:00:01.5","latitude":1.5,"hdop":0.9,"altitude":null}`))
+	assert.NoError(t, err)
+	assert.Equal(t, GGA{
+		BaseSentence: BaseSentence{Talker: "GP", Type: "GGA"},
+		Time:         Time{true, 12, 0, 1, 500},
+		Latitude:     1.5,
+		HDOP:         0.9,
+		HDOPValid:    true,
+	}, s)
+
+	_, err = UnmarshalSentence([]byte(`{"type":"XYZ","talker":"GP"}`))
+	assert.EqualError(t, err, "nmea: sentence type 'XYZ' not supported")
+
+	_, err = UnmarshalSentence([]byte(`{"type":"RMC","talker":"GP","time":"25:00"}`))
+	assert.EqualError(t, err, "nmea: invalid time: \"25:00\"")
+
+	_, err = UnmarshalSentence([]byte(`{"type":"RMC","talker":"GP","speed":"fast"}`))
+	assert.EqualError(t, err, "nmea: invalid speed: \"fast\"")
+}
+
+func TestTimeJSON(t *testing.T) {
+	b, err := json.Marshal(Time{true, 1, 2, 3, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, `"01:02:03.004"`, string(b))
+
+	var tm Time
+	assert.NoError(t, json.Unmarshal(b, &tm))
+	assert.Equal(t, Time{true, 1, 2, 3, 4}, tm)
+	assert.NoError(t, json.Unmarshal([]byte("null"), &tm))
+	assert.Equal(t, Time{}, tm)
+}
+
+func TestDateJSON(t *testing.T) {
+	b, err := json.Marshal(Date{true, 13, 6, 94})
+	assert.NoError(t, err)
+	assert.Equal(t, `"1994-06-13"`, string(b))
+
+	var d Date
+	assert.NoError(t, json.Unmarshal([]byte(`"2024-02-29"`), &d))
+	assert.Equal(t, Date{true, 29, 2, 24}, d)
+	assert.Error(t, json.Unmarshal([]byte(`"2024-02-30"`), &d))
+}
+
+func TestJSONName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Time":            "time",
+		"HDOP":            "hdop",
+		"DGPSAge":         "dgpsAge",
+		"SVs":             "svs",
+		"NumberSVsInView": "numberSVsInView",
+		"SVPRNNumber":     "svprnNumber",
+		"DD":              "dd",
+	} {
+		assert.Equal(t, expected, jsonName(name))
+	}
+}
@@ -0,0 +1,126 @@
+// Package kml writes GeoJSON feature collections as KML documents, so the
+// waypoints, tracks and vessels collected from a stream can be reviewed in
+// Google Earth.
+package kml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/storskegg/go-nmea/geojson"
+)
+
+// Namespace of KML 2.2 documents
+const Namespace = "http://www.opengis.net/kml/2.2"
+
+type document struct {
+	XMLName  xml.Name `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Document struct {
+		Name       string      `xml:"name,omitempty"`
+		Placemarks []placemark `xml:"Placemark"`
+	}
+}
+
+type placemark struct {
+	Name         string      `xml:"name,omitempty"`
+	ExtendedData *extended   `xml:"ExtendedData,omitempty"`
+	Point        *point      `xml:"Point,omitempty"`
+	LineString   *lineString `xml:"LineString,omitempty"`
+}
+
+type extended struct {
+	Data []data `xml:"Data"`
+}
+
+type data struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type point struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type lineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+// Write encodes the point and line string features of a collection as
+// placemarks of a KML document. Placemarks are named after the Name or
+// Ident property, or the feature id, and the other properties are kept as
+// extended data.
+func Write(w io.Writer, name string, fc geojson.FeatureCollection) error {
+	var doc document
+	doc.Document.Name = name
+	for _, f := range fc.Features {
+		if f.Geometry == nil {
+			continue
+		}
+		p := placemark{Name: placemarkName(f), ExtendedData: extendedData(f.Properties)}
+		switch c := f.Geometry.Coordinates.(type) {
+		case [2]float64:
+			p.Point = &point{Coordinates: coordinates(c)}
+		case [][2]float64:
+			parts := make([]string, len(c))
+			for i, pos := range c {
+				parts[i] = coordinates(pos)
+			}
+			p.LineString = &lineString{Tessellate: 1, Coordinates: strings.Join(parts, " ")}
+		default:
+			continue
+		}
+		doc.Document.Placemarks = append(doc.Document.Placemarks, p)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func placemarkName(f geojson.Feature) string {
+	for _, key := range []string{"Name", "Ident"} {
+		if s, ok := f.Properties[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	if f.ID != nil {
+		return fmt.Sprint(f.ID)
+	}
+	return ""
+}
+
+func extendedData(props map[string]interface{}) *extended {
+	if len(props) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	e := &extended{}
+	for _, k := range keys {
+		v := ""
+		if props[k] != nil {
+			v = fmt.Sprint(props[k])
+		}
+		e.Data = append(e.Data, data{Name: k, Value: v})
+	}
+	return e
+}
+
+func coordinates(p [2]float64) string {
+	return strconv.FormatFloat(p[0], 'f', -1, 64) + "," + strconv.FormatFloat(p[1], 'f', -1, 64)
+}
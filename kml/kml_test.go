@@ -0,0 +1,68 @@
+package kml
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/storskegg/go-nmea/geojson"
+)
+
+func TestWrite(t *testing.T) {
+	fc := geojson.NewFeatureCollection(
+		geojson.NewFeature(geojson.NewPoint(51.477, -0.4597), map[string]interface{}{"Ident": "EGLL", "Sentence": "GPWPL"}),
+		geojson.NewFeature(geojson.NewLineString([][2]float64{{-0.4597, 51.477}, {2.55, 49.0097}}), map[string]interface{}{"Name": "Track"}),
+		geojson.Feature{Type: "Feature", ID: 371798000, Geometry: geojson.NewPoint(48.38, -123.39), Properties: map[string]interface{}{"Name": "", "Course": nil}},
+		geojson.NewFeature(nil, nil),
+	)
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, "Voyage", fc))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <name>Voyage</name>
+    <Placemark>
+      <name>EGLL</name>
+      <ExtendedData>
+        <Data name="Ident">
+          <value>EGLL</value>
+        </Data>
+        <Data name="Sentence">
+          <value>GPWPL</value>
+        </Data>
+      </ExtendedData>
+      <Point>
+        <coordinates>-0.4597,51.477</coordinates>
+      </Point>
+    </Placemark>
+    <Placemark>
+      <name>Track</name>
+      <ExtendedData>
+        <Data name="Name">
+          <value>Track</value>
+        </Data>
+      </ExtendedData>
+      <LineString>
+        <tessellate>1</tessellate>
+        <coordinates>-0.4597,51.477 2.55,49.0097</coordinates>
+      </LineString>
+    </Placemark>
+    <Placemark>
+      <name>371798000</name>
+      <ExtendedData>
+        <Data name="Course">
+          <value></value>
+        </Data>
+        <Data name="Name">
+          <value></value>
+        </Data>
+      </ExtendedData>
+      <Point>
+        <coordinates>-123.39,48.38</coordinates>
+      </Point>
+    </Placemark>
+  </Document>
+</kml>
+`, buf.String())
+}
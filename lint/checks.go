@@ -0,0 +1,228 @@
+package lint
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// unbounded is the maximum of field counts without a maximum.
+const unbounded = math.MaxInt32
+
+// fieldCount is a range of field counts, without the prefix, going up by
+// step.
+type fieldCount struct {
+	min, max, step int
+}
+
+func (c fieldCount) match(n int) bool {
+	return n >= c.min && n <= c.max && (n-c.min)%c.step == 0
+}
+
+func (c fieldCount) String() string {
+	switch {
+	case c.min == c.max:
+		return strconv.Itoa(c.min)
+	case c.max == unbounded:
+		return fmt.Sprintf("at least %d", c.min)
+	case c.step == 1:
+		return fmt.Sprintf("%d to %d", c.min, c.max)
+	}
+	return fmt.Sprintf("%d to %d in steps of %d", c.min, c.max, c.step)
+}
+
+// fieldCounts are the field counts of known types, including the fields
+// added by later versions of NMEA 0183: the mode of RMC, GLL and VTG, the
+// navigational status of RMC and GNS, and the system and signal IDs of GSA
+// and GSV.
+var fieldCounts = map[string][]fieldCount{
+	nmea.TypeRMC:   {{11, 13, 1}},
+	nmea.TypeGGA:   {{14, 14, 1}},
+	nmea.TypeGSA:   {{17, 18, 1}},
+	nmea.TypeGSV:   {{3, 19, 4}, {4, 20, 4}},
+	nmea.TypeGLL:   {{6, 7, 1}},
+	nmea.TypeVTG:   {{8, 9, 1}},
+	nmea.TypeZDA:   {{6, 6, 1}},
+	nmea.TypeGNS:   {{12, 13, 1}},
+	nmea.TypeHDT:   {{2, 2, 1}},
+	nmea.TypeTHS:   {{2, 2, 1}},
+	nmea.TypeWPL:   {{5, 5, 1}},
+	nmea.TypeRTE:   {{4, unbounded, 1}},
+	nmea.TypeVHW:   {{8, 8, 1}},
+	nmea.TypeDPT:   {{3, 3, 1}},
+	nmea.TypeDBT:   {{6, 6, 1}},
+	nmea.TypeDBS:   {{6, 6, 1}},
+	nmea.TypeDTM:   {{8, 8, 1}},
+	nmea.TypeMWV:   {{5, 5, 1}},
+	nmea.TypeVDM:   {{6, 6, 1}},
+	nmea.TypeVDO:   {{6, 6, 1}},
+	nmea.TypePGRME: {{6, 6, 1}},
+	nmea.TypePGRMM: {{1, 1, 1}},
+	nmea.TypePCDIN: {{4, 4, 1}},
+}
+
+// fields reports a field count not matching the type, and whether it
+// matches.
+func (l *Linter) fields(s nmea.BaseSentence) bool {
+	counts, ok := fieldCounts[s.Type]
+	if !ok || s.Talker == nmea.TypeMTK {
+		return true
+	}
+	expected := make([]string, len(counts))
+	for i, c := range counts {
+		if c.match(len(s.Fields)) {
+			return true
+		}
+		expected[i] = c.String()
+	}
+	l.report(Error, CodeFields, "%s sentence has %d fields, expected %s", s.Type, len(s.Fields), strings.Join(expected, " or "))
+	return false
+}
+
+// rangeCheck checks the value of a field, returning the problem or an
+// empty string. Empty fields are not checked.
+type rangeCheck struct {
+	index int
+	check func(fields []string, i int) string
+}
+
+// rangeChecks are the checked fields of known types.
+var rangeChecks = map[string][]rangeCheck{
+	nmea.TypeRMC: {{0, checkTime}, {2, checkLat}, {4, checkLon}, {7, checkAngle("course")}, {8, checkDate}},
+	nmea.TypeGGA: {{0, checkTime}, {1, checkLat}, {3, checkLon}},
+	nmea.TypeGLL: {{0, checkLat}, {2, checkLon}, {4, checkTime}},
+	nmea.TypeGNS: {{0, checkTime}, {1, checkLat}, {3, checkLon}},
+	nmea.TypeWPL: {{0, checkLat}, {2, checkLon}},
+	nmea.TypeZDA: {{0, checkTime}, {1, checkInt("day", 1, 31)}, {2, checkInt("month", 1, 12)}},
+	nmea.TypeVTG: {{0, checkAngle("true track")}, {2, checkAngle("magnetic track")}},
+	nmea.TypeHDT: {{0, checkAngle("heading")}},
+	nmea.TypeTHS: {{0, checkAngle("heading")}},
+	nmea.TypeVHW: {{0, checkAngle("true heading")}, {2, checkAngle("magnetic heading")}},
+	nmea.TypeMWV: {{0, checkAngle("wind angle")}},
+	nmea.TypeGSV: {
+		{3, checkSatellites(1, checkInt("elevation", 0, 90))},
+		{3, checkSatellites(2, checkInt("azimuth", 0, 359))},
+		{3, checkSatellites(3, checkInt("SNR", 0, 99))},
+	},
+}
+
+// ranges reports values out of range, and whether they are all in range.
+func (l *Linter) ranges(s nmea.BaseSentence) bool {
+	ok := true
+	for _, c := range rangeChecks[s.Type] {
+		if c.index >= len(s.Fields) {
+			continue
+		}
+		if problem := c.check(s.Fields, c.index); problem != "" {
+			l.report(Error, CodeRange, "%s", problem)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// checkTime checks a time of day, hhmmss.ss.
+func checkTime(fields []string, i int) string {
+	v := fields[i]
+	if len(v) < 6 || !isDigits(v[:6]) {
+		return ""
+	}
+	h, _ := strconv.Atoi(v[:2])
+	m, _ := strconv.Atoi(v[2:4])
+	s, _ := strconv.Atoi(v[4:6])
+	if h > 23 || m > 59 || s > 60 {
+		return fmt.Sprintf("time %s:%s:%s out of range", v[:2], v[2:4], v[4:])
+	}
+	return ""
+}
+
+// checkDate checks a date, ddmmyy.
+func checkDate(fields []string, i int) string {
+	v := fields[i]
+	if len(v) != 6 || !isDigits(v) {
+		return ""
+	}
+	d, _ := strconv.Atoi(v[:2])
+	m, _ := strconv.Atoi(v[2:4])
+	if d < 1 || d > 31 || m < 1 || m > 12 {
+		return fmt.Sprintf("date %s-%s-%s out of range", v[4:], v[2:4], v[:2])
+	}
+	return ""
+}
+
+// checkLat checks a latitude, ddmm.mm, followed by its direction.
+func checkLat(fields []string, i int) string {
+	return checkLatLon(fields, i, "latitude", 90)
+}
+
+// checkLon checks a longitude, dddmm.mm, followed by its direction.
+func checkLon(fields []string, i int) string {
+	return checkLatLon(fields, i, "longitude", 180)
+}
+
+func checkLatLon(fields []string, i int, name string, max float64) string {
+	v, err := strconv.ParseFloat(fields[i], 64)
+	if err != nil || v < 0 {
+		return ""
+	}
+	dir := ""
+	if i+1 < len(fields) {
+		dir = fields[i+1]
+	}
+	degrees := math.Floor(v / 100)
+	minutes := v - degrees*100
+	if minutes >= 60 {
+		return fmt.Sprintf("%s %s %s has %g minutes", name, fields[i], dir, minutes)
+	}
+	if degrees+minutes/60 > max {
+		return fmt.Sprintf("%s %s %s out of range", name, fields[i], dir)
+	}
+	return ""
+}
+
+// checkAngle checks an angle from 0 to 360 degrees.
+func checkAngle(name string) func([]string, int) string {
+	return func(fields []string, i int) string {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil || (v >= 0 && v <= 360) {
+			return ""
+		}
+		return fmt.Sprintf("%s %s out of range", name, fields[i])
+	}
+}
+
+// checkInt checks an integer from min to max.
+func checkInt(name string, min, max int64) func([]string, int) string {
+	return func(fields []string, i int) string {
+		v, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil || (v >= min && v <= max) {
+			return ""
+		}
+		return fmt.Sprintf("%s %s out of range", name, fields[i])
+	}
+}
+
+// checkSatellites checks a field of every satellite of a GSV sentence,
+// which lists them in groups of four fields from i.
+func checkSatellites(offset int, check func([]string, int) string) func([]string, int) string {
+	return func(fields []string, i int) string {
+		for j := i + offset; j < len(fields) && j-offset+4 <= len(fields); j += 4 {
+			if problem := check(fields, j); problem != "" {
+				return problem
+			}
+		}
+		return ""
+	}
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldCount(t *testing.T) {
+	var tests = []struct {
+		name  string
+		count fieldCount
+		n     int
+		match bool
+		str   string
+	}{
+		{name: "fixed", count: fieldCount{14, 14, 1}, n: 14, match: true, str: "14"},
+		{name: "fixed mismatch", count: fieldCount{14, 14, 1}, n: 13, str: "14"},
+		{name: "range", count: fieldCount{11, 13, 1}, n: 12, match: true, str: "11 to 13"},
+		{name: "above range", count: fieldCount{11, 13, 1}, n: 14, str: "11 to 13"},
+		{name: "steps", count: fieldCount{3, 19, 4}, n: 15, match: true, str: "3 to 19 in steps of 4"},
+		{name: "between steps", count: fieldCount{3, 19, 4}, n: 14, str: "3 to 19 in steps of 4"},
+		{name: "unbounded", count: fieldCount{4, unbounded, 1}, n: 40, match: true, str: "at least 4"},
+		{name: "below unbounded", count: fieldCount{4, unbounded, 1}, n: 3, str: "at least 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.match, tt.count.match(tt.n))
+			assert.Equal(t, tt.str, tt.count.String())
+		})
+	}
+}
+
+func TestRangeChecks(t *testing.T) {
+	var tests = []struct {
+		name    string
+		check   func([]string, int) string
+		fields  []string
+		problem string
+	}{
+		{name: "time", check: checkTime, fields: []string{"235960.50"}},
+		{name: "time hour", check: checkTime, fields: []string{"240000"}, problem: "time 24:00:00 out of range"},
+		{name: "time minute", check: checkTime, fields: []string{"126100.00"}, problem: "time 12:61:00.00 out of range"},
+		{name: "time empty", check: checkTime, fields: []string{""}},
+		{name: "date", check: checkDate, fields: []string{"290220"}},
+		{name: "date day", check: checkDate, fields: []string{"000120"}, problem: "date 20-01-00 out of range"},
+		{name: "latitude", check: checkLat, fields: []string{"8959.99", "S"}},
+		{name: "latitude degrees", check: checkLat, fields: []string{"9000.01", "S"}, problem: "latitude 9000.01 S out of range"},
+		{name: "latitude minutes", check: checkLat, fields: []string{"4575.5", "N"}, problem: "latitude 4575.5 N has 75.5 minutes"},
+		{name: "longitude", check: checkLon, fields: []string{"17959.99", "E"}},
+		{name: "longitude degrees", check: checkLon, fields: []string{"18100.00", "W"}, problem: "longitude 18100.00 W out of range"},
+		{name: "angle", check: checkAngle("heading"), fields: []string{"360.0"}},
+		{name: "angle negative", check: checkAngle("heading"), fields: []string{"-1.5"}, problem: "heading -1.5 out of range"},
+		{name: "angle invalid", check: checkAngle("heading"), fields: []string{"XXX"}},
+		{name: "int", check: checkInt("month", 1, 12), fields: []string{"12"}},
+		{name: "int out of range", check: checkInt("month", 1, 12), fields: []string{"0"}, problem: "month 0 out of range"},
+		{
+			name:   "satellites",
+			check:  checkSatellites(1, checkInt("elevation", 0, 90)),
+			fields: []string{"2", "1", "05", "01", "40", "083", "46", "02", "17", "308", "41"},
+		},
+		{
+			name:    "satellite out of range",
+			check:   checkSatellites(2, checkInt("azimuth", 0, 359)),
+			fields:  []string{"2", "1", "05", "01", "40", "083", "46", "02", "17", "360", "41"},
+			problem: "azimuth 360 out of range",
+		},
+		{
+			name:   "satellites with signal ID",
+			check:  checkSatellites(3, checkInt("SNR", 0, 99)),
+			fields: []string{"1", "1", "01", "01", "40", "083", "46", "100"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := 0
+			if len(tt.fields) > 2 {
+				i = 3
+			}
+			assert.Equal(t, tt.problem, tt.check(tt.fields, i))
+		})
+	}
+}
@@ -0,0 +1,323 @@
+// Package lint checks logs of sentences for the problems that trip up
+// parsers and plotters: checksum errors, over-length sentences, illegal
+// characters, field counts and values out of range for known types,
+// broken GSV, RTE and VDM sequences, timestamps running backwards and
+// inconsistent talkers.
+//
+// Every problem is reported as an Issue with the number of its line and a
+// severity: errors make the sentence unusable, warnings point at data
+// which is likely wrong and infos at oddities worth knowing about.
+package lint
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// timeLayout is the layout of times of day in messages.
+const timeLayout = "15:04:05.000"
+
+// Severity of an issue.
+type Severity int
+
+// Severities, from the least severe.
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+var severities = []string{"info", "warning", "error"}
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	if s < Info || s > Error {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severities[s]
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (s *Severity) UnmarshalText(b []byte) error {
+	v, err := ParseSeverity(string(b))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// ParseSeverity returns the severity of a name: info, warning or error.
+func ParseSeverity(name string) (Severity, error) {
+	for i, s := range severities {
+		if strings.EqualFold(name, s) {
+			return Severity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("lint: unknown severity %s", name)
+}
+
+// Codes of the checks.
+const (
+	CodeSyntax    = "syntax"    // Malformed line: no start, checksum separator or valid TAG Block
+	CodeChecksum  = "checksum"  // Checksum mismatch
+	CodeLength    = "length"    // Sentence longer than nmea.MaxSentenceLength
+	CodeCharacter = "character" // Character outside printable ASCII, or a second sentence start
+	CodeFields    = "fields"    // Field count not matching a known type
+	CodeParse     = "parse"     // Field of a known type failing to decode
+	CodeRange     = "range"     // Value out of range
+	CodeSequence  = "sequence"  // Broken multi-part sequence
+	CodeTime      = "time"      // Timestamp running backwards
+	CodeTalker    = "talker"    // Unknown or inconsistent talker
+)
+
+// Issue is a problem found on a line.
+type Issue struct {
+	Line     int      `json:"line"` // Line number, from 1
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+// String formats the issue, e.g. 12: error: checksum mismatch [32 != 33].
+func (i Issue) String() string {
+	return fmt.Sprintf("%d: %s: %s", i.Line, i.Severity, i.Message)
+}
+
+// Linter checks lines in order, keeping the state of sequences, times and
+// talkers across them.
+type Linter struct {
+	line      int
+	issues    []Issue
+	sequences map[string]*sequence // Sequences in progress, by talker and type
+	times     map[string]stamp     // Last time of day, by talker
+	dates     map[string]stamp     // Last date, by talker
+	tags      map[string]stamp     // Last TAG Block time, by source
+	talkers   map[string]string    // First talker, by type
+	mixed     map[string]bool      // Talkers and types reported as mixed
+}
+
+// stamp is a time seen on a line.
+type stamp struct {
+	time time.Time
+	line int
+}
+
+// NewLinter constructor
+func NewLinter() *Linter {
+	return &Linter{
+		sequences: map[string]*sequence{},
+		times:     map[string]stamp{},
+		dates:     map[string]stamp{},
+		tags:      map[string]stamp{},
+		talkers:   map[string]string{},
+		mixed:     map[string]bool{},
+	}
+}
+
+// Lint checks the lines read from r until the end of the stream, and
+// returns the issues in line order.
+func Lint(r io.Reader) ([]Issue, error) {
+	l := NewLinter()
+	var issues []Issue
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		issues = append(issues, l.Check(scanner.Text())...)
+	}
+	issues = append(issues, l.Finish()...)
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues, scanner.Err()
+}
+
+// Check checks the next line and returns its issues. Issues of sequences
+// broken by the line may refer to earlier lines.
+func (l *Linter) Check(text string) []Issue {
+	l.line++
+	l.issues = nil
+	text = strings.TrimRight(text, "\r\n")
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	l.characters(text)
+
+	tags, raw, err := nmea.SplitTagBlock(text)
+	if err != nil {
+		l.report(Error, CodeSyntax, "%v", err)
+		return l.issues
+	}
+	if !strings.HasPrefix(raw, nmea.SentenceStart) && !strings.HasPrefix(raw, nmea.SentenceStartEncapsulated) {
+		l.report(Error, CodeSyntax, "sentence does not start with '$' or '!'")
+		return l.issues
+	}
+	if len(raw)+len(nmea.CRLF) > nmea.MaxSentenceLength {
+		l.report(Warning, CodeLength, "sentence is %d characters long with CRLF, over the maximum of %d", len(raw)+len(nmea.CRLF), nmea.MaxSentenceLength)
+	}
+	if !strings.Contains(raw, nmea.ChecksumSep) {
+		l.report(Error, CodeChecksum, "sentence has no checksum")
+		return l.issues
+	}
+	base, err := nmea.ParseBaseSentence(raw)
+	if err != nil {
+		l.report(Error, CodeChecksum, "%s", strings.TrimPrefix(err.Error(), "nmea: sentence "))
+		return l.issues
+	}
+	if tags.Time != 0 {
+		l.tagTime(tags)
+	}
+	l.talker(base)
+
+	if !l.fields(base) || !l.ranges(base) {
+		return l.issues
+	}
+	s, err := nmea.Parse(raw)
+	if err != nil {
+		if _, known := fieldCounts[base.Type]; known {
+			l.report(Error, CodeParse, "%s", strings.TrimPrefix(err.Error(), "nmea: "))
+		}
+		return l.issues
+	}
+	l.sequence(s)
+	l.time(s)
+	return l.issues
+}
+
+// Finish reports the sequences left incomplete at the end of the log.
+func (l *Linter) Finish() []Issue {
+	l.issues = nil
+	for _, key := range sortedKeys(l.sequences) {
+		l.incomplete(l.sequences[key])
+	}
+	l.sequences = map[string]*sequence{}
+	return l.issues
+}
+
+// report adds an issue of the current line.
+func (l *Linter) report(severity Severity, code, format string, args ...interface{}) {
+	l.reportLine(l.line, severity, code, format, args...)
+}
+
+func (l *Linter) reportLine(line int, severity Severity, code, format string, args ...interface{}) {
+	l.issues = append(l.issues, Issue{
+		Line:     line,
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// characters reports characters outside printable ASCII, and sentence
+// starts inside a sentence, which come from sentences run together.
+func (l *Linter) characters(text string) {
+	for i := 0; i < len(text); i++ {
+		if c := text[i]; c < 0x20 || c > 0x7e {
+			l.report(Error, CodeCharacter, "illegal character 0x%02X at column %d", c, i+1)
+			return
+		}
+	}
+	_, raw, err := nmea.SplitTagBlock(text)
+	if err != nil || len(raw) < 2 {
+		return
+	}
+	if i := strings.IndexAny(raw[1:], nmea.SentenceStart+nmea.SentenceStartEncapsulated); i >= 0 {
+		l.report(Error, CodeCharacter, "sentence start '%c' at column %d, sentences run together", raw[i+1], len(text)-len(raw)+i+2)
+	}
+}
+
+// talker reports unknown talkers, and types sent by several talkers.
+// Receivers tracking several constellations send GSA and GSV sentences
+// per constellation, so those may come from several talkers.
+func (l *Linter) talker(s nmea.BaseSentence) {
+	if strings.HasPrefix(s.Talker, "P") {
+		return
+	}
+	if _, ok := nmea.LookupTalker(s.Talker); !ok {
+		l.report(Warning, CodeTalker, "unknown talker %s", s.Talker)
+	}
+	if s.Type == nmea.TypeGSA || s.Type == nmea.TypeGSV {
+		return
+	}
+	first, ok := l.talkers[s.Type]
+	switch {
+	case !ok:
+		l.talkers[s.Type] = s.Talker
+	case first != s.Talker && !l.mixed[s.Prefix()]:
+		l.mixed[s.Prefix()] = true
+		l.report(Warning, CodeTalker, "%s sentence from talker %s, earlier from %s", s.Type, s.Talker, first)
+	}
+}
+
+// tagTime reports TAG Block times running backwards for a source.
+func (l *Linter) tagTime(tags nmea.TagBlock) {
+	t := tags.Timestamp()
+	last, ok := l.tags[tags.Source]
+	if ok && t.Before(last.time) {
+		l.report(Warning, CodeTime, "TAG Block time %s before %s of line %d", t.UTC().Format(time.RFC3339Nano), last.time.UTC().Format(time.RFC3339Nano), last.line)
+	}
+	l.tags[tags.Source] = stamp{t, l.line}
+}
+
+// time reports times of day and dates running backwards for a talker. A
+// time of day more than twelve hours before the last one has crossed
+// midnight.
+func (l *Linter) time(s nmea.Sentence) {
+	talker := s.TalkerID()
+	if t, ok := timeOf(s); ok {
+		tod := time.Time{}.Add(t.Duration())
+		last, ok := l.times[talker]
+		if ok && tod.Before(last.time) && last.time.Sub(tod) < 12*time.Hour {
+			l.report(Warning, CodeTime, "time %s before %s of line %d", tod.Format(timeLayout), last.time.Format(timeLayout), last.line)
+		}
+		l.times[talker] = stamp{tod, l.line}
+	}
+	if d, ok := dateOf(s); ok {
+		last, ok := l.dates[talker]
+		if ok && d.Before(last.time) {
+			l.report(Warning, CodeTime, "date %s before %s of line %d", d.Format("2006-01-02"), last.time.Format("2006-01-02"), last.line)
+		}
+		l.dates[talker] = stamp{d, l.line}
+	}
+}
+
+// timeOf returns the time of day of sentences carrying one.
+func timeOf(s nmea.Sentence) (nmea.Time, bool) {
+	var t nmea.Time
+	switch m := s.(type) {
+	case nmea.RMC:
+		t = m.Time
+	case nmea.GGA:
+		t = m.Time
+	case nmea.GLL:
+		t = m.Time
+	case nmea.GNS:
+		t = m.Time
+	case nmea.ZDA:
+		t = m.Time
+	}
+	return t, t.Valid
+}
+
+// dateOf returns the date of sentences carrying one.
+func dateOf(s nmea.Sentence) (time.Time, bool) {
+	switch m := s.(type) {
+	case nmea.RMC:
+		if m.Date.Valid {
+			return time.Date(m.Date.FullYear(nmea.DefaultCenturyPivot), time.Month(m.Date.MM), m.Date.DD, 0, 0, 0, 0, time.UTC), true
+		}
+	case nmea.ZDA:
+		if m.Day > 0 {
+			return time.Date(int(m.Year), time.Month(m.Month), int(m.Day), 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,201 @@
+package lint
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var linttests = []struct {
+	name   string
+	lines  []string
+	issues []Issue
+}{
+	{
+		name: "valid",
+		lines: []string{
+			"$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70",
+			"",
+			"$GPGGA,220517,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*57",
+			"$HEHDT,123.4,T*2B",
+			"\\s:Satelite_1*10\\$HEHDT,123.456,T*28",
+			"$PXYZ,1,2*08",
+		},
+	},
+	{
+		name: "syntax",
+		lines: []string{
+			"GPHDT,123.4,T*31",
+			"\\s:Satelite_1*01\\$GPHDT,123.456,T*32",
+		},
+		issues: []Issue{
+			{Line: 1, Severity: Error, Code: CodeSyntax, Message: "sentence does not start with '$' or '!'"},
+			{Line: 2, Severity: Error, Code: CodeSyntax, Message: "nmea: tagblock checksum mismatch [10 != 01]"},
+		},
+	},
+	{
+		name: "checksum",
+		lines: []string{
+			"$GPHDT,123.4,T*32",
+			"$GPHDT,123.4,T",
+		},
+		issues: []Issue{
+			{Line: 1, Severity: Error, Code: CodeChecksum, Message: "checksum mismatch [31 != 32]"},
+			{Line: 2, Severity: Error, Code: CodeChecksum, Message: "sentence has no checksum"},
+		},
+	},
+	{
+		name: "length",
+		lines: []string{
+			"$IIRTE,1,1,c,0,WAYPOINT01,WAYPOINT02,WAYPOINT03,WAYPOINT04,WAYPOINT05,WAYPOINT06,WAYPOIN*6C",
+		},
+		issues: []Issue{
+			{Line: 1, Severity: Warning, Code: CodeLength, Message: "sentence is 93 characters long with CRLF, over the maximum of 82"},
+		},
+	},
+	{
+		name: "characters",
+		lines: []string{
+			"$GPHDT,123.4,T*31\x00",
+			"$GPHDT,123.4$GPHDT,123.4,T*31",
+		},
+		issues: []Issue{
+			{Line: 1, Severity: Error, Code: CodeCharacter, Message: "illegal character 0x00 at column 18"},
+			{Line: 1, Severity: Error, Code: CodeChecksum, Message: "checksum mismatch [31 != 31\x00]"},
+			{Line: 2, Severity: Error, Code: CodeCharacter, Message: "sentence start '$' at column 13, sentences run together"},
+			{Line: 2, Severity: Error, Code: CodeChecksum, Message: "checksum mismatch [5C != 31]"},
+		},
+	},
+	{
+		name: "fields",
+		lines: []string{
+			"$GPRMC,220516,A,5133.82,N*66",
+			"$SDDPT,12.5,0.5*64",
+			"$SDDPT,12.5,0.5,100*79",
+		},
+		issues: []Issue{
+			{Line: 1, Severity: Error, Code: CodeFields, Message: "RMC sentence has 4 fields, expected 11 to 13"},
+			{Line: 2, Severity: Error, Code: CodeFields, Message: "DPT sentence has 2 fields, expected 3"},
+		},
+	},
+	{
+		name: "range",
+		lines: []string{
+			"$GPRMC,256116,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*75",
+			"$GPRMC,220516,A,9133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*7C",
+			"$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,400.0,301394,004.2,W*79",
+			"$GPZDA,220516,12,13,1994,00,00*4E",
+			"$GPGSV,2,2,05,15,95,120,44*47",
+		},
+		issues: []Issue{
+			{Line: 1, Severity: Error, Code: CodeRange, Message: "time 25:61:16 out of range"},
+			{Line: 2, Severity: Error, Code: CodeRange, Message: "latitude 9133.82 N out of range"},
+			{Line: 3, Severity: Error, Code: CodeRange, Message: "course 400.0 out of range"},
+			{Line: 3, Severity: Error, Code: CodeRange, Message: "date 94-13-30 out of range"},
+			{Line: 4, Severity: Error, Code: CodeRange, Message: "month 13 out of range"},
+			{Line: 5, Severity: Error, Code: CodeRange, Message: "elevation 95 out of range"},
+		},
+	},
+	{
+		name: "parse",
+		lines: []string{
+			"$GPHDT,XXX,T*43",
+			"$GPFOO,1,2,3.4,x,y,zz,*56",
+		},
+		issues: []Issue{
+			{Line: 1, Severity: Error, Code: CodeParse, Message: "GPHDT invalid heading: XXX"},
+		},
+	},
+	{
+		name: "time",
+		lines: []string{
+			"$GPGGA,220517,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*57",
+			"$GPGGA,220515,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*55",
+			"$GPGGA,000001,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*55",
+			"$GPZDA,220516,13,06,1994,00,00*4B",
+			"$GPZDA,220516,12,06,1994,00,00*4A",
+			"\\c:1553390540*50\\$GPHDT,123.4,T*31",
+			"\\c:1553390530*57\\$GPHDT,123.4,T*31",
+		},
+		issues: []Issue{
+			{Line: 2, Severity: Warning, Code: CodeTime, Message: "time 22:05:15.000 before 22:05:17.000 of line 1"},
+			{Line: 5, Severity: Warning, Code: CodeTime, Message: "date 1994-06-12 before 1994-06-13 of line 4"},
+			{Line: 7, Severity: Warning, Code: CodeTime, Message: "TAG Block time 2019-03-24T01:22:10Z before 2019-03-24T01:22:20Z of line 6"},
+		},
+	},
+	{
+		name: "talker",
+		lines: []string{
+			"$GPGGA,220517,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*57",
+			"$GNGGA,220517,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*49",
+			"$GNGGA,220517,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*49",
+			"$QQHDT,123.4,T*26",
+		},
+		issues: []Issue{
+			{Line: 2, Severity: Warning, Code: CodeTalker, Message: "GGA sentence from talker GN, earlier from GP"},
+			{Line: 4, Severity: Warning, Code: CodeTalker, Message: "unknown talker QQ"},
+		},
+	},
+	{
+		name: "sequence at the end",
+		lines: []string{
+			"$GPGSV,2,1,05,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,45*78",
+			"$HEHDT,123.4,T*2B",
+		},
+		issues: []Issue{
+			{Line: 1, Severity: Warning, Code: CodeSequence, Message: "GSV sequence incomplete, 1 of 2 sentences"},
+		},
+	},
+}
+
+func TestLint(t *testing.T) {
+	for _, tt := range linttests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := Lint(strings.NewReader(strings.Join(tt.lines, "\r\n")))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.issues, issues)
+		})
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	var tests = []struct {
+		name     string
+		severity Severity
+		err      string
+	}{
+		{name: "info", severity: Info},
+		{name: "Warning", severity: Warning},
+		{name: "ERROR", severity: Error},
+		{name: "fatal", err: "lint: unknown severity fatal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			severity, err := ParseSeverity(tt.name)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.severity, severity)
+			assert.Equal(t, strings.ToLower(tt.name), severity.String())
+		})
+	}
+	assert.Equal(t, "Severity(7)", Severity(7).String())
+}
+
+func TestIssueJSON(t *testing.T) {
+	issue := Issue{Line: 3, Severity: Warning, Code: CodeTalker, Message: "unknown talker QQ"}
+	b, err := json.Marshal(issue)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, `{"line":3,"severity":"warning","code":"talker","message":"unknown talker QQ"}`, string(b))
+
+	var decoded Issue
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, issue, decoded)
+	assert.Equal(t, "3: warning: unknown talker QQ", issue.String())
+}
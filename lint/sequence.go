@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// sequence is a multi-part sequence in progress.
+type sequence struct {
+	typ    string
+	line   int    // Line of the first sentence
+	total  int64  // Number of sentences
+	next   int64  // Number of the sentence expected next
+	id     string // Route name or message ID, the same in every sentence
+	idName string // What the id is, e.g. route
+
+	// GSV satellites
+	inView int64
+	listed int
+}
+
+// sequence follows the multi-part sequences of GSV, RTE, VDM and VDO
+// sentences. A sequence is broken by a sentence out of order or by the
+// first sentence of the next one.
+func (l *Linter) sequence(s nmea.Sentence) {
+	switch m := s.(type) {
+	case nmea.GSV:
+		seq := l.part(m.Talker+m.Type, m.Type, m.TotalMessages, m.MessageNumber, "", "")
+		if seq == nil {
+			return
+		}
+		if m.MessageNumber == 1 {
+			seq.inView = m.NumberSVsInView
+		} else if m.NumberSVsInView != seq.inView {
+			l.report(Warning, CodeSequence, "GSV satellites in view changed from %d to %d within a sequence", seq.inView, m.NumberSVsInView)
+			seq.inView = m.NumberSVsInView
+		}
+		seq.listed += len(m.Info)
+		if m.MessageNumber == m.TotalMessages && int64(seq.listed) != seq.inView {
+			l.report(Warning, CodeSequence, "GSV sequence lists %d satellites, %d in view", seq.listed, seq.inView)
+		}
+	case nmea.RTE:
+		l.part(m.Talker+m.Type, m.Type, m.NumberOfSentences, m.SentenceNumber, m.Name, "route")
+	case nmea.VDMVDO:
+		id := ""
+		if m.NumFragments > 1 {
+			id = strconv.FormatInt(m.MessageID, 10)
+		}
+		l.part(m.Talker+m.Type+m.Channel, m.Type, m.NumFragments, m.FragmentNumber, id, "message")
+	}
+}
+
+// part follows a sentence of a sequence, and returns the sequence unless
+// the sentence is out of order.
+func (l *Linter) part(key, typ string, total, number int64, id, idName string) *sequence {
+	describe := func(number, total int64, id string) string {
+		d := fmt.Sprintf("%s sentence %d of %d", typ, number, total)
+		if id != "" {
+			d += fmt.Sprintf(" of %s %s", idName, id)
+		}
+		return d
+	}
+	if total < 1 || number < 1 || number > total {
+		l.report(Error, CodeSequence, "%s is not a valid position in a sequence", describe(number, total, id))
+		return nil
+	}
+	seq, ok := l.sequences[key]
+	switch {
+	case ok && number == 1:
+		l.incomplete(seq)
+	case ok && (number != seq.next || total != seq.total || id != seq.id):
+		l.report(Error, CodeSequence, "%s out of sequence, expected %s", describe(number, total, id), describe(seq.next, seq.total, seq.id))
+		delete(l.sequences, key)
+		return nil
+	case !ok && number != 1:
+		l.report(Error, CodeSequence, "%s without the sentences before it", describe(number, total, id))
+		return nil
+	}
+	if number == 1 {
+		seq = &sequence{typ: typ, line: l.line, total: total, next: 1, id: id, idName: idName}
+		l.sequences[key] = seq
+	}
+	seq.next++
+	if number == total {
+		delete(l.sequences, key)
+	}
+	return seq
+}
+
+// incomplete reports a sequence missing its last sentences, on the line of
+// its first sentence.
+func (l *Linter) incomplete(seq *sequence) {
+	l.reportLine(seq.line, Warning, CodeSequence, "%s sequence incomplete, %d of %d sentences", seq.typ, seq.next-1, seq.total)
+}
+
+func sortedKeys(m map[string]*sequence) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,102 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequence(t *testing.T) {
+	var tests = []struct {
+		name   string
+		lines  []string
+		issues []Issue
+	}{
+		{
+			name: "complete",
+			lines: []string{
+				"$GPGSV,2,1,05,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,45*78",
+				"$GPGSV,2,2,05,15,45,120,44*4A",
+				"$GPGSV,1,1,01,15,45,120,44*4E",
+				"$IIRTE,2,1,c,0,W1,W2*10",
+				"$IIRTE,2,2,c,0,W3*58",
+				"!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C",
+				"!AIVDM,2,2,1,A,88888888880,2*25",
+			},
+		},
+		{
+			name: "incomplete",
+			lines: []string{
+				"$GPGSV,2,1,05,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,45*78",
+				"$GPGSV,2,1,05,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,45*78",
+				"$GPGSV,2,2,05,15,45,120,44*4A",
+			},
+			issues: []Issue{
+				{Line: 1, Severity: Warning, Code: CodeSequence, Message: "GSV sequence incomplete, 1 of 2 sentences"},
+			},
+		},
+		{
+			name: "out of sequence",
+			lines: []string{
+				"$GPGSV,2,1,05,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,45*78",
+				"$GPGSV,3,3,01,15,45,120,44*4E",
+				"$GPGSV,2,2,05,15,45,120,44*4A",
+			},
+			issues: []Issue{
+				{Line: 2, Severity: Error, Code: CodeSequence, Message: "GSV sentence 3 of 3 out of sequence, expected GSV sentence 2 of 2"},
+				{Line: 3, Severity: Error, Code: CodeSequence, Message: "GSV sentence 2 of 2 without the sentences before it"},
+			},
+		},
+		{
+			name: "invalid position",
+			lines: []string{
+				"$GPGSV,1,2,01,15,45,120,44*4D",
+			},
+			issues: []Issue{
+				{Line: 1, Severity: Error, Code: CodeSequence, Message: "GSV sentence 2 of 1 is not a valid position in a sequence"},
+			},
+		},
+		{
+			name: "satellites in view",
+			lines: []string{
+				"$GPGSV,2,1,05,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,45*78",
+				"$GPGSV,2,2,06,15,45,120,44*49",
+			},
+			issues: []Issue{
+				{Line: 2, Severity: Warning, Code: CodeSequence, Message: "GSV satellites in view changed from 5 to 6 within a sequence"},
+				{Line: 2, Severity: Warning, Code: CodeSequence, Message: "GSV sequence lists 5 satellites, 6 in view"},
+			},
+		},
+		{
+			name: "route changed",
+			lines: []string{
+				"$IIRTE,2,1,c,0,W1,W2*10",
+				"$IIRTE,2,2,c,1,W3*59",
+			},
+			issues: []Issue{
+				{Line: 2, Severity: Error, Code: CodeSequence, Message: "RTE sentence 2 of 2 of route 1 out of sequence, expected RTE sentence 2 of 2 of route 0"},
+			},
+		},
+		{
+			name: "message changed",
+			lines: []string{
+				"!AIVDM,2,1,2,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1F",
+				"!AIVDM,2,2,1,A,88888888880,2*25",
+			},
+			issues: []Issue{
+				{Line: 2, Severity: Error, Code: CodeSequence, Message: "VDM sentence 2 of 2 of message 1 out of sequence, expected VDM sentence 2 of 2 of message 2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLinter()
+			var issues []Issue
+			for _, line := range tt.lines {
+				issues = append(issues, l.Check(line)...)
+			}
+			issues = append(issues, l.Finish()...)
+			assert.Equal(t, tt.issues, issues)
+		})
+	}
+}
@@ -23,3 +23,13 @@ func newMTK(s BaseSentence) (MTK, error) {
 		Flag:         flag,
 	}, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s MTK) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *MTK) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
@@ -0,0 +1,67 @@
+package mux
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+)
+
+// maxDatagramSize is the size of the buffer datagrams are read into.
+const maxDatagramSize = 64 * 1024
+
+// ReadUDP routes the lines of the datagrams received on conn, until it is
+// closed. A datagram can hold several lines.
+func (m *Mux) ReadUDP(in Input, conn net.PacketConn) error {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		for _, text := range bytes.Split(buf[:n], []byte("\n")) {
+			m.Route(in, string(text))
+		}
+	}
+}
+
+// ListenTCP routes the lines sent by the clients connecting to l, until it
+// is closed.
+func (m *Mux) ListenTCP(in Input, l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			m.Read(in, conn)
+			conn.Close()
+		}()
+	}
+}
+
+// ServeTCP sends lines to the clients connecting to l, until it is closed.
+// Every client is an output named after the output and its address, with
+// the rules of the output. Clients are removed when they disconnect.
+func (m *Mux) ServeTCP(out Output, l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		client := out
+		client.Name = fmt.Sprintf("%s/%s", out.Name, conn.RemoteAddr())
+		if err := m.AddOutput(client, conn); err != nil {
+			conn.Close()
+			continue
+		}
+		go func() {
+			// Anything sent by the client is discarded, until it
+			// disconnects.
+			io.Copy(ioutil.Discard, conn)
+			m.RemoveOutput(client.Name)
+			conn.Close()
+		}()
+	}
+}
@@ -0,0 +1,73 @@
+package mux
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+func TestUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	m := NewMux()
+	var s sink
+	assert.NoError(t, m.AddOutput(Output{Name: "out"}, &s))
+	go m.ReadUDP(Input{Name: "udp"}, conn)
+	defer conn.Close()
+
+	out, err := net.Dial("udp", conn.LocalAddr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer out.Close()
+	fmt.Fprintf(out, "%s\r\n%s\r\n", rmc, gga)
+	assert.Equal(t, []string{rmc, gga}, s.wait(t, 2))
+}
+
+func TestTCP(t *testing.T) {
+	in, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer in.Close()
+	out, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer out.Close()
+
+	m := NewMux()
+	go m.ListenTCP(Input{Name: "tcp", Stamp: true}, in)
+	go m.ServeTCP(Output{Name: "clients", Rules: []Rule{{Types: []string{"RMC"}}}}, out)
+
+	client, err := net.Dial("tcp", out.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+	assert.Eventually(t, func() bool { return len(m.Stats()) == 1 }, 5*time.Second, time.Millisecond)
+
+	source, err := net.Dial("tcp", in.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	fmt.Fprintf(source, "%s\r\n%s\r\n", gga, rmc)
+	source.Close()
+
+	line, err := bufio.NewReader(client).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, nmea.TagBlock{Source: "tcp"}.String()+rmc+"\r\n", line)
+
+	// Clients disconnecting are removed.
+	client.Close()
+	assert.Eventually(t, func() bool { return len(m.Stats()) == 0 }, 5*time.Second, time.Millisecond)
+}
@@ -0,0 +1,320 @@
+// Package mux fans sentences between inputs and outputs, like kplex.
+//
+// Inputs are any io.Reader, or UDP and TCP endpoints, read line by line.
+// Every output has rules on the input, talker, sentence type, TAG Block
+// source and checksum validity of lines, deciding which lines it is sent.
+// Identical sentences received from redundant inputs can be dropped, and
+// lines can be stamped with the name of their input as TAG Block source.
+//
+// Every output has a queue of its own, so that a slow output does not hold
+// up the others: lines are dropped when the queue is full, or the inputs
+// wait for room when the output blocks.
+package mux
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// DefaultQueueSize is the number of lines queued for an output.
+const DefaultQueueSize = 1024
+
+// Input describes where lines come from.
+type Input struct {
+	Name  string // Name of the input, matched by the Inputs of rules
+	Stamp bool   // Set the TAG Block source of lines without one to Name
+}
+
+// Rule matches lines. Empty lists match any value.
+type Rule struct {
+	Inputs  []string // Names of inputs
+	Talkers []string // Talker IDs, e.g. GP
+	Types   []string // Sentence types, e.g. RMC
+	Sources []string // TAG Block sources
+	Invalid bool     // Also match lines failing checksum validation
+	Deny    bool     // Drop matching lines instead of sending them
+}
+
+// Output describes where lines go.
+type Output struct {
+	Name string // Name of the output, unique within a Mux
+
+	// Rules are tried in order and the first rule matching a line decides
+	// whether it is sent. Valid lines matching no rule are sent when every
+	// rule denies, and invalid lines are dropped.
+	Rules []Rule
+
+	// Block makes the inputs wait for room in the queue of the output,
+	// rather than dropping lines when it is full.
+	Block bool
+}
+
+// Stats holds the counters of an output.
+type Stats struct {
+	Written int64 // Number of lines written
+	Dropped int64 // Number of lines dropped as the queue was full
+}
+
+// Mux routes lines from inputs to outputs.
+type Mux struct {
+	Dedup     time.Duration // Drop sentences identical to one routed from another input within this window, 0 disables
+	QueueSize int           // Number of lines queued for outputs added later
+
+	mu      sync.Mutex
+	outputs map[string]*output
+	seen    map[string]seen
+	pruned  time.Time
+	now     func() time.Time
+}
+
+// seen is the last routing of a sentence, for deduplication.
+type seen struct {
+	input string
+	at    time.Time
+}
+
+// line is a line being routed.
+type line struct {
+	text   string
+	input  string
+	talker string
+	typ    string
+	source string
+	valid  bool
+}
+
+type output struct {
+	written int64 // First for the alignment of atomic operations
+	dropped int64
+
+	Output
+	w     io.Writer
+	queue chan string
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewMux constructor
+func NewMux() *Mux {
+	return &Mux{
+		QueueSize: DefaultQueueSize,
+		outputs:   map[string]*output{},
+		seen:      map[string]seen{},
+		now:       time.Now,
+	}
+}
+
+// AddOutput starts sending lines to w, each one in a single write. Writing
+// to a UDP connection thus sends a datagram per line. The output is removed
+// when a write fails. Outputs which do not block need a QueueSize of at
+// least 1.
+func (m *Mux) AddOutput(out Output, w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if o, ok := m.outputs[out.Name]; ok && !o.closed() {
+		return fmt.Errorf("mux: output %s already exists", out.Name)
+	}
+	if m.QueueSize < 0 || (m.QueueSize == 0 && !out.Block) {
+		return fmt.Errorf("mux: queue size %d is too small for output %s", m.QueueSize, out.Name)
+	}
+	o := &output{
+		Output: out,
+		w:      w,
+		queue:  make(chan string, m.QueueSize),
+		done:   make(chan struct{}),
+	}
+	m.outputs[out.Name] = o
+	go o.write()
+	return nil
+}
+
+// RemoveOutput stops sending lines to an output. Lines still queued are
+// dropped.
+func (m *Mux) RemoveOutput(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if o, ok := m.outputs[name]; ok {
+		o.close()
+		delete(m.outputs, name)
+	}
+}
+
+// Stats returns the counters of every output.
+func (m *Mux) Stats() map[string]Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make(map[string]Stats, len(m.outputs))
+	for name, o := range m.outputs {
+		stats[name] = Stats{
+			Written: atomic.LoadInt64(&o.written),
+			Dropped: atomic.LoadInt64(&o.dropped),
+		}
+	}
+	return stats
+}
+
+// Read routes the lines read from r until the end of the stream.
+func (m *Mux) Read(in Input, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m.Route(in, scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// Route sends a line from the input to the outputs whose rules allow it.
+func (m *Mux) Route(in Input, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	l := line{text: text, input: in.Name}
+	tags, raw, err := nmea.SplitTagBlock(text)
+	if err == nil {
+		l.source = tags.Source
+		if s, err := nmea.ParseBaseSentence(raw); err == nil {
+			l.talker, l.typ, l.valid = s.Talker, s.Type, true
+		}
+		if in.Stamp && tags.Source == "" {
+			tags.Source = in.Name
+			l.source = in.Name
+			l.text = tags.String() + raw
+		}
+	}
+	outputs, ok := m.match(l, raw)
+	if !ok {
+		return
+	}
+	for _, o := range outputs {
+		o.send(l.text)
+	}
+}
+
+// match returns the outputs allowing a line, or false for a duplicate: a
+// sentence routed from another input within the Dedup window. Repeated
+// sentences from a single input, like an unchanged heading, are not
+// duplicates.
+func (m *Mux) match(l line, raw string) ([]*output, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Dedup > 0 && l.valid {
+		now := m.now()
+		if last, ok := m.seen[raw]; ok && last.input != l.input && now.Sub(last.at) < m.Dedup {
+			return nil, false
+		}
+		m.seen[raw] = seen{l.input, now}
+		if now.Sub(m.pruned) >= m.Dedup {
+			for k, last := range m.seen {
+				if now.Sub(last.at) >= m.Dedup {
+					delete(m.seen, k)
+				}
+			}
+			m.pruned = now
+		}
+	}
+	var outputs []*output
+	for name, o := range m.outputs {
+		if o.closed() {
+			delete(m.outputs, name)
+			continue
+		}
+		if allowed(o.Rules, l) {
+			outputs = append(outputs, o)
+		}
+	}
+	return outputs, true
+}
+
+// allowed reports whether the rules allow a line.
+func allowed(rules []Rule, l line) bool {
+	for _, r := range rules {
+		if r.match(l) {
+			return !r.Deny
+		}
+	}
+	if !l.valid {
+		return false
+	}
+	for _, r := range rules {
+		if !r.Deny {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rule) match(l line) bool {
+	return (l.valid || r.Invalid) &&
+		contains(r.Inputs, l.input) &&
+		contains(r.Talkers, l.talker) &&
+		contains(r.Types, l.typ) &&
+		contains(r.Sources, l.source)
+}
+
+// contains reports whether the list is empty or holds the value.
+func contains(list []string, v string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// send queues a line, waiting for room if the output blocks.
+func (o *output) send(text string) {
+	if o.Block {
+		select {
+		case o.queue <- text:
+		case <-o.done:
+		}
+		return
+	}
+	select {
+	case o.queue <- text:
+	default:
+		atomic.AddInt64(&o.dropped, 1)
+	}
+}
+
+func (o *output) write() {
+	for {
+		select {
+		case text := <-o.queue:
+			if _, err := io.WriteString(o.w, text+"\r\n"); err != nil {
+				o.close()
+				return
+			}
+			atomic.AddInt64(&o.written, 1)
+		case <-o.done:
+			return
+		}
+	}
+}
+
+func (o *output) close() {
+	o.once.Do(func() { close(o.done) })
+}
+
+func (o *output) closed() bool {
+	select {
+	case <-o.done:
+		return true
+	default:
+		return false
+	}
+}
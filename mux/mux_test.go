@@ -0,0 +1,217 @@
+package mux
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// sink collects the lines written to an output.
+type sink struct {
+	mu    sync.Mutex
+	lines []string
+	block chan struct{}
+}
+
+func (s *sink) Write(p []byte) (int, error) {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lines = append(s.lines, strings.TrimRight(string(p), "\r\n"))
+	return len(p), nil
+}
+
+// wait returns the lines written once there are n of them.
+func (s *sink) wait(t *testing.T, n int) []string {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		lines := append([]string(nil), s.lines...)
+		s.mu.Unlock()
+		if len(lines) >= n {
+			return lines
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d lines", n)
+	return nil
+}
+
+var (
+	rmc     = "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70"
+	gga     = "$GPGGA,034225.077,3356.4650,S,15124.5567,E,1,03,9.7,-25.0,M,21.0,M,,0000*51"
+	hdt     = "$HEHDT,123.456,T*28"
+	pxyz    = "$PXYZ,1,2*08"
+	bad     = "$GPHDT,123.456,T*33"
+	tagged  = nmea.TagBlock{Source: "gps1"}.String() + rmc
+	routing = []string{rmc, gga, hdt, pxyz, bad, tagged}
+)
+
+var routetests = []struct {
+	name  string
+	rules []Rule
+	lines []string
+}{
+	{
+		name:  "no rules",
+		lines: []string{rmc, gga, hdt, pxyz, tagged},
+	},
+	{
+		name:  "types",
+		rules: []Rule{{Types: []string{"RMC", "HDT"}}},
+		lines: []string{rmc, hdt, tagged},
+	},
+	{
+		name:  "talkers",
+		rules: []Rule{{Talkers: []string{"HE"}}},
+		lines: []string{hdt},
+	},
+	{
+		name:  "deny",
+		rules: []Rule{{Types: []string{"GGA"}, Deny: true}, {Talkers: []string{"HE"}, Deny: true}},
+		lines: []string{rmc, pxyz, tagged},
+	},
+	{
+		name:  "first match decides",
+		rules: []Rule{{Sources: []string{"gps1"}, Deny: true}, {Types: []string{"RMC"}}},
+		lines: []string{rmc},
+	},
+	{
+		name:  "sources",
+		rules: []Rule{{Sources: []string{"gps1"}}},
+		lines: []string{tagged},
+	},
+	{
+		name:  "invalid",
+		rules: []Rule{{Invalid: true}},
+		lines: []string{rmc, gga, hdt, pxyz, bad, tagged},
+	},
+	{
+		name:  "inputs",
+		rules: []Rule{{Inputs: []string{"other"}}},
+		lines: nil,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	for _, tt := range routetests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMux()
+			var s sink
+			if !assert.NoError(t, m.AddOutput(Output{Name: "out", Rules: tt.rules}, &s)) {
+				return
+			}
+			assert.NoError(t, m.Read(Input{Name: "in"}, strings.NewReader(strings.Join(routing, "\r\n"))))
+			assert.Equal(t, tt.lines, s.wait(t, len(tt.lines)))
+		})
+	}
+}
+
+func TestDedup(t *testing.T) {
+	m := NewMux()
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	m.Dedup = time.Second
+
+	var s sink
+	assert.NoError(t, m.AddOutput(Output{Name: "out"}, &s))
+	m.Route(Input{Name: "a"}, rmc)
+	m.Route(Input{Name: "b"}, rmc)
+	m.Route(Input{Name: "b"}, tagged)
+	m.Route(Input{Name: "a"}, bad)
+	now = now.Add(time.Second)
+	m.Route(Input{Name: "a"}, rmc)
+	m.Route(Input{Name: "a"}, gga)
+	assert.Equal(t, []string{rmc, rmc, gga}, s.wait(t, 3))
+	assert.Len(t, m.seen, 2)
+}
+
+func TestDedupSameInput(t *testing.T) {
+	m := NewMux()
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	m.Dedup = time.Second
+
+	var s sink
+	assert.NoError(t, m.AddOutput(Output{Name: "out"}, &s))
+	m.Route(Input{Name: "a"}, rmc)
+	now = now.Add(100 * time.Millisecond)
+	m.Route(Input{Name: "a"}, rmc)
+	m.Route(Input{Name: "b"}, rmc)
+	assert.Equal(t, []string{rmc, rmc}, s.wait(t, 2))
+}
+
+func TestQueueSize(t *testing.T) {
+	m := NewMux()
+	var s sink
+	m.QueueSize = -1
+	assert.EqualError(t, m.AddOutput(Output{Name: "out", Block: true}, &s), "mux: queue size -1 is too small for output out")
+	m.QueueSize = 0
+	assert.EqualError(t, m.AddOutput(Output{Name: "out"}, &s), "mux: queue size 0 is too small for output out")
+	assert.NoError(t, m.AddOutput(Output{Name: "out", Block: true}, &s))
+	m.Route(Input{Name: "in"}, rmc)
+	assert.Equal(t, []string{rmc}, s.wait(t, 1))
+}
+
+func TestStamp(t *testing.T) {
+	m := NewMux()
+	var s sink
+	assert.NoError(t, m.AddOutput(Output{Name: "out", Rules: []Rule{{Sources: []string{"serial"}}}}, &s))
+	m.Route(Input{Name: "serial", Stamp: true}, rmc)
+	m.Route(Input{Name: "serial", Stamp: true}, tagged)
+	m.Route(Input{Name: "serial", Stamp: true}, gga)
+	assert.Equal(t, []string{
+		nmea.TagBlock{Source: "serial"}.String() + rmc,
+		nmea.TagBlock{Source: "serial"}.String() + gga,
+	}, s.wait(t, 2))
+}
+
+func TestBackpressure(t *testing.T) {
+	m := NewMux()
+	m.QueueSize = 2
+
+	slow := sink{block: make(chan struct{})}
+	blocking := sink{block: make(chan struct{})}
+	var fast sink
+	assert.NoError(t, m.AddOutput(Output{Name: "slow"}, &slow))
+	assert.NoError(t, m.AddOutput(Output{Name: "blocking", Block: true}, &blocking))
+	m.QueueSize = 16
+	assert.NoError(t, m.AddOutput(Output{Name: "fast"}, &fast))
+	assert.EqualError(t, m.AddOutput(Output{Name: "fast"}, &fast), "mux: output fast already exists")
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			m.Route(Input{Name: "in"}, rmc)
+		}
+		close(done)
+	}()
+	// The blocking output holds up the input once its queue is full.
+	fast.wait(t, 2)
+	select {
+	case <-done:
+		t.Fatal("input not held up by the blocking output")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(blocking.block)
+	<-done
+	close(slow.block)
+
+	// The slow output drops the lines not fitting in its queue.
+	dropped := m.Stats()["slow"].Dropped
+	assert.True(t, dropped > 0)
+	assert.Len(t, slow.wait(t, int(10-dropped)), int(10-dropped))
+	assert.Len(t, fast.wait(t, 10), 10)
+	assert.Len(t, blocking.wait(t, 10), 10)
+	assert.Eventually(t, func() bool {
+		return m.Stats()["fast"] == Stats{Written: 10}
+	}, 5*time.Second, time.Millisecond)
+}
@@ -0,0 +1,57 @@
+package nmea
+
+const (
+	// TypeMWV type for MWV sentences
+	TypeMWV = "MWV"
+	// RelativeMWV wind angle relative to the bow (apparent wind)
+	RelativeMWV = "R"
+	// TrueMWV wind angle relative to the bow, corrected for the vessel speed (true wind)
+	TrueMWV = "T"
+	// KPHMWV wind speed in kilometers per hour
+	KPHMWV = "K"
+	// MPSMWV wind speed in meters per second
+	MPSMWV = "M"
+	// KnotsMWV wind speed in knots
+	KnotsMWV = "N"
+	// MPHMWV wind speed in statute miles per hour
+	MPHMWV = "S"
+	// ValidMWV data valid
+	ValidMWV = "A"
+	// InvalidMWV data invalid
+	InvalidMWV = "V"
+)
+
+// MWV is the wind speed and angle.
+// https://gpsd.gitlab.io/gpsd/NMEA.html#_mwv_wind_speed_and_angle
+type MWV struct {
+	BaseSentence
+	WindAngle     float64 // Wind angle in degrees, clockwise from the bow
+	Reference     string  // Reference of the angle, relative or true
+	WindSpeed     float64 // Wind speed
+	WindSpeedUnit string  // Unit of the wind speed, K, M, N or S
+	Status        string  // Data status, A valid or V invalid
+}
+
+// newMWV constructor
+func newMWV(s BaseSentence) (MWV, error) {
+	p := NewParser(s)
+	p.AssertType(TypeMWV)
+	return MWV{
+		BaseSentence:  s,
+		WindAngle:     p.Float64(0, "wind angle"),
+		Reference:     p.EnumString(1, "reference", RelativeMWV, TrueMWV),
+		WindSpeed:     p.Float64(2, "wind speed"),
+		WindSpeedUnit: p.EnumString(3, "wind speed unit", KPHMWV, MPSMWV, KnotsMWV, MPHMWV),
+		Status:        p.EnumString(4, "status", ValidMWV, InvalidMWV),
+	}, p.Err()
+}
+
+// MarshalJSON implements json.Marshaler
+func (s MWV) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *MWV) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
@@ -0,0 +1,69 @@
+package nmea
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var mwvtests = []struct {
+	name string
+	raw  string
+	err  string
+	msg  MWV
+}{
+	{
+		name: "good sentence",
+		raw:  "$WIMWV,214.8,R,0.1,K,A*28",
+		msg: MWV{
+			WindAngle:     214.8,
+			Reference:     RelativeMWV,
+			WindSpeed:     0.1,
+			WindSpeedUnit: KPHMWV,
+			Status:        ValidMWV,
+		},
+	},
+	{
+		name: "true wind in knots",
+		raw:  "$WIMWV,12.5,T,8.3,N,A*18",
+		msg: MWV{
+			WindAngle:     12.5,
+			Reference:     TrueMWV,
+			WindSpeed:     8.3,
+			WindSpeedUnit: KnotsMWV,
+			Status:        ValidMWV,
+		},
+	},
+	{
+		name: "invalid reference",
+		raw:  "$WIMWV,214.8,X,0.1,K,A*22",
+		err:  "nmea: WIMWV invalid reference: X",
+	},
+	{
+		name: "invalid wind speed unit",
+		raw:  "$WIMWV,214.8,R,0.1,Q,A*32",
+		err:  "nmea: WIMWV invalid wind speed unit: Q",
+	},
+	{
+		name: "invalid status",
+		raw:  "$WIMWV,214.8,R,0.1,K,X*31",
+		err:  "nmea: WIMWV invalid status: X",
+	},
+}
+
+func TestMWV(t *testing.T) {
+	for _, tt := range mwvtests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(tt.raw)
+			if tt.err != "" {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				mwv := m.(MWV)
+				mwv.BaseSentence = BaseSentence{}
+				assert.Equal(t, tt.msg, mwv)
+			}
+		})
+	}
+}
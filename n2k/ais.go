@@ -0,0 +1,112 @@
+package n2k
+
+import (
+	"math"
+
+	"github.com/storskegg/go-nmea/ais"
+)
+
+// AISPositionReport is PGN 129038, a class A position report, or PGN
+// 129039, a class B position report when its message Type is 18.
+type AISPositionReport struct {
+	ais.PositionReport
+	RAIM bool // Receiver autonomous integrity monitoring in use
+}
+
+// PGN implements Payload
+func (p AISPositionReport) PGN() uint32 {
+	if p.Type == 18 {
+		return PGNAISClassBPosition
+	}
+	return PGNAISClassAPosition
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (p AISPositionReport) MarshalBinary() ([]byte, error) {
+	m := p.PositionReport
+	b := make([]byte, 28)
+	b[0] = uint8(m.Type)&0x3f | uint8(m.Repeat)<<6
+	putUint32(b[1:], m.MMSI)
+	putUint32(b[5:], notAvailableInt32)
+	putUint32(b[9:], notAvailableInt32)
+	if m.PositionValid {
+		putUint32(b[5:], uint32(int32(math.Round(m.Longitude/coordinateResolution))))
+		putUint32(b[9:], uint32(int32(math.Round(m.Latitude/coordinateResolution))))
+	}
+	b[13] = uint8(m.Timestamp) << 2
+	if m.PositionAccuracy {
+		b[13] |= 0x01
+	}
+	if p.RAIM {
+		b[13] |= 0x02
+	}
+	putUint16(b[14:], notAvailableUint16)
+	if m.CourseOverGroundValid {
+		putUint16(b[14:], angle(m.CourseOverGround))
+	}
+	putUint16(b[16:], notAvailableUint16)
+	if m.SpeedOverGroundValid {
+		putUint16(b[16:], uint16(math.Round(m.SpeedOverGround*knot/speedResolution)))
+	}
+	b[18], b[19], b[20] = 0xff, 0xff, 0xff // communication state and transceiver information
+	putUint16(b[21:], notAvailableUint16)
+	if m.TrueHeadingValid {
+		putUint16(b[21:], angle(float64(m.TrueHeading)))
+	}
+	if m.Type == 18 {
+		b[23], b[24], b[25] = 0xff, 0xff, 0xff
+		return b[:26], nil
+	}
+	putUint16(b[23:], notAvailableInt16)
+	if m.RateOfTurnValid {
+		// degrees per minute to radians per second
+		putUint16(b[23:], uint16(int16(math.Round(m.RateOfTurn*degree/60/rateOfTurnResolution))))
+	}
+	b[25] = uint8(m.NavigationStatus)&0x0f | 0xf0
+	b[26], b[27] = 0xff, 0xff
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (p *AISPositionReport) UnmarshalBinary(b []byte) error {
+	if err := checkLength(PGNAISClassBPosition, b, 23); err != nil {
+		return err
+	}
+	m := ais.PositionReport{
+		Header: ais.Header{
+			Type:   int(b[0] & 0x3f),
+			Repeat: int(b[0] >> 6),
+			MMSI:   getUint32(b[1:]),
+		},
+		NavigationStatus: ais.NotDefined,
+		PositionAccuracy: b[13]&0x01 != 0,
+		Timestamp:        int(b[13] >> 2),
+		Longitude:        181,
+		Latitude:         91,
+	}
+	if lon, lat := int32(getUint32(b[5:])), int32(getUint32(b[9:])); lon != notAvailableInt32 && lat != notAvailableInt32 {
+		m.Longitude = float64(lon) * coordinateResolution
+		m.Latitude = float64(lat) * coordinateResolution
+		m.PositionValid = true
+	}
+	if v := getUint16(b[14:]); v != notAvailableUint16 {
+		m.CourseOverGround, m.CourseOverGroundValid = degrees(float64(v)), true
+	}
+	if v := getUint16(b[16:]); v != notAvailableUint16 {
+		m.SpeedOverGround, m.SpeedOverGroundValid = float64(v)*speedResolution/knot, true
+	}
+	if v := getUint16(b[21:]); v != notAvailableUint16 {
+		m.TrueHeading, m.TrueHeadingValid = int(math.Round(degrees(float64(v))))%360, true
+	}
+	if m.Type != 18 {
+		if err := checkLength(PGNAISClassAPosition, b, 26); err != nil {
+			return err
+		}
+		if v := int16(getUint16(b[23:])); v != notAvailableInt16 {
+			m.RateOfTurn, m.RateOfTurnValid = float64(v)*rateOfTurnResolution/degree*60, true
+		}
+		m.NavigationStatus = int(b[25] & 0x0f)
+	}
+	*p = AISPositionReport{PositionReport: m, RAIM: b[13]&0x02 != 0}
+	return nil
+}
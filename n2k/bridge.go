@@ -0,0 +1,195 @@
+package n2k
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/ais"
+	"github.com/storskegg/go-nmea/coord"
+)
+
+// FromSentence returns the payloads carrying the data of a sentence:
+//
+//   - RMC gives PositionRapid, COGSOGRapid and SystemTime
+//   - GGA, GLL and GNS give PositionRapid
+//   - VTG gives COGSOGRapid
+//   - HDT and THS give Heading
+//   - DPT and DBT give Depth
+//   - MWV gives Wind
+//   - ZDA gives SystemTime
+//   - VDM and VDO with a class A or B position report give AISPositionReport
+//
+// Invalid sentences, sentences missing a value required by the payload and
+// other types give no payload.
+func FromSentence(s nmea.Sentence) []Payload {
+	switch m := s.(type) {
+	case nmea.RMC:
+		if m.Validity != nmea.ValidRMC {
+			return nil
+		}
+		payloads := []Payload{PositionRapid{Latitude: m.Latitude, Longitude: m.Longitude}}
+		if m.SpeedValid && m.CourseValid {
+			payloads = append(payloads, COGSOGRapid{Reference: ReferenceTrue, COG: m.Course, SOG: m.Speed})
+		}
+		if t, err := nmea.DateTime(nmea.DefaultCenturyPivot, m.Date, m.Time); err == nil {
+			payloads = append(payloads, SystemTime{Source: TimeSourceGPS, Time: t})
+		}
+		return payloads
+	case nmea.GGA:
+		if m.FixQuality == nmea.Invalid {
+			return nil
+		}
+		return []Payload{PositionRapid{Latitude: m.Latitude, Longitude: m.Longitude}}
+	case nmea.GLL:
+		if m.Validity != nmea.ValidGLL {
+			return nil
+		}
+		return []Payload{PositionRapid{Latitude: m.Latitude, Longitude: m.Longitude}}
+	case nmea.GNS:
+		return []Payload{PositionRapid{Latitude: m.Latitude, Longitude: m.Longitude}}
+	case nmea.VTG:
+		if m.Mode == nmea.NotValidVTG || !m.TrueTrackValid || !m.GroundSpeedKnotsValid {
+			return nil
+		}
+		return []Payload{COGSOGRapid{Reference: ReferenceTrue, COG: m.TrueTrack, SOG: m.GroundSpeedKnots}}
+	case nmea.HDT:
+		if !m.True {
+			return nil
+		}
+		return []Payload{Heading{Heading: m.Heading, Reference: ReferenceTrue}}
+	case nmea.THS:
+		if m.Status == nmea.InvalidTHS {
+			return nil
+		}
+		return []Payload{Heading{Heading: m.Heading, Reference: ReferenceTrue}}
+	case nmea.DPT:
+		if !m.DepthValid {
+			return nil
+		}
+		p := Depth{Depth: m.Depth, Offset: m.Offset, OffsetValid: m.OffsetValid}
+		if m.RangeScale != 0 {
+			p.Range, p.RangeValid = m.RangeScale, true
+		}
+		return []Payload{p}
+	case nmea.DBT:
+		if !m.DepthMetersValid {
+			return nil
+		}
+		return []Payload{Depth{Depth: m.DepthMeters}}
+	case nmea.MWV:
+		if m.Status != nmea.ValidMWV {
+			return nil
+		}
+		p := Wind{Angle: m.WindAngle, Reference: WindApparent}
+		if m.Reference == nmea.TrueMWV {
+			p.Reference = WindTrueBoat
+		}
+		switch m.WindSpeedUnit {
+		case nmea.KnotsMWV:
+			p.Speed = m.WindSpeed
+		case nmea.MPSMWV:
+			p.Speed = m.WindSpeed / knot
+		case nmea.KPHMWV:
+			p.Speed = m.WindSpeed / 3.6 / knot
+		case nmea.MPHMWV:
+			p.Speed = m.WindSpeed * 0.44704 / knot
+		}
+		return []Payload{p}
+	case nmea.ZDA:
+		t, err := m.DateTime()
+		if err != nil {
+			return nil
+		}
+		return []Payload{SystemTime{Source: TimeSourceGPS, Time: t}}
+	case nmea.VDMVDO:
+		if m.NumFragments > 1 {
+			return nil
+		}
+		msg, err := ais.Decode(m.Payload)
+		if err != nil {
+			return nil
+		}
+		if r, ok := msg.(ais.PositionReport); ok {
+			return []Payload{AISPositionReport{PositionReport: r}}
+		}
+	}
+	return nil
+}
+
+// ToSentence formats a payload as a sentence of the given talker:
+//
+//   - PositionRapid gives GLL, without a time
+//   - COGSOGRapid gives VTG
+//   - Heading gives HDT when true, or VHW when magnetic
+//   - Depth gives DPT
+//   - Wind gives MWV for apparent and boat referenced true wind
+//   - SystemTime gives ZDA
+//   - AISPositionReport gives VDM
+func ToSentence(p Payload, talker string) (nmea.Sentence, error) {
+	var prefix string
+	var fields []string
+	switch m := p.(type) {
+	case PositionRapid:
+		lat, latDir := coord.NMEALat(m.Latitude, coord.DefaultPrecision)
+		lon, lonDir := coord.NMEALon(m.Longitude, coord.DefaultPrecision)
+		prefix = nmea.TypeGLL
+		fields = []string{lat, latDir, lon, lonDir, "", nmea.ValidGLL, "A"}
+	case COGSOGRapid:
+		prefix = nmea.TypeVTG
+		cog := formatFloat(m.COG, 1)
+		fields = []string{cog, "T", "", "M", formatFloat(m.SOG, 2), "N", formatFloat(m.SOG*knot*3.6, 2), "K", "A"}
+		if m.Reference == ReferenceMagnetic {
+			fields[0], fields[2] = "", cog
+		}
+	case Heading:
+		if m.Reference == ReferenceMagnetic {
+			prefix = nmea.TypeVHW
+			fields = []string{"", "T", formatFloat(m.Heading, 1), "M", "", "N", "", "K"}
+		} else {
+			prefix = nmea.TypeHDT
+			fields = []string{formatFloat(m.Heading, 1), "T"}
+		}
+	case Depth:
+		prefix = nmea.TypeDPT
+		fields = []string{formatFloat(m.Depth, 2), "", ""}
+		if m.OffsetValid {
+			fields[1] = formatFloat(m.Offset, 3)
+		}
+		if m.RangeValid {
+			fields[2] = formatFloat(m.Range, 0)
+		}
+	case Wind:
+		prefix = nmea.TypeMWV
+		reference := nmea.RelativeMWV
+		switch m.Reference {
+		case WindApparent:
+		case WindTrueBoat, WindTrueWater:
+			reference = nmea.TrueMWV
+		default:
+			return nil, fmt.Errorf("n2k: wind reference %d not supported", m.Reference)
+		}
+		fields = []string{formatFloat(m.Angle, 1), reference, formatFloat(m.Speed, 2), nmea.KnotsMWV, nmea.ValidMWV}
+	case SystemTime:
+		t := m.Time.UTC()
+		prefix = nmea.TypeZDA
+		fields = []string{
+			fmt.Sprintf("%02d%02d%02d.%02d", t.Hour(), t.Minute(), t.Second(), t.Nanosecond()/1e7),
+			fmt.Sprintf("%02d", t.Day()), fmt.Sprintf("%02d", int(t.Month())), strconv.Itoa(t.Year()),
+			"00", "00",
+		}
+	case AISPositionReport:
+		payload, fill := ais.Armor(ais.EncodePositionReport(m.PositionReport))
+		return nmea.Parse(nmea.FormatSentence(nmea.SentenceStartEncapsulated, talker+nmea.TypeVDM, []string{
+			"1", "1", "", "A", payload, strconv.Itoa(fill),
+		}))
+	default:
+		return nil, fmt.Errorf("n2k: PGN %d not supported", p.PGN())
+	}
+	return nmea.Parse(nmea.FormatSentence(nmea.SentenceStart, talker+prefix, fields))
+}
+
+func formatFloat(v float64, precision int) string {
+	return strconv.FormatFloat(math.Round(v*math.Pow10(precision))/math.Pow10(precision), 'f', precision, 64)
+}
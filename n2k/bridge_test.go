@@ -0,0 +1,147 @@
+package n2k
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+var bridgetests = []struct {
+	name      string
+	raw       string
+	pgns      []uint32
+	sentences []string
+}{
+	{
+		name: "RMC",
+		raw:  "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70",
+		pgns: []uint32{PGNPositionRapid, PGNCOGSOGRapid, PGNSystemTime},
+		sentences: []string{
+			"$GPGLL,5133.8200,N,00042.2400,W,,A,A*5B",
+			"$GPVTG,231.8,T,,M,173.80,N,321.88,K,A*08",
+			"$GPZDA,220516.00,13,06,1994,00,00*65",
+		},
+	},
+	{
+		name:      "GGA",
+		raw:       "$GPGGA,034225.077,3356.4650,S,15124.5567,E,1,03,9.7,-25.0,M,21.0,M,,0000*51",
+		pgns:      []uint32{PGNPositionRapid},
+		sentences: []string{"$GPGLL,3356.4650,S,15124.5567,E,,A,A*5C"},
+	},
+	{
+		name:      "VTG",
+		raw:       "$GPVTG,45.5,T,67.5,M,30.45,N,56.40,K*4B",
+		pgns:      []uint32{PGNCOGSOGRapid},
+		sentences: []string{"$GPVTG,45.5,T,,M,30.44,N,56.38,K,A*32"},
+	},
+	{
+		name:      "HDT",
+		raw:       "$GPHDT,123.456,T*32",
+		pgns:      []uint32{PGNHeading},
+		sentences: []string{"$GPHDT,123.5,T*30"},
+	},
+	{
+		name:      "DPT",
+		raw:       "$SDDPT,10.5,-1.5,*66",
+		pgns:      []uint32{PGNDepth},
+		sentences: []string{"$SDDPT,10.50,-1.500,*56"},
+	},
+	{
+		name:      "DBT",
+		raw:       "$SDDBT,32.8,f,10.0,M,5.5,F*0E",
+		pgns:      []uint32{PGNDepth},
+		sentences: []string{"$SDDPT,10.00,,*54"},
+	},
+	{
+		name:      "MWV",
+		raw:       "$WIMWV,214.8,R,10.0,N,A*1D",
+		pgns:      []uint32{PGNWind},
+		sentences: []string{"$WIMWV,214.8,R,9.99,N,A*15"},
+	},
+	{
+		name:      "ZDA",
+		raw:       "$GPZDA,172809.456,12,07,1996,00,00*57",
+		pgns:      []uint32{PGNSystemTime},
+		sentences: []string{"$GPZDA,172809.45,12,07,1996,00,00*61"},
+	},
+	{
+		name:      "AIS",
+		raw:       "!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A",
+		pgns:      []uint32{PGNAISClassAPosition},
+		sentences: []string{"!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g20000,0*50"},
+	},
+	{
+		name: "invalid RMC",
+		raw:  "$GPRMC,220516,V,,,,,,,130694,,*3A",
+	},
+	{
+		name: "VTG without course",
+		raw:  "$GPVTG,,T,,M,30.45,N,56.40,K,A*26",
+	},
+	{
+		name: "VTG not valid",
+		raw:  "$GPVTG,45.5,T,67.5,M,30.45,N,56.40,K,N*29",
+	},
+	{
+		name: "DBT without depth",
+		raw:  "$SDDBT,,f,,M,,F*28",
+	},
+	{
+		name: "DPT without depth",
+		raw:  "$SDDPT,,,*7B",
+	},
+	{
+		name: "unsupported",
+		raw:  "$PGRME,3.3,M,4.9,M,6.0,M*25",
+	},
+}
+
+func TestBridge(t *testing.T) {
+	for _, tt := range bridgetests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := nmea.Parse(tt.raw)
+			if !assert.NoError(t, err) {
+				return
+			}
+			var pgns []uint32
+			var sentences []string
+			for _, p := range FromSentence(s) {
+				m, err := Encode(p)
+				if !assert.NoError(t, err) {
+					return
+				}
+				pgns = append(pgns, m.PGN)
+				decoded, err := Decode(m)
+				if !assert.NoError(t, err) {
+					return
+				}
+				out, err := ToSentence(decoded, s.TalkerID())
+				if !assert.NoError(t, err) {
+					return
+				}
+				sentences = append(sentences, out.String())
+			}
+			assert.Equal(t, tt.pgns, pgns)
+			assert.Equal(t, tt.sentences, sentences)
+		})
+	}
+}
+
+func TestToSentence(t *testing.T) {
+	s, err := ToSentence(Heading{Heading: 90, Reference: ReferenceMagnetic}, "HC")
+	assert.NoError(t, err)
+	assert.Equal(t, "$HCVHW,,T,90.0,M,,N,,K*49", s.String())
+
+	s, err = ToSentence(COGSOGRapid{COG: 10, SOG: 5, Reference: ReferenceMagnetic}, "GP")
+	assert.NoError(t, err)
+	assert.Equal(t, "$GPVTG,,T,10.0,M,5.00,N,9.26,K,A*34", s.String())
+
+	s, err = ToSentence(Wind{Angle: 12.5, Speed: 8, Reference: WindTrueWater}, "WI")
+	assert.NoError(t, err)
+	assert.Equal(t, nmea.TrueMWV, s.(nmea.MWV).Reference)
+
+	_, err = ToSentence(Wind{Angle: 12.5, Speed: 8, Reference: WindTrueNorth}, "WI")
+	assert.EqualError(t, err, "n2k: wind reference 0 not supported")
+}
@@ -0,0 +1,195 @@
+package n2k
+
+import (
+	"sync"
+	"time"
+)
+
+// FastPacket lists the PGNs sent as fast packets, split over several CAN
+// frames. Other PGNs are sent in a single frame.
+var FastPacket = map[uint32]bool{
+	126208: true, // NMEA group function
+	126464: true, // PGN list
+	126996: true, // Product information
+	126998: true, // Configuration information
+	127233: true, // Man overboard notification
+	127237: true, // Heading/track control
+	127489: true, // Engine parameters, dynamic
+	127496: true, // Trip parameters, vessel
+	127497: true, // Trip parameters, engine
+	127498: true, // Engine parameters, static
+	127503: true, // AC input status
+	127504: true, // AC output status
+	127506: true, // DC detailed status
+	127507: true, // Charger status
+	127509: true, // Inverter status
+	128275: true, // Distance log
+	129029: true, // GNSS position data
+	129038: true, // AIS class A position report
+	129039: true, // AIS class B position report
+	129040: true, // AIS class B extended position report
+	129041: true, // AIS aids to navigation report
+	129044: true, // Datum
+	129045: true, // User datum
+	129284: true, // Navigation data
+	129285: true, // Navigation route/waypoint information
+	129540: true, // GNSS satellites in view
+	129794: true, // AIS class A static and voyage related data
+	129795: true, // AIS addressed binary message
+	129797: true, // AIS binary broadcast message
+	129798: true, // AIS SAR aircraft position report
+	129809: true, // AIS class B static data, part A
+	129810: true, // AIS class B static data, part B
+	130074: true, // Route and WP service, WP list
+	130577: true, // Direction data
+}
+
+// Frame is a CAN frame of an NMEA 2000 network.
+type Frame struct {
+	ID   uint32    // 29 bit CAN identifier
+	Data []byte    // Up to 8 bytes
+	Time time.Time // Time given by the gateway
+}
+
+// pduFormat returns the PDU format of the identifier. Below 240, the PGN is
+// addressed and its low byte is the destination.
+func (f Frame) pduFormat() uint32 {
+	return (f.ID >> 16) & 0xff
+}
+
+// PGN returns the PGN of the frame.
+func (f Frame) PGN() uint32 {
+	pgn := (f.ID >> 8) & 0x3ffff
+	if f.pduFormat() < 240 {
+		pgn &^= 0xff
+	}
+	return pgn
+}
+
+// Priority returns the priority of the frame.
+func (f Frame) Priority() uint8 {
+	return uint8(f.ID>>26) & 0x07
+}
+
+// Source returns the source address of the frame.
+func (f Frame) Source() uint8 {
+	return uint8(f.ID)
+}
+
+// Destination returns the destination address of the frame.
+func (f Frame) Destination() uint8 {
+	if f.pduFormat() < 240 {
+		return uint8(f.ID >> 8)
+	}
+	return BroadcastAddress
+}
+
+// CANID returns the 29 bit CAN identifier of a message.
+func CANID(m Message) uint32 {
+	id := uint32(m.Priority&0x07)<<26 | (m.PGN&0x3ffff)<<8 | uint32(m.Source)
+	if (m.PGN>>8)&0xff < 240 {
+		id |= uint32(m.Destination) << 8
+	}
+	return id
+}
+
+// Frames splits a message into CAN frames, as fast packets with the given
+// sequence number when its PGN is a FastPacket.
+func Frames(m Message, sequence uint8) []Frame {
+	id := CANID(m)
+	if !FastPacket[m.PGN] {
+		return []Frame{{ID: id, Data: m.Data, Time: m.Time}}
+	}
+	seq := (sequence & 0x07) << 5
+	first := append([]byte{seq, byte(len(m.Data))}, m.Data[:min(6, len(m.Data))]...)
+	frames := []Frame{{ID: id, Data: pad(first), Time: m.Time}}
+	for i, index := 6, byte(1); i < len(m.Data); i, index = i+7, index+1 {
+		data := append([]byte{seq | index}, m.Data[i:min(i+7, len(m.Data))]...)
+		frames = append(frames, Frame{ID: id, Data: pad(data), Time: m.Time})
+	}
+	return frames
+}
+
+// pad fills a frame up to 8 bytes.
+func pad(data []byte) []byte {
+	for len(data) < 8 {
+		data = append(data, 0xff)
+	}
+	return data
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+type fastPacketKey struct {
+	source uint8
+	pgn    uint32
+}
+
+type fastPacket struct {
+	sequence byte
+	next     byte
+	length   int
+	data     []byte
+}
+
+// Assembler joins the frames of fast packets into messages.
+type Assembler struct {
+	mu      sync.Mutex
+	pending map[fastPacketKey]*fastPacket
+}
+
+// NewAssembler constructor
+func NewAssembler() *Assembler {
+	return &Assembler{pending: map[fastPacketKey]*fastPacket{}}
+}
+
+// Add adds a frame. When the frame completes a message, the message is
+// returned along with true. Frames arriving out of order drop the
+// incomplete message.
+func (a *Assembler) Add(f Frame) (Message, bool) {
+	m := Message{
+		PGN:         f.PGN(),
+		Priority:    f.Priority(),
+		Source:      f.Source(),
+		Destination: f.Destination(),
+		Data:        f.Data,
+		Time:        f.Time,
+	}
+	if !FastPacket[m.PGN] {
+		return m, true
+	}
+	if len(f.Data) < 2 {
+		return Message{}, false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := fastPacketKey{m.Source, m.PGN}
+	sequence, index := f.Data[0]>>5, f.Data[0]&0x1f
+	if index == 0 {
+		p := &fastPacket{sequence: sequence, next: 1, length: int(f.Data[1])}
+		p.data = append(p.data, f.Data[2:]...)
+		a.pending[key] = p
+	} else {
+		p, ok := a.pending[key]
+		if !ok || p.sequence != sequence || p.next != index {
+			delete(a.pending, key)
+			return Message{}, false
+		}
+		p.data = append(p.data, f.Data[1:]...)
+		p.next++
+	}
+	p := a.pending[key]
+	if len(p.data) < p.length {
+		return Message{}, false
+	}
+	delete(a.pending, key)
+	m.Data = p.data[:p.length]
+	return m, true
+}
@@ -0,0 +1,101 @@
+package n2k
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var frametests = []struct {
+	name        string
+	id          uint32
+	pgn         uint32
+	priority    uint8
+	source      uint8
+	destination uint8
+}{
+	{
+		name:        "broadcast",
+		id:          0x09F11209,
+		pgn:         PGNHeading,
+		priority:    2,
+		source:      0x09,
+		destination: BroadcastAddress,
+	},
+	{
+		name:        "fast packet",
+		id:          0x19F51323,
+		pgn:         128275,
+		priority:    6,
+		source:      0x23,
+		destination: BroadcastAddress,
+	},
+	{
+		name:        "addressed",
+		id:          0x18EA2301,
+		pgn:         59904,
+		priority:    6,
+		source:      0x01,
+		destination: 0x23,
+	},
+}
+
+func TestFrame(t *testing.T) {
+	for _, tt := range frametests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Frame{ID: tt.id}
+			assert.Equal(t, tt.pgn, f.PGN())
+			assert.Equal(t, tt.priority, f.Priority())
+			assert.Equal(t, tt.source, f.Source())
+			assert.Equal(t, tt.destination, f.Destination())
+			assert.Equal(t, tt.id, CANID(Message{
+				PGN:         tt.pgn,
+				Priority:    tt.priority,
+				Source:      tt.source,
+				Destination: tt.destination,
+			}))
+		})
+	}
+}
+
+func TestFastPacket(t *testing.T) {
+	m := Message{
+		PGN:         PGNAISClassAPosition,
+		Priority:    4,
+		Source:      0x2a,
+		Destination: BroadcastAddress,
+		Data:        make([]byte, 28),
+	}
+	for i := range m.Data {
+		m.Data[i] = byte(i)
+	}
+	frames := Frames(m, 3)
+	if !assert.Len(t, frames, 5) {
+		return
+	}
+	assert.Equal(t, []byte{0x60, 28, 0, 1, 2, 3, 4, 5}, frames[0].Data)
+	assert.Equal(t, []byte{0x61, 6, 7, 8, 9, 10, 11, 12}, frames[1].Data)
+	assert.Equal(t, []byte{0x64, 27, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, frames[4].Data)
+
+	a := NewAssembler()
+	for _, f := range frames[:4] {
+		_, ok := a.Add(f)
+		assert.False(t, ok)
+	}
+	out, ok := a.Add(frames[4])
+	assert.True(t, ok)
+	assert.Equal(t, m, out)
+
+	// A frame out of order drops the message.
+	a.Add(frames[0])
+	_, ok = a.Add(frames[2])
+	assert.False(t, ok)
+	_, ok = a.Add(frames[3])
+	assert.False(t, ok)
+
+	single := Frames(Message{PGN: PGNHeading, Priority: 2, Source: 9, Destination: BroadcastAddress, Data: []byte{1, 2, 3}}, 0)
+	assert.Len(t, single, 1)
+	out, ok = a.Add(single[0])
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1, 2, 3}, out.Data)
+}
@@ -0,0 +1,153 @@
+package n2k
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// timeOfDay is the layout of the times of Yacht Devices RAW lines.
+const timeOfDay = "15:04:05.000"
+
+// ParseYDRAW parses a frame in the Yacht Devices RAW format, e.g.
+//
+//	17:33:21.107 R 19F51323 01 02 03 04 05 06 07 08
+//
+// with the time of day, the direction (R received or T transmitted), the
+// CAN identifier and data bytes in hexadecimal.
+func ParseYDRAW(line string) (Frame, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || (fields[1] != "R" && fields[1] != "T") || len(fields) > 11 {
+		return Frame{}, invalidLine("Yacht Devices RAW", line)
+	}
+	t, err := time.Parse(timeOfDay, fields[0])
+	if err != nil {
+		return Frame{}, invalidLine("Yacht Devices RAW", line)
+	}
+	id, err := strconv.ParseUint(fields[2], 16, 29)
+	if err != nil {
+		return Frame{}, invalidLine("Yacht Devices RAW", line)
+	}
+	data, err := hex.DecodeString(strings.Join(fields[3:], ""))
+	if err != nil || len(data) != len(fields)-3 {
+		return Frame{}, invalidLine("Yacht Devices RAW", line)
+	}
+	return Frame{ID: uint32(id), Data: data, Time: t}, nil
+}
+
+// FormatYDRAW formats a frame in the Yacht Devices RAW format, as sent to
+// the gateway.
+func FormatYDRAW(f Frame) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s T %08X", f.Time.Format(timeOfDay), f.ID)
+	for _, v := range f.Data {
+		fmt.Fprintf(&b, " %02X", v)
+	}
+	return b.String()
+}
+
+// ParseN2KASCII parses a message in the Actisense N2K ASCII format, e.g.
+//
+//	A173321.107 23FF7 1F513 012F3070002F30709F
+//
+// with the time of day, the source, destination and priority, the PGN and
+// the payload in hexadecimal.
+func ParseN2KASCII(line string) (Message, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 || !strings.HasPrefix(fields[0], "A") || len(fields[1]) != 5 {
+		return Message{}, invalidLine("N2K ASCII", line)
+	}
+	t, err := time.Parse("150405.000", fields[0][1:])
+	if err != nil {
+		return Message{}, invalidLine("N2K ASCII", line)
+	}
+	address, err := strconv.ParseUint(fields[1], 16, 32)
+	if err != nil {
+		return Message{}, invalidLine("N2K ASCII", line)
+	}
+	pgn, err := strconv.ParseUint(fields[2], 16, 32)
+	if err != nil {
+		return Message{}, invalidLine("N2K ASCII", line)
+	}
+	data, err := hex.DecodeString(fields[3])
+	if err != nil {
+		return Message{}, invalidLine("N2K ASCII", line)
+	}
+	return Message{
+		PGN:         uint32(pgn),
+		Priority:    uint8(address & 0x0f),
+		Source:      uint8(address >> 12),
+		Destination: uint8(address >> 4),
+		Data:        data,
+		Time:        t,
+	}, nil
+}
+
+// ParseActisense parses a message in the comma separated format of the
+// Actisense NGT-1 tools and canboat, e.g.
+//
+//	2016-04-09T16:41:09.078Z,3,127257,17,255,8,00,ff,7f,52,00,21,fe,ff
+//
+// with the timestamp, priority, PGN, source, destination, length and
+// payload bytes in hexadecimal.
+func ParseActisense(line string) (Message, error) {
+	fields := strings.Split(strings.TrimSpace(line), ",")
+	if len(fields) < 6 {
+		return Message{}, invalidLine("Actisense", line)
+	}
+	t, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		t, err = time.Parse("2006-01-02-15:04:05.000", fields[0])
+	}
+	if err != nil {
+		return Message{}, invalidLine("Actisense", line)
+	}
+	var values [5]uint64
+	for i := range values {
+		if values[i], err = strconv.ParseUint(fields[i+1], 10, 32); err != nil {
+			return Message{}, invalidLine("Actisense", line)
+		}
+	}
+	data, err := hex.DecodeString(strings.Join(fields[6:], ""))
+	if err != nil || len(data) != int(values[4]) || len(data) != len(fields)-6 {
+		return Message{}, invalidLine("Actisense", line)
+	}
+	return Message{
+		PGN:         uint32(values[1]),
+		Priority:    uint8(values[0]),
+		Source:      uint8(values[2]),
+		Destination: uint8(values[3]),
+		Data:        data,
+		Time:        t,
+	}, nil
+}
+
+// FromPCDIN returns the message wrapped in a PCDIN sentence. The priority
+// is the default of its PGN, as PCDIN does not carry it.
+func FromPCDIN(s nmea.PCDIN) Message {
+	return Message{
+		PGN:         uint32(s.PGN),
+		Priority:    priority(uint32(s.PGN)),
+		Source:      uint8(s.Source),
+		Destination: BroadcastAddress,
+		Data:        s.Data,
+	}
+}
+
+// FormatPCDIN wraps a message in a PCDIN sentence, without a timestamp.
+func FormatPCDIN(m Message) string {
+	return nmea.FormatSentence(nmea.SentenceStart, "P"+nmea.TypePCDIN, []string{
+		fmt.Sprintf("%06X", m.PGN),
+		"00000000",
+		fmt.Sprintf("%02X", m.Source),
+		strings.ToUpper(hex.EncodeToString(m.Data)),
+	})
+}
+
+func invalidLine(format, line string) error {
+	return fmt.Errorf("n2k: invalid %s line: %s", format, line)
+}
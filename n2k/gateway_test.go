@@ -0,0 +1,176 @@
+package n2k
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+func timeOfDayAt(hour, min, sec, msec int) time.Time {
+	return time.Date(0, 1, 1, hour, min, sec, msec*1e6, time.UTC)
+}
+
+var ydrawtests = []struct {
+	name  string
+	raw   string
+	frame Frame
+	err   string
+}{
+	{
+		name: "heading",
+		raw:  "17:33:21.107 R 09F11209 00 2B 54 FF 7F FF 7F FC",
+		frame: Frame{
+			ID:   0x09F11209,
+			Data: []byte{0x00, 0x2b, 0x54, 0xff, 0x7f, 0xff, 0x7f, 0xfc},
+			Time: timeOfDayAt(17, 33, 21, 107),
+		},
+	},
+	{
+		name: "transmitted",
+		raw:  "00:00:01.000 T 19F51323 01 02",
+		frame: Frame{
+			ID:   0x19F51323,
+			Data: []byte{0x01, 0x02},
+			Time: timeOfDayAt(0, 0, 1, 0),
+		},
+	},
+	{
+		name: "bad direction",
+		raw:  "17:33:21.107 X 09F11209 00",
+		err:  "n2k: invalid Yacht Devices RAW line: 17:33:21.107 X 09F11209 00",
+	},
+	{
+		name: "bad identifier",
+		raw:  "17:33:21.107 R 29F11209 00",
+		err:  "n2k: invalid Yacht Devices RAW line: 17:33:21.107 R 29F11209 00",
+	},
+	{
+		name: "bad data",
+		raw:  "17:33:21.107 R 09F11209 0G",
+		err:  "n2k: invalid Yacht Devices RAW line: 17:33:21.107 R 09F11209 0G",
+	},
+}
+
+func TestYDRAW(t *testing.T) {
+	for _, tt := range ydrawtests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseYDRAW(tt.raw)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.frame, f)
+			assert.Equal(t, tt.raw[:13]+"T"+tt.raw[14:], FormatYDRAW(f))
+		})
+	}
+}
+
+var gatewaytests = []struct {
+	name    string
+	raw     string
+	parse   func(string) (Message, error)
+	message Message
+	err     string
+}{
+	{
+		name:  "N2K ASCII",
+		raw:   "A173321.107 23FF7 1F513 012F3070002F30709F",
+		parse: ParseN2KASCII,
+		message: Message{
+			PGN:         128275,
+			Priority:    7,
+			Source:      0x23,
+			Destination: BroadcastAddress,
+			Data:        []byte{0x01, 0x2f, 0x30, 0x70, 0x00, 0x2f, 0x30, 0x70, 0x9f},
+			Time:        timeOfDayAt(17, 33, 21, 107),
+		},
+	},
+	{
+		name:  "N2K ASCII bad address",
+		raw:   "A173321.107 23F7 1F513 012F",
+		parse: ParseN2KASCII,
+		err:   "n2k: invalid N2K ASCII line: A173321.107 23F7 1F513 012F",
+	},
+	{
+		name:  "N2K ASCII bad time",
+		raw:   "A1733 23FF7 1F513 012F",
+		parse: ParseN2KASCII,
+		err:   "n2k: invalid N2K ASCII line: A1733 23FF7 1F513 012F",
+	},
+	{
+		name:  "Actisense",
+		raw:   "2016-04-09T16:41:09.078Z,3,127257,17,255,8,00,ff,7f,52,00,21,fe,ff",
+		parse: ParseActisense,
+		message: Message{
+			PGN:         127257,
+			Priority:    3,
+			Source:      17,
+			Destination: BroadcastAddress,
+			Data:        []byte{0x00, 0xff, 0x7f, 0x52, 0x00, 0x21, 0xfe, 0xff},
+			Time:        time.Date(2016, 4, 9, 16, 41, 9, 78e6, time.UTC),
+		},
+	},
+	{
+		name:  "Actisense analyzer time",
+		raw:   "2016-04-09-16:41:09.078,2,127250,9,255,3,00,2b,54",
+		parse: ParseActisense,
+		message: Message{
+			PGN:         PGNHeading,
+			Priority:    2,
+			Source:      9,
+			Destination: BroadcastAddress,
+			Data:        []byte{0x00, 0x2b, 0x54},
+			Time:        time.Date(2016, 4, 9, 16, 41, 9, 78e6, time.UTC),
+		},
+	},
+	{
+		name:  "Actisense bad length",
+		raw:   "2016-04-09T16:41:09.078Z,3,127257,17,255,8,00,ff",
+		parse: ParseActisense,
+		err:   "n2k: invalid Actisense line: 2016-04-09T16:41:09.078Z,3,127257,17,255,8,00,ff",
+	},
+	{
+		name:  "Actisense bad PGN",
+		raw:   "2016-04-09T16:41:09.078Z,3,x,17,255,1,00",
+		parse: ParseActisense,
+		err:   "n2k: invalid Actisense line: 2016-04-09T16:41:09.078Z,3,x,17,255,1,00",
+	},
+}
+
+func TestGateway(t *testing.T) {
+	for _, tt := range gatewaytests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := tt.parse(tt.raw)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.message, m)
+			}
+		})
+	}
+}
+
+func TestPCDIN(t *testing.T) {
+	m := Message{
+		PGN:         PGNHeading,
+		Priority:    2,
+		Source:      9,
+		Destination: BroadcastAddress,
+		Data:        []byte{0x00, 0x2b, 0x54, 0xff, 0x7f, 0xff, 0x7f, 0xfc},
+	}
+	raw := FormatPCDIN(m)
+	assert.Equal(t, "$PCDIN,01F112,00000000,09,002B54FF7FFF7FFC*58", raw)
+	s, err := nmea.Parse(raw)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, m, FromPCDIN(s.(nmea.PCDIN)))
+}
@@ -0,0 +1,176 @@
+// Package n2k encodes and decodes the payloads of common NMEA 2000 PGNs,
+// and bridges them to and from NMEA 0183 sentences.
+//
+// Payloads are the data bytes of a message, already reassembled from fast
+// packet frames where needed. Values are held in the units of the rest of
+// the library, degrees, knots and meters, and converted from and to the SI
+// units and resolutions of NMEA 2000 when encoding and decoding. Optional
+// fields have a Valid companion which is false when the value is not
+// available.
+package n2k
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// PGNs supported by Encode and Decode.
+const (
+	PGNSystemTime        = 126992
+	PGNHeading           = 127250
+	PGNDepth             = 128267
+	PGNPositionRapid     = 129025
+	PGNCOGSOGRapid       = 129026
+	PGNAISClassAPosition = 129038
+	PGNAISClassBPosition = 129039
+	PGNWind              = 130306
+)
+
+const (
+	// BroadcastAddress is the destination of messages sent to all devices.
+	BroadcastAddress = 255
+
+	// DefaultPriority is the priority of PGNs without a default of their own.
+	DefaultPriority = 6
+)
+
+// Resolutions and special values of NMEA 2000 fields.
+const (
+	knot                  = 1852.0 / 3600 // Meters per second
+	degree                = math.Pi / 180 // Radians
+	angleResolution       = 1e-4          // Radians
+	rateOfTurnResolution  = 3.125e-5      // Radians per second
+	speedResolution       = 0.01          // Meters per second
+	coordinateResolution  = 1e-7          // Degrees
+	depthResolution       = 0.01          // Meters
+	depthOffsetResolution = 0.001         // Meters
+	depthRangeResolution  = 10            // Meters
+	timeResolution        = 1e-4          // Seconds
+	secondsPerDay         = 86400
+
+	notAvailableUint8  = 0xff
+	notAvailableUint16 = 0xffff
+	notAvailableInt16  = 0x7fff
+	notAvailableUint32 = 0xffffffff
+	notAvailableInt32  = 0x7fffffff
+)
+
+var (
+	// ErrNotAvailable is returned when decoding a payload whose main value
+	// is not available.
+	ErrNotAvailable = errors.New("n2k: value not available")
+
+	// priorities are the default priorities of the PGNs.
+	priorities = map[uint32]uint8{
+		PGNSystemTime:        3,
+		PGNHeading:           2,
+		PGNDepth:             3,
+		PGNPositionRapid:     2,
+		PGNCOGSOGRapid:       2,
+		PGNAISClassAPosition: 4,
+		PGNAISClassBPosition: 4,
+		PGNWind:              2,
+	}
+)
+
+// Message is an NMEA 2000 message.
+type Message struct {
+	PGN         uint32
+	Priority    uint8
+	Source      uint8 // Source address
+	Destination uint8 // Destination address, BroadcastAddress for all
+	Data        []byte
+	Time        time.Time // Time given by the gateway, on January 1 of year 0 when it only gives a time of day
+}
+
+// Payload is the decoded payload of a PGN.
+type Payload interface {
+	encoding.BinaryMarshaler
+	PGN() uint32
+}
+
+// Encode returns the broadcast message of a payload, with the default
+// priority of its PGN.
+func Encode(p Payload) (Message, error) {
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		PGN:         p.PGN(),
+		Priority:    priority(p.PGN()),
+		Destination: BroadcastAddress,
+		Data:        data,
+	}, nil
+}
+
+// Decode decodes the payload of a message.
+func Decode(m Message) (Payload, error) {
+	var p encoding.BinaryUnmarshaler
+	switch m.PGN {
+	case PGNSystemTime:
+		p = &SystemTime{}
+	case PGNHeading:
+		p = &Heading{}
+	case PGNDepth:
+		p = &Depth{}
+	case PGNPositionRapid:
+		p = &PositionRapid{}
+	case PGNCOGSOGRapid:
+		p = &COGSOGRapid{}
+	case PGNAISClassAPosition, PGNAISClassBPosition:
+		p = &AISPositionReport{}
+	case PGNWind:
+		p = &Wind{}
+	default:
+		return nil, fmt.Errorf("n2k: PGN %d not supported", m.PGN)
+	}
+	if err := p.UnmarshalBinary(m.Data); err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(p).Elem().Interface().(Payload), nil
+}
+
+// priority returns the default priority of a PGN.
+func priority(pgn uint32) uint8 {
+	if p, ok := priorities[pgn]; ok {
+		return p
+	}
+	return DefaultPriority
+}
+
+// checkLength returns an error if a payload is shorter than its PGN.
+func checkLength(pgn uint32, data []byte, length int) error {
+	if len(data) < length {
+		return fmt.Errorf("n2k: PGN %d payload too short: %d bytes", pgn, len(data))
+	}
+	return nil
+}
+
+// angle encodes an angle in degrees as an unsigned angle in [0, 2π).
+func angle(deg float64) uint16 {
+	rad := math.Mod(deg, 360) * degree
+	if rad < 0 {
+		rad += 2 * math.Pi
+	}
+	return uint16(math.Round(rad / angleResolution))
+}
+
+// signedAngle encodes an angle in degrees as a signed angle.
+func signedAngle(deg float64) int16 {
+	return int16(math.Round(deg * degree / angleResolution))
+}
+
+func degrees(v float64) float64 {
+	return v * angleResolution / degree
+}
+
+func putUint16(b []byte, v uint16) { binary.LittleEndian.PutUint16(b, v) }
+func putUint32(b []byte, v uint32) { binary.LittleEndian.PutUint32(b, v) }
+func getUint16(b []byte) uint16    { return binary.LittleEndian.Uint16(b) }
+func getUint32(b []byte) uint32    { return binary.LittleEndian.Uint32(b) }
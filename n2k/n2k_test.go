@@ -0,0 +1,152 @@
+package n2k
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/storskegg/go-nmea/ais"
+)
+
+var pgntests = []struct {
+	name    string
+	payload Payload
+	data    []byte
+	decoded Payload
+}{
+	{
+		name:    "system time",
+		payload: SystemTime{SID: 1, Source: TimeSourceGPS, Time: time.Date(2020, 1, 2, 3, 4, 5, 250e6, time.UTC)},
+		data:    []byte{0x01, 0xf0, 0x57, 0x47, 0x14, 0x5f, 0x95, 0x06},
+	},
+	{
+		name:    "true heading",
+		payload: Heading{Heading: 123.456, Reference: ReferenceTrue},
+		data:    []byte{0x00, 0x2b, 0x54, 0xff, 0x7f, 0xff, 0x7f, 0xfc},
+		decoded: Heading{Heading: 123.45521611683849, Reference: ReferenceTrue},
+	},
+	{
+		name:    "magnetic heading with variation",
+		payload: Heading{SID: 7, Heading: 90, Variation: -4.2, VariationValid: true, Reference: ReferenceMagnetic},
+		data:    []byte{0x07, 0x5c, 0x3d, 0xff, 0x7f, 0x23, 0xfd, 0xfd},
+		decoded: Heading{SID: 7, Heading: 90.00021045914971, Variation: -4.199780638308934, VariationValid: true, Reference: ReferenceMagnetic},
+	},
+	{
+		name:    "depth",
+		payload: Depth{Depth: 10.5, Offset: -1.5, OffsetValid: true},
+		data:    []byte{0x00, 0x1a, 0x04, 0x00, 0x00, 0x24, 0xfa, 0xff},
+	},
+	{
+		name:    "depth with range",
+		payload: Depth{Depth: 3.25, Range: 100, RangeValid: true},
+		data:    []byte{0x00, 0x45, 0x01, 0x00, 0x00, 0xff, 0x7f, 0x0a},
+	},
+	{
+		name:    "position",
+		payload: PositionRapid{Latitude: 51.5636667, Longitude: -0.704},
+		data:    []byte{0xbb, 0xfd, 0xbb, 0x1e, 0x00, 0x94, 0x94, 0xff},
+	},
+	{
+		name:    "course and speed",
+		payload: COGSOGRapid{SID: 2, COG: 231.8, SOG: 10},
+		data:    []byte{0x02, 0xfc, 0x09, 0x9e, 0x02, 0x02, 0xff, 0xff},
+		decoded: COGSOGRapid{SID: 2, COG: 231.80153517607715, SOG: 9.991360691144708},
+	},
+	{
+		name:    "apparent wind",
+		payload: Wind{Speed: 10, Angle: 270, Reference: WindApparent},
+		data:    []byte{0x00, 0x02, 0x02, 0x14, 0xb8, 0xfa, 0xff, 0xff},
+		decoded: Wind{Speed: 9.991360691144708, Angle: 270.00063137744917, Reference: WindApparent},
+	},
+}
+
+func TestPGN(t *testing.T) {
+	for _, tt := range pgntests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Encode(tt.payload)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.payload.PGN(), m.PGN)
+			assert.Equal(t, uint8(BroadcastAddress), m.Destination)
+			assert.Equal(t, tt.data, m.Data)
+
+			p, err := Decode(m)
+			assert.NoError(t, err)
+			if tt.decoded != nil {
+				assert.Equal(t, tt.decoded, p)
+			} else {
+				assert.Equal(t, tt.payload, p)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, err := Decode(Message{PGN: 60928, Data: make([]byte, 8)})
+	assert.EqualError(t, err, "n2k: PGN 60928 not supported")
+
+	_, err = Decode(Message{PGN: PGNHeading, Data: []byte{0x00, 0x2b}})
+	assert.EqualError(t, err, "n2k: PGN 127250 payload too short: 2 bytes")
+
+	_, err = Decode(Message{PGN: PGNPositionRapid, Data: []byte{0xff, 0xff, 0xff, 0x7f, 0xff, 0xff, 0xff, 0x7f}})
+	assert.Equal(t, ErrNotAvailable, err)
+}
+
+func TestAISPositionReport(t *testing.T) {
+	classA := AISPositionReport{PositionReport: ais.PositionReport{
+		Header:                ais.Header{Type: 1, MMSI: 371798000},
+		NavigationStatus:      ais.UnderWaySailing,
+		RateOfTurn:            -2.5,
+		RateOfTurnValid:       true,
+		SpeedOverGround:       12.3,
+		SpeedOverGroundValid:  true,
+		PositionAccuracy:      true,
+		Longitude:             -123.3953833,
+		Latitude:              48.3816333,
+		PositionValid:         true,
+		CourseOverGround:      224,
+		CourseOverGroundValid: true,
+		TrueHeading:           215,
+		TrueHeadingValid:      true,
+		Timestamp:             33,
+	}}
+	m, err := Encode(classA)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint32(PGNAISClassAPosition), m.PGN)
+	assert.Len(t, m.Data, 28)
+	p, err := Decode(m)
+	if !assert.NoError(t, err) {
+		return
+	}
+	decoded := p.(AISPositionReport)
+	assert.InDelta(t, -2.5, decoded.RateOfTurn, 0.1)
+	assert.InDelta(t, 12.3, decoded.SpeedOverGround, 0.01)
+	assert.InDelta(t, 224, decoded.CourseOverGround, 0.01)
+	assert.InDelta(t, -123.3953833, decoded.Longitude, 1e-7)
+	decoded.RateOfTurn = classA.RateOfTurn
+	decoded.Longitude = classA.Longitude
+	decoded.SpeedOverGround = classA.SpeedOverGround
+	decoded.CourseOverGround = classA.CourseOverGround
+	assert.Equal(t, classA, decoded)
+
+	classB := AISPositionReport{PositionReport: ais.PositionReport{
+		Header:           ais.Header{Type: 18, MMSI: 367430530},
+		NavigationStatus: ais.NotDefined,
+		Longitude:        181,
+		Latitude:         91,
+		Timestamp:        60,
+	}, RAIM: true}
+	m, err = Encode(classB)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint32(PGNAISClassBPosition), m.PGN)
+	assert.Len(t, m.Data, 26)
+	p, err = Decode(m)
+	assert.NoError(t, err)
+	assert.Equal(t, classB, p)
+}
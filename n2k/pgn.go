@@ -0,0 +1,280 @@
+package n2k
+
+import (
+	"math"
+	"time"
+)
+
+// Heading and direction references.
+const (
+	ReferenceTrue     = 0
+	ReferenceMagnetic = 1
+)
+
+// Wind references.
+const (
+	WindTrueNorth = 0 // True wind, referenced to true north
+	WindMagnetic  = 1 // True wind, referenced to magnetic north
+	WindApparent  = 2 // Apparent wind, relative to the bow
+	WindTrueBoat  = 3 // True wind, relative to the bow
+	WindTrueWater = 4 // True wind, relative to the bow and corrected for current
+)
+
+// Time sources of SystemTime.
+const (
+	TimeSourceGPS     = 0
+	TimeSourceGLONASS = 1
+	TimeSourceRadio   = 2
+	TimeSourceLocal   = 3
+)
+
+// SystemTime is PGN 126992, the UTC date and time.
+type SystemTime struct {
+	SID    uint8
+	Source uint8 // Time source, e.g. TimeSourceGPS
+	Time   time.Time
+}
+
+// PGN implements Payload
+func (p SystemTime) PGN() uint32 { return PGNSystemTime }
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (p SystemTime) MarshalBinary() ([]byte, error) {
+	t := p.Time.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	b := make([]byte, 8)
+	b[0] = p.SID
+	b[1] = p.Source&0x0f | 0xf0
+	putUint16(b[2:], uint16(midnight.Unix()/secondsPerDay))
+	putUint32(b[4:], uint32(t.Sub(midnight).Seconds()/timeResolution+0.5))
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (p *SystemTime) UnmarshalBinary(b []byte) error {
+	if err := checkLength(PGNSystemTime, b, 8); err != nil {
+		return err
+	}
+	days, ticks := getUint16(b[2:]), getUint32(b[4:])
+	if days == notAvailableUint16 || ticks == notAvailableUint32 {
+		return ErrNotAvailable
+	}
+	*p = SystemTime{
+		SID:    b[0],
+		Source: b[1] & 0x0f,
+		Time:   time.Unix(int64(days)*secondsPerDay, int64(ticks)*int64(timeResolution*1e9)).UTC(),
+	}
+	return nil
+}
+
+// Heading is PGN 127250, the vessel heading.
+type Heading struct {
+	SID            uint8
+	Heading        float64 // Degrees
+	Deviation      float64 // Degrees, positive easterly
+	DeviationValid bool
+	Variation      float64 // Degrees, positive easterly
+	VariationValid bool
+	Reference      uint8 // ReferenceTrue or ReferenceMagnetic
+}
+
+// PGN implements Payload
+func (p Heading) PGN() uint32 { return PGNHeading }
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (p Heading) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	b[0] = p.SID
+	putUint16(b[1:], angle(p.Heading))
+	putUint16(b[3:], notAvailableInt16)
+	if p.DeviationValid {
+		putUint16(b[3:], uint16(signedAngle(p.Deviation)))
+	}
+	putUint16(b[5:], notAvailableInt16)
+	if p.VariationValid {
+		putUint16(b[5:], uint16(signedAngle(p.Variation)))
+	}
+	b[7] = p.Reference&0x03 | 0xfc
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (p *Heading) UnmarshalBinary(b []byte) error {
+	if err := checkLength(PGNHeading, b, 8); err != nil {
+		return err
+	}
+	heading := getUint16(b[1:])
+	if heading == notAvailableUint16 {
+		return ErrNotAvailable
+	}
+	*p = Heading{SID: b[0], Heading: degrees(float64(heading)), Reference: b[7] & 0x03}
+	if v := int16(getUint16(b[3:])); v != notAvailableInt16 {
+		p.Deviation, p.DeviationValid = degrees(float64(v)), true
+	}
+	if v := int16(getUint16(b[5:])); v != notAvailableInt16 {
+		p.Variation, p.VariationValid = degrees(float64(v)), true
+	}
+	return nil
+}
+
+// Depth is PGN 128267, the water depth below the transducer.
+type Depth struct {
+	SID         uint8
+	Depth       float64 // Meters below the transducer
+	Offset      float64 // Meters, positive from the transducer to the surface, negative to the keel
+	OffsetValid bool
+	Range       float64 // Maximum range of the sounder in meters
+	RangeValid  bool
+}
+
+// PGN implements Payload
+func (p Depth) PGN() uint32 { return PGNDepth }
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (p Depth) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	b[0] = p.SID
+	putUint32(b[1:], uint32(math.Round(p.Depth/depthResolution)))
+	putUint16(b[5:], notAvailableInt16)
+	if p.OffsetValid {
+		putUint16(b[5:], uint16(int16(math.Round(p.Offset/depthOffsetResolution))))
+	}
+	b[7] = notAvailableUint8
+	if p.RangeValid {
+		b[7] = uint8(math.Round(p.Range / depthRangeResolution))
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (p *Depth) UnmarshalBinary(b []byte) error {
+	if err := checkLength(PGNDepth, b, 8); err != nil {
+		return err
+	}
+	depth := getUint32(b[1:])
+	if depth == notAvailableUint32 {
+		return ErrNotAvailable
+	}
+	*p = Depth{SID: b[0], Depth: float64(depth) * depthResolution}
+	if v := int16(getUint16(b[5:])); v != notAvailableInt16 {
+		p.Offset, p.OffsetValid = float64(v)*depthOffsetResolution, true
+	}
+	if b[7] != notAvailableUint8 {
+		p.Range, p.RangeValid = float64(b[7])*depthRangeResolution, true
+	}
+	return nil
+}
+
+// PositionRapid is PGN 129025, the position sent at a high rate.
+type PositionRapid struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// PGN implements Payload
+func (p PositionRapid) PGN() uint32 { return PGNPositionRapid }
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (p PositionRapid) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	putUint32(b[0:], uint32(int32(math.Round(p.Latitude/coordinateResolution))))
+	putUint32(b[4:], uint32(int32(math.Round(p.Longitude/coordinateResolution))))
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (p *PositionRapid) UnmarshalBinary(b []byte) error {
+	if err := checkLength(PGNPositionRapid, b, 8); err != nil {
+		return err
+	}
+	lat, lon := int32(getUint32(b[0:])), int32(getUint32(b[4:]))
+	if lat == notAvailableInt32 || lon == notAvailableInt32 {
+		return ErrNotAvailable
+	}
+	*p = PositionRapid{
+		Latitude:  float64(lat) * coordinateResolution,
+		Longitude: float64(lon) * coordinateResolution,
+	}
+	return nil
+}
+
+// COGSOGRapid is PGN 129026, the course and speed over ground sent at a high
+// rate.
+type COGSOGRapid struct {
+	SID       uint8
+	Reference uint8   // ReferenceTrue or ReferenceMagnetic
+	COG       float64 // Course over ground in degrees
+	SOG       float64 // Speed over ground in knots
+}
+
+// PGN implements Payload
+func (p COGSOGRapid) PGN() uint32 { return PGNCOGSOGRapid }
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (p COGSOGRapid) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	b[0] = p.SID
+	b[1] = p.Reference&0x03 | 0xfc
+	putUint16(b[2:], angle(p.COG))
+	putUint16(b[4:], uint16(math.Round(p.SOG*knot/speedResolution)))
+	putUint16(b[6:], 0xffff)
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (p *COGSOGRapid) UnmarshalBinary(b []byte) error {
+	if err := checkLength(PGNCOGSOGRapid, b, 6); err != nil {
+		return err
+	}
+	cog, sog := getUint16(b[2:]), getUint16(b[4:])
+	if cog == notAvailableUint16 || sog == notAvailableUint16 {
+		return ErrNotAvailable
+	}
+	*p = COGSOGRapid{
+		SID:       b[0],
+		Reference: b[1] & 0x03,
+		COG:       degrees(float64(cog)),
+		SOG:       float64(sog) * speedResolution / knot,
+	}
+	return nil
+}
+
+// Wind is PGN 130306, the wind speed and angle.
+type Wind struct {
+	SID       uint8
+	Speed     float64 // Knots
+	Angle     float64 // Degrees, clockwise from the bow or north depending on the reference
+	Reference uint8   // WindApparent, WindTrueBoat, ...
+}
+
+// PGN implements Payload
+func (p Wind) PGN() uint32 { return PGNWind }
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (p Wind) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	b[0] = p.SID
+	putUint16(b[1:], uint16(math.Round(p.Speed*knot/speedResolution)))
+	putUint16(b[3:], angle(p.Angle))
+	b[5] = p.Reference&0x07 | 0xf8
+	putUint16(b[6:], 0xffff)
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (p *Wind) UnmarshalBinary(b []byte) error {
+	if err := checkLength(PGNWind, b, 6); err != nil {
+		return err
+	}
+	speed, angle := getUint16(b[1:]), getUint16(b[3:])
+	if speed == notAvailableUint16 || angle == notAvailableUint16 {
+		return ErrNotAvailable
+	}
+	*p = Wind{
+		SID:       b[0],
+		Speed:     float64(speed) * speedResolution / knot,
+		Angle:     degrees(float64(angle)),
+		Reference: b[5] & 0x07,
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package n2k
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// Reader reads messages from a text stream of gateway lines, telling the
+// framing of each line apart: Yacht Devices RAW frames, which are joined
+// into messages when sent as fast packets, Actisense N2K ASCII and comma
+// separated messages, and PCDIN sentences. Other lines, like NMEA 0183
+// sentences, are skipped.
+type Reader struct {
+	scanner *bufio.Scanner
+	frames  *Assembler
+}
+
+// NewReader constructor
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		scanner: bufio.NewScanner(r),
+		frames:  NewAssembler(),
+	}
+}
+
+// Read returns the next message, or io.EOF at the end of the stream. A
+// malformed line returns an error and reading can go on with the next one.
+func (r *Reader) Read() (Message, error) {
+	for r.scanner.Scan() {
+		line := strings.TrimSpace(r.scanner.Text())
+		switch {
+		case isYDRAW(line):
+			f, err := ParseYDRAW(line)
+			if err != nil {
+				return Message{}, err
+			}
+			if m, ok := r.frames.Add(f); ok {
+				return m, nil
+			}
+		case isN2KASCII(line):
+			return ParseN2KASCII(line)
+		case isActisense(line):
+			return ParseActisense(line)
+		case strings.Contains(line, "PCDIN,"):
+			s, err := nmea.Parse(line)
+			if err != nil {
+				return Message{}, err
+			}
+			if pcdin, ok := s.(nmea.PCDIN); ok {
+				return FromPCDIN(pcdin), nil
+			}
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Message{}, err
+	}
+	return Message{}, io.EOF
+}
+
+// isYDRAW reports whether a line starts with a time of day and a direction.
+func isYDRAW(line string) bool {
+	return len(line) > 15 && line[2] == ':' && line[5] == ':' &&
+		(strings.HasPrefix(line[12:], " R ") || strings.HasPrefix(line[12:], " T "))
+}
+
+// isN2KASCII reports whether a line starts with A and a time of day.
+func isN2KASCII(line string) bool {
+	return len(line) > 11 && line[0] == 'A' && isDigits(line[1:7]) && line[7] == '.'
+}
+
+// isActisense reports whether a line starts with a date.
+func isActisense(line string) bool {
+	return len(line) > 11 && isDigits(line[:4]) && line[4] == '-' && line[7] == '-' && strings.Count(line, ",") >= 5
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package n2k
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReader(t *testing.T) {
+	ais := Message{
+		PGN:         PGNAISClassAPosition,
+		Priority:    4,
+		Source:      0x2a,
+		Destination: BroadcastAddress,
+		Data:        make([]byte, 28),
+		Time:        timeOfDayAt(10, 0, 0, 0),
+	}
+	lines := []string{
+		"$GPHDT,123.456,T*32",
+		"",
+		"17:33:21.107 R 09F11209 00 2B 54 FF 7F FF 7F FC",
+	}
+	for _, f := range Frames(ais, 1) {
+		lines = append(lines, FormatYDRAW(f))
+	}
+	lines = append(lines,
+		"A173321.107 23FF7 1F513 012F3070002F30709F",
+		"2016-04-09T16:41:09.078Z,3,127257,17,255,8,00,ff,7f,52,00,21,fe,ff",
+		"2016-04-09T16:41:09.078Z,3,127257,17,255,8,00,ff",
+		"$PCDIN,01F112,00000000,09,002B54FF7FFF7FFC*58",
+	)
+	r := NewReader(strings.NewReader(strings.Join(lines, "\r\n")))
+
+	var pgns []uint32
+	var errors int
+	for {
+		m, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errors++
+			continue
+		}
+		pgns = append(pgns, m.PGN)
+		if m.PGN == PGNAISClassAPosition {
+			assert.Equal(t, ais, m)
+		}
+	}
+	assert.Equal(t, []uint32{PGNHeading, PGNAISClassAPosition, 128275, 127257, PGNHeading}, pgns)
+	assert.Equal(t, 1, errors)
+}
@@ -1,6 +1,7 @@
 package nmea
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strconv"
 )
@@ -138,6 +139,28 @@ func (p *Parser) Float64(i int, context string) float64 {
 	return v
 }
 
+// NullInt64 returns the int64 value at the specified index.
+// If the value is an empty string, the result is marked as invalid.
+func (p *Parser) NullInt64(i int, context string) NullInt64 {
+	s := p.String(i, context)
+	if p.err != nil || s == "" {
+		return NullInt64{}
+	}
+	v := p.Int64(i, context)
+	return NullInt64{Value: v, Valid: p.err == nil}
+}
+
+// NullFloat64 returns the float64 value at the specified index.
+// If the value is an empty string, the result is marked as invalid.
+func (p *Parser) NullFloat64(i int, context string) NullFloat64 {
+	s := p.String(i, context)
+	if p.err != nil || s == "" {
+		return NullFloat64{}
+	}
+	v := p.Float64(i, context)
+	return NullFloat64{Value: v, Valid: p.err == nil}
+}
+
 // Time returns the Time value at the specified index.
 // If the value is empty, the Time is marked as invalid.
 func (p *Parser) Time(i int, context string) Time {
@@ -230,3 +253,34 @@ func (p *Parser) SixBitASCIIArmour(i int, fillBits int, context string) []byte {
 
 	return result
 }
+
+// HexInt64 returns the int64 value of the hexadecimal field at the specified
+// index. If the value is an empty string, 0 is returned.
+func (p *Parser) HexInt64(i int, context string) int64 {
+	s := p.String(i, context)
+	if p.err != nil {
+		return 0
+	}
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 16, 64)
+	if err != nil {
+		p.SetErr(context, s)
+	}
+	return v
+}
+
+// HexBytes returns the bytes of the hexadecimal field at the specified index.
+func (p *Parser) HexBytes(i int, context string) []byte {
+	s := p.String(i, context)
+	if p.err != nil {
+		return nil
+	}
+	v, err := hex.DecodeString(s)
+	if err != nil {
+		p.SetErr(context, s)
+		return nil
+	}
+	return v
+}
@@ -189,6 +189,66 @@ var parsertests = []struct {
 			return p.Float64(0, "context")
 		},
 	},
+	{
+		name:     "NullInt64",
+		fields:   []string{"123"},
+		expected: NullInt64{Value: 123, Valid: true},
+		parse: func(p *Parser) interface{} {
+			return p.NullInt64(0, "context")
+		},
+	},
+	{
+		name:     "NullInt64 empty field is invalid",
+		fields:   []string{""},
+		expected: NullInt64{},
+		parse: func(p *Parser) interface{} {
+			return p.NullInt64(0, "context")
+		},
+	},
+	{
+		name:     "NullInt64 invalid",
+		fields:   []string{"abc"},
+		expected: NullInt64{},
+		hasErr:   true,
+		parse: func(p *Parser) interface{} {
+			return p.NullInt64(0, "context")
+		},
+	},
+	{
+		name:     "NullFloat64",
+		fields:   []string{"0"},
+		expected: NullFloat64{Value: 0, Valid: true},
+		parse: func(p *Parser) interface{} {
+			return p.NullFloat64(0, "context")
+		},
+	},
+	{
+		name:     "NullFloat64 empty field is invalid",
+		fields:   []string{""},
+		expected: NullFloat64{},
+		parse: func(p *Parser) interface{} {
+			return p.NullFloat64(0, "context")
+		},
+	},
+	{
+		name:     "NullFloat64 invalid",
+		fields:   []string{"abc"},
+		expected: NullFloat64{},
+		hasErr:   true,
+		parse: func(p *Parser) interface{} {
+			return p.NullFloat64(0, "context")
+		},
+	},
+	{
+		name:     "NullFloat64 with existing error",
+		fields:   []string{"123.123"},
+		expected: NullFloat64{},
+		hasErr:   true,
+		parse: func(p *Parser) interface{} {
+			p.SetErr("context", "value")
+			return p.NullFloat64(0, "context")
+		},
+	},
 	{
 		name:     "Time",
 		fields:   []string{"123456"},
@@ -295,6 +355,58 @@ var parsertests = []struct {
 			return p.LatLong(0, 1, "context")
 		},
 	},
+	{
+		name:     "HexInt64",
+		fields:   []string{"01F119"},
+		expected: int64(127257),
+		parse: func(p *Parser) interface{} {
+			return p.HexInt64(0, "context")
+		},
+	},
+	{
+		name:     "HexInt64 empty field is zero",
+		fields:   []string{""},
+		expected: int64(0),
+		parse: func(p *Parser) interface{} {
+			return p.HexInt64(0, "context")
+		},
+	},
+	{
+		name:     "HexInt64 invalid",
+		fields:   []string{"0G"},
+		expected: int64(0),
+		hasErr:   true,
+		parse: func(p *Parser) interface{} {
+			return p.HexInt64(0, "context")
+		},
+	},
+	{
+		name:     "HexBytes",
+		fields:   []string{"2AAF00d1"},
+		expected: []byte{0x2a, 0xaf, 0x00, 0xd1},
+		parse: func(p *Parser) interface{} {
+			return p.HexBytes(0, "context")
+		},
+	},
+	{
+		name:     "HexBytes odd length",
+		fields:   []string{"2AA"},
+		expected: []byte(nil),
+		hasErr:   true,
+		parse: func(p *Parser) interface{} {
+			return p.HexBytes(0, "context")
+		},
+	},
+	{
+		name:     "HexBytes with existing error",
+		fields:   []string{"2AAF"},
+		expected: []byte(nil),
+		hasErr:   true,
+		parse: func(p *Parser) interface{} {
+			p.SetErr("context", "value")
+			return p.HexBytes(0, "context")
+		},
+	},
 }
 
 func TestParser(t *testing.T) {
@@ -0,0 +1,40 @@
+package nmea
+
+const (
+	// TypePCDIN type for PCDIN sentences
+	TypePCDIN = "CDIN"
+)
+
+// PCDIN is an NMEA 2000 message wrapped in a sentence by SeaSmart gateways.
+// The n2k package decodes its payload.
+// http://www.seasmart.net/pdf/SeaSmart_HTTP_Protocol_RevG_043012.pdf
+type PCDIN struct {
+	BaseSentence
+	PGN       int64  // Parameter group number
+	Timestamp int64  // Gateway timestamp, 0 when not available
+	Source    int64  // Source address of the message
+	Data      []byte // Payload of the message
+}
+
+// newPCDIN constructor
+func newPCDIN(s BaseSentence) (PCDIN, error) {
+	p := NewParser(s)
+	p.AssertType(TypePCDIN)
+	return PCDIN{
+		BaseSentence: s,
+		PGN:          p.HexInt64(0, "pgn"),
+		Timestamp:    p.HexInt64(1, "timestamp"),
+		Source:       p.HexInt64(2, "source"),
+		Data:         p.HexBytes(3, "data"),
+	}, p.Err()
+}
+
+// MarshalJSON implements json.Marshaler
+func (s PCDIN) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *PCDIN) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
@@ -0,0 +1,61 @@
+package nmea
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var pcdintests = []struct {
+	name string
+	raw  string
+	err  string
+	msg  PCDIN
+}{
+	{
+		name: "good sentence",
+		raw:  "$PCDIN,01F119,00000000,0F,2AAF00D1067414FF*59",
+		msg: PCDIN{
+			PGN:    127257,
+			Source: 15,
+			Data:   []byte{0x2a, 0xaf, 0x00, 0xd1, 0x06, 0x74, 0x14, 0xff},
+		},
+	},
+	{
+		name: "with timestamp",
+		raw:  "$PCDIN,01F112,000C72EA,09,002B54FF7FFF7FFC*2A",
+		msg: PCDIN{
+			PGN:       127250,
+			Timestamp: 815850,
+			Source:    9,
+			Data:      []byte{0x00, 0x2b, 0x54, 0xff, 0x7f, 0xff, 0x7f, 0xfc},
+		},
+	},
+	{
+		name: "invalid pgn",
+		raw:  "$PCDIN,01F11G,00000000,0F,2AAF00D1067414FF*27",
+		err:  "nmea: PCDIN invalid pgn: 01F11G",
+	},
+	{
+		name: "invalid data",
+		raw:  "$PCDIN,01F119,00000000,0F,2AAF00D1067414F*1F",
+		err:  "nmea: PCDIN invalid data: 2AAF00D1067414F",
+	},
+}
+
+func TestPCDIN(t *testing.T) {
+	for _, tt := range pcdintests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(tt.raw)
+			if tt.err != "" {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				pcdin := m.(PCDIN)
+				pcdin.BaseSentence = BaseSentence{}
+				assert.Equal(t, tt.msg, pcdin)
+			}
+		})
+	}
+}
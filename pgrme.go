@@ -37,3 +37,13 @@ func newPGRME(s BaseSentence) (PGRME, error) {
 		Spherical:    spherical,
 	}, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s PGRME) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *PGRME) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
@@ -0,0 +1,33 @@
+package nmea
+
+const (
+	// TypePGRMM type for PGRMM sentences
+	TypePGRMM = "GRMM"
+)
+
+// PGRMM is the map datum in use (Garmin proprietary sentence)
+// https://www8.garmin.com/support/pdf/NMEA_0183.pdf
+type PGRMM struct {
+	BaseSentence
+	Datum string // Name of the currently active datum, e.g. WGS 84
+}
+
+// newPGRMM constructor
+func newPGRMM(s BaseSentence) (PGRMM, error) {
+	p := NewParser(s)
+	p.AssertType(TypePGRMM)
+	return PGRMM{
+		BaseSentence: s,
+		Datum:        p.String(0, "datum"),
+	}, p.Err()
+}
+
+// MarshalJSON implements json.Marshaler
+func (s PGRMM) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *PGRMM) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
@@ -0,0 +1,167 @@
+package replay
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultMaxDelay is the longest wait between two lines, so that gaps in a
+// recording are skipped.
+const DefaultMaxDelay = 10 * time.Second
+
+// Player writes the lines of a log at the pace they were recorded.
+type Player struct {
+	Speed    float64       // Speed-up factor, e.g. 2 plays twice as fast, or 0 to play without waiting
+	Loop     bool          // Start over at the end of the log
+	MaxDelay time.Duration // Longest wait between two lines, longer gaps are shortened
+
+	log   Log
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+
+	mu      sync.Mutex
+	pos     int       // Index of the next line
+	last    time.Time // Time of the line played last
+	hasLast bool
+	paused  bool
+	stopped bool
+	changed chan struct{} // Closed on every pause, resume, seek or stop
+}
+
+// NewPlayer constructor
+func NewPlayer(l Log) *Player {
+	return &Player{
+		Speed:    1,
+		MaxDelay: DefaultMaxDelay,
+		log:      l,
+		now:      time.Now,
+		after:    time.After,
+		changed:  make(chan struct{}),
+	}
+}
+
+// Play writes the lines to w, each one in a single write, until the end of
+// the log or until the player is stopped.
+func (p *Player) Play(w io.Writer) error {
+	target := p.now()
+	for {
+		p.mu.Lock()
+		changed := p.changed
+		if p.stopped {
+			p.mu.Unlock()
+			return nil
+		}
+		if p.paused {
+			p.mu.Unlock()
+			<-changed
+			target = p.now()
+			continue
+		}
+		if p.pos >= len(p.log.Entries) {
+			if !p.Loop || len(p.log.Entries) == 0 {
+				p.mu.Unlock()
+				return nil
+			}
+			p.pos, p.hasLast = 0, false
+		}
+		e := p.log.Entries[p.pos]
+		target = target.Add(p.delay(e.Time))
+		p.mu.Unlock()
+
+		if d := target.Sub(p.now()); d > 0 {
+			select {
+			case <-p.after(d):
+			case <-changed:
+				target = p.now()
+				continue
+			}
+		}
+
+		p.mu.Lock()
+		if p.changed != changed {
+			p.mu.Unlock()
+			target = p.now()
+			continue
+		}
+		p.pos++
+		p.last, p.hasLast = e.Time, true
+		p.mu.Unlock()
+
+		if _, err := io.WriteString(w, e.Line+"\r\n"); err != nil {
+			return err
+		}
+	}
+}
+
+// delay returns the wait before a line recorded at t.
+func (p *Player) delay(t time.Time) time.Duration {
+	if !p.hasLast || p.Speed <= 0 {
+		return 0
+	}
+	d := t.Sub(p.last)
+	if d < 0 {
+		d = 0
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return time.Duration(float64(d) / p.Speed)
+}
+
+// Pause pauses the playback.
+func (p *Player) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.paused = true
+	p.notify()
+}
+
+// Resume resumes the playback after a pause.
+func (p *Player) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.paused = false
+	p.notify()
+}
+
+// Paused reports whether the playback is paused.
+func (p *Player) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.paused
+}
+
+// Seek moves the playback to the first line recorded at or after t.
+func (p *Player) Seek(t time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pos, p.hasLast = p.log.Index(t), false
+	p.notify()
+}
+
+// Position returns the time of the line played last.
+func (p *Player) Position() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.last
+}
+
+// Stop ends the playback.
+func (p *Player) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopped = true
+	p.notify()
+}
+
+func (p *Player) notify() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
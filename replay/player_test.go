@@ -0,0 +1,169 @@
+package replay
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lines collects the lines written by a player.
+type lines struct {
+	mu      sync.Mutex
+	lines   []string
+	written chan struct{}
+	fail    int // Number of writes before failing, or 0
+}
+
+func newLines() *lines {
+	return &lines{written: make(chan struct{}, 100)}
+}
+
+func (l *lines) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.fail > 0 && len(l.lines) == l.fail {
+		return 0, errors.New("write failed")
+	}
+	l.lines = append(l.lines, strings.TrimSuffix(string(p), "\r\n"))
+	l.written <- struct{}{}
+	return len(p), nil
+}
+
+func (l *lines) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]string(nil), l.lines...)
+}
+
+func logOf(offsets ...time.Duration) Log {
+	var l Log
+	for i, d := range offsets {
+		l.Entries = append(l.Entries, Entry{Line: string(rune('A' + i)), Time: at(12, 0, 0).Add(d)})
+	}
+	return l
+}
+
+// fakeClock returns immediately from waits and records them.
+func fakeClock(p *Player) *[]time.Duration {
+	var waits []time.Duration
+	now := at(0, 0, 0)
+	p.now = func() time.Time { return now }
+	p.after = func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		now = now.Add(d)
+		ch := make(chan time.Time, 1)
+		ch <- now
+		return ch
+	}
+	return &waits
+}
+
+var playtests = []struct {
+	name  string
+	speed float64
+	loop  bool
+	lines []string
+	waits []time.Duration
+}{
+	{
+		name:  "original pace",
+		speed: 1,
+		lines: []string{"A", "B", "C", "D", "E", "F"},
+		waits: []time.Duration{time.Second, 2 * time.Second, 10 * time.Second},
+	},
+	{
+		name:  "faster",
+		speed: 4,
+		lines: []string{"A", "B", "C", "D", "E", "F"},
+		waits: []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, 2500 * time.Millisecond},
+	},
+	{
+		name:  "without waiting",
+		speed: 0,
+		lines: []string{"A", "B", "C", "D", "E", "F"},
+	},
+	{
+		name:  "loop",
+		speed: 1,
+		loop:  true,
+		lines: []string{"A", "B", "C", "D", "E", "F", "A", "B", "C"},
+		waits: []time.Duration{time.Second, 2 * time.Second, 10 * time.Second, time.Second, 2 * time.Second},
+	},
+}
+
+func TestPlay(t *testing.T) {
+	for _, tt := range playtests {
+		t.Run(tt.name, func(t *testing.T) {
+			// E goes back in time and the gap to F exceeds the maximum delay.
+			p := NewPlayer(logOf(0, 0, time.Second, 3*time.Second, time.Second, time.Hour))
+			p.Speed, p.Loop = tt.speed, tt.loop
+			waits := fakeClock(p)
+			w := newLines()
+			if tt.loop {
+				w.fail = len(tt.lines)
+			}
+			err := p.Play(w)
+			if tt.loop {
+				assert.EqualError(t, err, "write failed")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.lines, w.get())
+			assert.Equal(t, tt.waits, *waits)
+		})
+	}
+}
+
+func TestSeek(t *testing.T) {
+	p := NewPlayer(logOf(0, time.Hour, 2*time.Hour, 2*time.Hour))
+	w := newLines()
+	done := make(chan error)
+	go func() { done <- p.Play(w) }()
+
+	<-w.written
+	assert.True(t, at(12, 0, 0).Equal(p.Position()))
+	p.Seek(at(13, 30, 0))
+	assert.NoError(t, <-done)
+	assert.Equal(t, []string{"A", "C", "D"}, w.get())
+}
+
+func TestPause(t *testing.T) {
+	p := NewPlayer(logOf(0, time.Hour))
+	p.Pause()
+	assert.True(t, p.Paused())
+	w := newLines()
+	done := make(chan error)
+	go func() { done <- p.Play(w) }()
+
+	select {
+	case <-w.written:
+		t.Fatal("line written while paused")
+	case <-time.After(10 * time.Millisecond):
+	}
+	p.Resume()
+	<-w.written
+	p.Pause()
+	p.Seek(at(13, 0, 0))
+	p.Resume()
+	<-w.written
+	assert.NoError(t, <-done)
+	assert.Equal(t, []string{"A", "B"}, w.get())
+}
+
+func TestStop(t *testing.T) {
+	p := NewPlayer(logOf(0, time.Hour))
+	w := newLines()
+	done := make(chan error)
+	go func() { done <- p.Play(w) }()
+
+	<-w.written
+	p.Stop()
+	assert.NoError(t, <-done)
+	assert.Equal(t, []string{"A"}, w.get())
+}
@@ -0,0 +1,161 @@
+// Package replay plays recorded logs of sentences back at their original
+// pace.
+//
+// The time of each line comes from the c: parameter of its TAG Block when
+// the log has such timestamps, or from the times of RMC, ZDA, GGA, GLL and
+// GNS sentences otherwise, dated with the DateTracker. Lines without a time
+// are played right after the line before them.
+package replay
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+// Entry is a line of a log.
+type Entry struct {
+	Line string
+	Time time.Time // Time the line was recorded, or zero for a log without times
+}
+
+// Log is a recorded log.
+type Log struct {
+	Entries []Entry
+}
+
+// Read reads a log, one line per sentence, and works out the time of each
+// line.
+func Read(r io.Reader) (Log, error) {
+	var (
+		lines  []string
+		tagged []time.Time // Times of TAG Blocks
+		dated  []time.Time // Times of sentences
+		tods   []nmea.Time // Times of day of sentences which cannot be dated yet
+		hasTag bool
+	)
+	dates := nmea.NewDateTracker()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var tag, t time.Time
+		var tod nmea.Time
+		if tags, _, err := nmea.SplitTagBlock(line); err == nil && tags.Time != 0 {
+			tag, hasTag = tags.Timestamp(), true
+		}
+		if s, err := nmea.Parse(line); err == nil {
+			if v, ok := dates.Update(s); ok {
+				t = v
+			} else {
+				tod = timeOfDay(s)
+			}
+		}
+		lines = append(lines, line)
+		tagged = append(tagged, tag)
+		dated = append(dated, t)
+		tods = append(tods, tod)
+	}
+	if err := scanner.Err(); err != nil {
+		return Log{}, err
+	}
+
+	times := dated
+	if hasTag {
+		times = tagged
+	} else {
+		dateTimesOfDay(times, tods)
+	}
+	fill(times)
+	l := Log{Entries: make([]Entry, len(lines))}
+	for i, line := range lines {
+		l.Entries[i] = Entry{Line: line, Time: times[i]}
+	}
+	return l, nil
+}
+
+// Start returns the time of the first line.
+func (l Log) Start() time.Time {
+	if len(l.Entries) == 0 {
+		return time.Time{}
+	}
+	return l.Entries[0].Time
+}
+
+// End returns the time of the last line.
+func (l Log) End() time.Time {
+	if len(l.Entries) == 0 {
+		return time.Time{}
+	}
+	return l.Entries[len(l.Entries)-1].Time
+}
+
+// Index returns the index of the first line recorded at or after t, or the
+// number of lines if there is none.
+func (l Log) Index(t time.Time) int {
+	return sort.Search(len(l.Entries), func(i int) bool {
+		return !l.Entries[i].Time.Before(t)
+	})
+}
+
+// timeOfDay returns the time of the sentences carrying only a time of day.
+func timeOfDay(s nmea.Sentence) nmea.Time {
+	switch m := s.(type) {
+	case nmea.GGA:
+		return m.Time
+	case nmea.GLL:
+		return m.Time
+	case nmea.GNS:
+		return m.Time
+	}
+	return nmea.Time{}
+}
+
+// dateTimesOfDay dates the times of day seen before the first date of the
+// log with that date, or the day before when they are more than twelve
+// hours after it. Without any date, they are dated January 1 of year 1.
+func dateTimesOfDay(times []time.Time, tods []nmea.Time) {
+	first := time.Time{}
+	for _, t := range times {
+		if !t.IsZero() {
+			first = t
+			break
+		}
+	}
+	date := first.Truncate(24 * time.Hour)
+	for i, tod := range tods {
+		if !tod.Valid || !times[i].IsZero() {
+			continue
+		}
+		t := date.Add(tod.Duration())
+		if !first.IsZero() && t.Sub(first) > 12*time.Hour {
+			t = t.AddDate(0, 0, -1)
+		}
+		times[i] = t
+	}
+}
+
+// fill gives lines without a time the time of the line before them, or of
+// the first line with a time.
+func fill(times []time.Time) {
+	var last time.Time
+	for _, t := range times {
+		if !t.IsZero() {
+			last = t
+			break
+		}
+	}
+	for i, t := range times {
+		if t.IsZero() {
+			times[i] = last
+		} else {
+			last = t
+		}
+	}
+}
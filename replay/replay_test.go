@@ -0,0 +1,107 @@
+package replay
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+const (
+	gga1 = "$GPGGA,220515,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*55"
+	gsv  = "$GPGSV,1,1,01,04,45,180,42*42"
+	rmc  = "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70"
+	gga2 = "$GPGGA,220518,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*58"
+	zda  = "$GPZDA,220530.00,13,06,1994,00,00*61"
+	hdt  = "$GPHDT,123.456,T*32"
+)
+
+func at(hour, min, sec int) time.Time {
+	return time.Date(1994, 6, 13, hour, min, sec, 0, time.UTC)
+}
+
+func tagged(t int64, line string) string {
+	return nmea.TagBlock{Time: t}.String() + line
+}
+
+var readtests = []struct {
+	name  string
+	lines []string
+	times []time.Time
+}{
+	{
+		name:  "sentence times",
+		lines: []string{gsv, gga1, gsv, rmc, "", gga2, hdt, zda},
+		times: []time.Time{at(22, 5, 15), at(22, 5, 15), at(22, 5, 15), at(22, 5, 16), at(22, 5, 18), at(22, 5, 18), at(22, 5, 30)},
+	},
+	{
+		name: "midnight",
+		lines: []string{
+			"$GPGGA,235959,5133.82,N,00042.24,W,1,08,0.9,545.4,M,46.9,M,,*55",
+			"$GPRMC,000001,A,5133.82,N,00042.24,W,173.8,231.8,140694,004.2,W*74",
+		},
+		times: []time.Time{at(23, 59, 59), at(24, 0, 1)},
+	},
+	{
+		name:  "TAG Block times",
+		lines: []string{hdt, tagged(1577934245, rmc), hdt, tagged(1577934247500, gga2)},
+		times: []time.Time{
+			time.Unix(1577934245, 0),
+			time.Unix(1577934245, 0),
+			time.Unix(1577934245, 0),
+			time.Unix(1577934247, 5e8),
+		},
+	},
+	{
+		name:  "times of day only",
+		lines: []string{gga1, hdt, gga2},
+		times: []time.Time{
+			time.Time{}.Add(22*time.Hour + 5*time.Minute + 15*time.Second),
+			time.Time{}.Add(22*time.Hour + 5*time.Minute + 15*time.Second),
+			time.Time{}.Add(22*time.Hour + 5*time.Minute + 18*time.Second),
+		},
+	},
+	{
+		name:  "no times",
+		lines: []string{hdt, "garbage", hdt},
+		times: []time.Time{{}, {}, {}},
+	},
+}
+
+func TestRead(t *testing.T) {
+	for _, tt := range readtests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := Read(strings.NewReader(strings.Join(tt.lines, "\r\n")))
+			if !assert.NoError(t, err) {
+				return
+			}
+			var times []time.Time
+			for _, e := range l.Entries {
+				times = append(times, e.Time)
+			}
+			assert.Len(t, l.Entries, len(tt.times))
+			for i := range tt.times {
+				if i < len(times) {
+					assert.True(t, tt.times[i].Equal(times[i]), "line %d: %s != %s", i, tt.times[i], times[i])
+				}
+			}
+			assert.True(t, tt.times[0].Equal(l.Start()))
+			assert.True(t, tt.times[len(tt.times)-1].Equal(l.End()))
+		})
+	}
+}
+
+func TestIndex(t *testing.T) {
+	l, err := Read(strings.NewReader(strings.Join([]string{gga1, gsv, rmc, gga2, zda}, "\n")))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 0, l.Index(time.Time{}))
+	assert.Equal(t, 2, l.Index(at(22, 5, 16)))
+	assert.Equal(t, 3, l.Index(at(22, 5, 17)))
+	assert.Equal(t, 5, l.Index(at(23, 0, 0)))
+	assert.Equal(t, time.Time{}, Log{}.Start())
+}
@@ -1,5 +1,7 @@
 package nmea
 
+import "time"
+
 const (
 	// TypeRMC type for RMC sentences
 	TypeRMC = "RMC"
@@ -21,6 +23,11 @@ type RMC struct {
 	Course    float64 // True course
 	Date      Date    // Date
 	Variation float64 // Magnetic variation
+
+	SpeedValid     bool // Speed is present in the sentence
+	CourseValid    bool // Course is present in the sentence
+	VariationValid bool // Variation is present in the sentence
+	DateCorrected  bool // Date was corrected for the GPS week rollover
 }
 
 // newRMC constructor
@@ -33,13 +40,45 @@ func newRMC(s BaseSentence) (RMC, error) {
 		Validity:     p.EnumString(1, "validity", ValidRMC, InvalidRMC),
 		Latitude:     p.LatLong(2, 3, "latitude"),
 		Longitude:    p.LatLong(4, 5, "longitude"),
-		Speed:        p.Float64(6, "speed"),
-		Course:       p.Float64(7, "course"),
-		Date:         p.Date(8, "date"),
-		Variation:    p.Float64(9, "variation"),
 	}
+	speed := p.NullFloat64(6, "speed")
+	m.Speed, m.SpeedValid = speed.Value, speed.Valid
+	course := p.NullFloat64(7, "course")
+	m.Course, m.CourseValid = course.Value, course.Valid
+	m.Date = p.Date(8, "date")
+	variation := p.NullFloat64(9, "variation")
+	m.Variation, m.VariationValid = variation.Value, variation.Valid
 	if p.EnumString(10, "direction", West, East) == West {
 		m.Variation = 0 - m.Variation
 	}
 	return m, p.Err()
 }
+
+// DateTime returns the date and time of the sentence as a UTC time.Time.
+// The two-digit year is expanded with DefaultCenturyPivot.
+func (s RMC) DateTime() (time.Time, error) {
+	return dateTime(DefaultCenturyPivot, s.Date, s.Time, s.timeOfDay(0, s.Time))
+}
+
+// CorrectRollover returns the sentence with its date corrected for the GPS
+// week rollover, see Date.CorrectRollover. DateCorrected is set on the
+// returned sentence when a correction was applied, which is also reported.
+func (s RMC) CorrectRollover(reference time.Time) (RMC, bool) {
+	date, ok := s.Date.CorrectRollover(reference)
+	if !ok {
+		return s, false
+	}
+	s.Date = date
+	s.DateCorrected = true
+	return s, true
+}
+
+// MarshalJSON implements json.Marshaler
+func (s RMC) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *RMC) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
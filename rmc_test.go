@@ -2,6 +2,7 @@ package nmea
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -16,42 +17,48 @@ var rmctests = []struct {
 		name: "good sentence A",
 		raw:  "$GNRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*6E",
 		msg: RMC{
-			Time:      Time{true, 22, 05, 16, 0},
-			Validity:  "A",
-			Speed:     173.8,
-			Course:    231.8,
-			Date:      Date{true, 13, 06, 94},
-			Variation: -4.2,
-			Latitude:  MustParseGPS("5133.82 N"),
-			Longitude: MustParseGPS("00042.24 W"),
+			Time:           Time{true, 22, 05, 16, 0},
+			Validity:       "A",
+			Speed:          173.8,
+			Course:         231.8,
+			Date:           Date{true, 13, 06, 94},
+			Variation:      -4.2,
+			Latitude:       MustParseGPS("5133.82 N"),
+			Longitude:      MustParseGPS("00042.24 W"),
+			SpeedValid:     true,
+			CourseValid:    true,
+			VariationValid: true,
 		},
 	},
 	{
 		name: "good sentence B",
 		raw:  "$GNRMC,142754.0,A,4302.539570,N,07920.379823,W,0.0,,070617,0.0,E,A*21",
 		msg: RMC{
-			Time:      Time{true, 14, 27, 54, 0},
-			Validity:  "A",
-			Speed:     0,
-			Course:    0,
-			Date:      Date{true, 7, 6, 17},
-			Variation: 0,
-			Latitude:  MustParseGPS("4302.539570 N"),
-			Longitude: MustParseGPS("07920.379823 W"),
+			Time:           Time{true, 14, 27, 54, 0},
+			Validity:       "A",
+			Speed:          0,
+			Course:         0,
+			Date:           Date{true, 7, 6, 17},
+			Variation:      0,
+			Latitude:       MustParseGPS("4302.539570 N"),
+			Longitude:      MustParseGPS("07920.379823 W"),
+			SpeedValid:     true,
+			VariationValid: true,
 		},
 	},
 	{
 		name: "good sentence C",
 		raw:  "$GNRMC,100538.00,A,5546.27711,N,03736.91144,E,0.061,,260318,,,A*60",
 		msg: RMC{
-			Time:      Time{true, 10, 5, 38, 0},
-			Validity:  "A",
-			Speed:     0.061,
-			Course:    0,
-			Date:      Date{true, 26, 3, 18},
-			Variation: 0,
-			Latitude:  MustParseGPS("5546.27711 N"),
-			Longitude: MustParseGPS("03736.91144 E"),
+			Time:       Time{true, 10, 5, 38, 0},
+			Validity:   "A",
+			Speed:      0.061,
+			Course:     0,
+			Date:       Date{true, 26, 3, 18},
+			Variation:  0,
+			Latitude:   MustParseGPS("5546.27711 N"),
+			Longitude:  MustParseGPS("03736.91144 E"),
+			SpeedValid: true,
 		},
 	},
 	{
@@ -63,28 +70,33 @@ var rmctests = []struct {
 		name: "good sentence A",
 		raw:  "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70",
 		msg: RMC{
-			Time:      Time{true, 22, 5, 16, 0},
-			Validity:  "A",
-			Speed:     173.8,
-			Course:    231.8,
-			Date:      Date{true, 13, 6, 94},
-			Variation: -4.2,
-			Latitude:  MustParseGPS("5133.82 N"),
-			Longitude: MustParseGPS("00042.24 W"),
+			Time:           Time{true, 22, 5, 16, 0},
+			Validity:       "A",
+			Speed:          173.8,
+			Course:         231.8,
+			Date:           Date{true, 13, 6, 94},
+			Variation:      -4.2,
+			Latitude:       MustParseGPS("5133.82 N"),
+			Longitude:      MustParseGPS("00042.24 W"),
+			SpeedValid:     true,
+			CourseValid:    true,
+			VariationValid: true,
 		},
 	},
 	{
 		name: "good sentence B",
 		raw:  "$GPRMC,142754.0,A,4302.539570,N,07920.379823,W,0.0,,070617,0.0,E,A*3F",
 		msg: RMC{
-			Time:      Time{true, 14, 27, 54, 0},
-			Validity:  "A",
-			Speed:     0,
-			Course:    0,
-			Date:      Date{true, 7, 6, 17},
-			Variation: 0,
-			Latitude:  MustParseGPS("4302.539570 N"),
-			Longitude: MustParseGPS("07920.379823 W"),
+			Time:           Time{true, 14, 27, 54, 0},
+			Validity:       "A",
+			Speed:          0,
+			Course:         0,
+			Date:           Date{true, 7, 6, 17},
+			Variation:      0,
+			Latitude:       MustParseGPS("4302.539570 N"),
+			Longitude:      MustParseGPS("07920.379823 W"),
+			SpeedValid:     true,
+			VariationValid: true,
 		},
 	},
 	{
@@ -110,3 +122,23 @@ func TestRMC(t *testing.T) {
 		})
 	}
 }
+
+func TestRMCDateTime(t *testing.T) {
+	m, err := Parse("$GPRMC,220516.123,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*6E")
+	assert.NoError(t, err)
+	dt, err := m.(RMC).DateTime()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(1994, 6, 13, 22, 5, 16, 123e6, time.UTC), dt)
+
+	m, err = Parse("$GPRMC,220516.123456,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*59")
+	assert.NoError(t, err)
+	assert.Equal(t, 123, m.(RMC).Time.Millisecond)
+	dt, err = m.(RMC).DateTime()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(1994, 6, 13, 22, 5, 16, 123456e3, time.UTC), dt)
+
+	m, err = Parse("$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,,004.2,W*79")
+	assert.NoError(t, err)
+	_, err = m.(RMC).DateTime()
+	assert.EqualError(t, err, "nmea: invalid date")
+}
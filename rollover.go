@@ -0,0 +1,21 @@
+package nmea
+
+import (
+	"time"
+)
+
+// WeekRolloverPeriod is the period after which the 10-bit GPS week number
+// wraps around.
+const WeekRolloverPeriod = 1024 * 7 * 24 * time.Hour
+
+// CorrectWeekRollover adds multiples of 1024 weeks to t until it is not
+// before the reference. It reports whether a correction was applied.
+func CorrectWeekRollover(t, reference time.Time) (time.Time, bool) {
+	if reference.IsZero() || !t.Before(reference) {
+		return t, false
+	}
+	for t.Before(reference) {
+		t = t.Add(WeekRolloverPeriod)
+	}
+	return t, true
+}
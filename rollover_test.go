@@ -0,0 +1,95 @@
+package nmea
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectWeekRollover(t *testing.T) {
+	reference := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	corrected, ok := CorrectWeekRollover(time.Date(1999, 8, 20, 0, 0, 0, 0, time.UTC), reference)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2019, 4, 5, 0, 0, 0, 0, time.UTC), corrected)
+
+	// two rollovers
+	corrected, ok = CorrectWeekRollover(time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC), reference)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2019, 4, 7, 0, 0, 0, 0, time.UTC), corrected)
+
+	date := time.Date(2019, 4, 5, 0, 0, 0, 0, time.UTC)
+	corrected, ok = CorrectWeekRollover(date, reference)
+	assert.False(t, ok)
+	assert.Equal(t, date, corrected)
+
+	corrected, ok = CorrectWeekRollover(date, time.Time{})
+	assert.False(t, ok)
+	assert.Equal(t, date, corrected)
+}
+
+func TestDateCorrectRollover(t *testing.T) {
+	reference := time.Date(2018, 1, 1, 15, 0, 0, 0, time.UTC)
+
+	d, ok := Date{true, 3, 11, 99}.CorrectRollover(reference)
+	assert.True(t, ok)
+	assert.Equal(t, Date{true, 19, 6, 19}, d)
+
+	d, ok = Date{true, 1, 1, 18}.CorrectRollover(reference)
+	assert.False(t, ok)
+	assert.Equal(t, Date{true, 1, 1, 18}, d)
+
+	d, ok = Date{true, 3, 11, 99}.CorrectRollover(time.Time{})
+	assert.False(t, ok)
+	assert.Equal(t, Date{true, 3, 11, 99}, d)
+
+	d, ok = Date{}.CorrectRollover(reference)
+	assert.False(t, ok)
+	assert.Equal(t, Date{}, d)
+
+	m, err := Parse("$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,031199,004.2,W*7A")
+	assert.NoError(t, err)
+	d, ok = m.(RMC).Date.CorrectRollover(reference)
+	assert.True(t, ok)
+	assert.Equal(t, Date{true, 19, 6, 19}, d)
+}
+
+func TestRMCCorrectRollover(t *testing.T) {
+	reference := time.Date(2018, 1, 1, 15, 0, 0, 0, time.UTC)
+
+	m, err := Parse("$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,031199,004.2,W*7A")
+	if !assert.NoError(t, err) {
+		return
+	}
+	rmc, ok := m.(RMC).CorrectRollover(reference)
+	assert.True(t, ok)
+	assert.True(t, rmc.DateCorrected)
+	assert.Equal(t, Date{true, 19, 6, 19}, rmc.Date)
+	assert.False(t, m.(RMC).DateCorrected)
+
+	rmc, ok = m.(RMC).CorrectRollover(time.Time{})
+	assert.False(t, ok)
+	assert.False(t, rmc.DateCorrected)
+	assert.Equal(t, Date{true, 3, 11, 99}, rmc.Date)
+}
+
+func TestZDACorrectRollover(t *testing.T) {
+	reference := time.Date(2018, 1, 1, 15, 0, 0, 0, time.UTC)
+
+	m, err := Parse("$GPZDA,172809.456,03,11,1999,00,00*5F")
+	assert.NoError(t, err)
+	zda, ok := m.(ZDA).CorrectRollover(reference)
+	assert.True(t, ok)
+	assert.True(t, zda.DateCorrected)
+	assert.Equal(t, []int64{19, 6, 2019}, []int64{zda.Day, zda.Month, zda.Year})
+
+	zda, ok = zda.CorrectRollover(reference)
+	assert.False(t, ok)
+	assert.Equal(t, []int64{19, 6, 2019}, []int64{zda.Day, zda.Month, zda.Year})
+
+	m, err = Parse("$GPZDA,172809.456,,,,00,00*54")
+	assert.NoError(t, err)
+	_, ok = m.(ZDA).CorrectRollover(reference)
+	assert.False(t, ok)
+}
@@ -0,0 +1,196 @@
+// Package route assembles routes from RTE and WPL sentences. RTE sentences
+// list the waypoint idents of a route, split across several sentences, and
+// WPL sentences give the position of each waypoint.
+package route
+
+import (
+	"sort"
+	"sync"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/geo"
+)
+
+// Leg is the great circle between two consecutive waypoints of a route.
+type Leg struct {
+	From     geo.Waypoint
+	To       geo.Waypoint
+	Distance float64 // Meters
+	Bearing  float64 // Initial bearing in degrees from true north
+}
+
+// Route is an ordered list of waypoints.
+type Route struct {
+	Name      string
+	Active    bool           // The route is the active route (c) rather than a waypoint list (w)
+	Idents    []string       // Waypoint idents in route order
+	Waypoints []geo.Waypoint // Resolved waypoints, in route order
+	Legs      []Leg          // Legs between consecutive waypoints, none spans a missing one
+	Missing   []string       // Idents without a known position
+}
+
+// Complete reports whether the position of every waypoint is known.
+func (r Route) Complete() bool {
+	return len(r.Missing) == 0
+}
+
+// Length returns the total length of the legs in meters.
+func (r Route) Length() float64 {
+	var length float64
+	for _, l := range r.Legs {
+		length += l.Distance
+	}
+	return length
+}
+
+type pendingRoute struct {
+	total int64
+	parts map[int64][]string
+}
+
+type routeInfo struct {
+	active bool
+	idents []string
+}
+
+// Assembler collects RTE and WPL sentences from a stream. Routes are
+// resolved against the latest position of each waypoint when they are
+// queried, so waypoints may arrive before or after the route.
+type Assembler struct {
+	mu        sync.Mutex
+	waypoints map[string]geo.Waypoint
+	pending   map[string]*pendingRoute
+	routes    map[string]routeInfo
+	active    string
+	hasActive bool
+}
+
+// NewAssembler constructor
+func NewAssembler() *Assembler {
+	return &Assembler{
+		waypoints: map[string]geo.Waypoint{},
+		pending:   map[string]*pendingRoute{},
+		routes:    map[string]routeInfo{},
+	}
+}
+
+// Add observes a sentence. When an RTE sentence completes a route, the
+// resolved route is returned along with true.
+func (a *Assembler) Add(s nmea.Sentence) (Route, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch m := s.(type) {
+	case nmea.WPL:
+		a.waypoints[m.Ident] = geo.Waypoint{ID: m.Ident, LatLon: geo.LatLon{Lat: m.Latitude, Lon: m.Longitude}}
+	case nmea.RTE:
+		if m.NumberOfSentences < 1 || m.SentenceNumber < 1 || m.SentenceNumber > m.NumberOfSentences {
+			return Route{}, false
+		}
+		p, ok := a.pending[m.Name]
+		// the first sentence or a change of size restarts the route
+		if !ok || m.SentenceNumber == 1 || p.total != m.NumberOfSentences {
+			p = &pendingRoute{total: m.NumberOfSentences, parts: map[int64][]string{}}
+			a.pending[m.Name] = p
+		}
+		p.parts[m.SentenceNumber] = m.Idents
+		if int64(len(p.parts)) < p.total {
+			return Route{}, false
+		}
+		delete(a.pending, m.Name)
+
+		var idents []string
+		for i := int64(1); i <= p.total; i++ {
+			idents = append(idents, p.parts[i]...)
+		}
+		info := routeInfo{active: m.ActiveRouteOrWaypointList == nmea.ActiveRoute, idents: idents}
+		a.routes[m.Name] = info
+		if info.active {
+			a.active, a.hasActive = m.Name, true
+		} else if a.hasActive && a.active == m.Name {
+			a.hasActive = false
+		}
+		return a.resolve(m.Name, info), true
+	}
+	return Route{}, false
+}
+
+// Waypoint returns the last position seen for a waypoint ident.
+func (a *Assembler) Waypoint(ident string) (geo.Waypoint, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	w, ok := a.waypoints[ident]
+	return w, ok
+}
+
+// Route returns the route with the given name.
+func (a *Assembler) Route(name string) (Route, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	info, ok := a.routes[name]
+	if !ok {
+		return Route{}, false
+	}
+	return a.resolve(name, info), true
+}
+
+// Active returns the last route received as the active route.
+func (a *Assembler) Active() (Route, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.hasActive {
+		return Route{}, false
+	}
+	return a.resolve(a.active, a.routes[a.active]), true
+}
+
+// Routes returns all complete routes received, ordered by name.
+func (a *Assembler) Routes() []Route {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	names := make([]string, 0, len(a.routes))
+	for name := range a.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	routes := make([]Route, 0, len(names))
+	for _, name := range names {
+		routes = append(routes, a.resolve(name, a.routes[name]))
+	}
+	return routes
+}
+
+func (a *Assembler) resolve(name string, info routeInfo) Route {
+	r := Route{
+		Name:   name,
+		Active: info.active && a.hasActive && a.active == name,
+		Idents: info.idents,
+	}
+	var (
+		from    geo.Waypoint
+		hasFrom bool
+	)
+	for _, ident := range info.idents {
+		w, ok := a.waypoints[ident]
+		if !ok {
+			r.Missing = append(r.Missing, ident)
+			hasFrom = false
+			continue
+		}
+		if hasFrom {
+			r.Legs = append(r.Legs, Leg{
+				From:     from,
+				To:       w,
+				Distance: geo.Distance(from.LatLon, w.LatLon),
+				Bearing:  geo.InitialBearing(from.LatLon, w.LatLon),
+			})
+		}
+		r.Waypoints = append(r.Waypoints, w)
+		from, hasFrom = w, true
+	}
+	return r
+}
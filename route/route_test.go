@@ -0,0 +1,101 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/geo"
+)
+
+func add(t *testing.T, a *Assembler, raw string) (Route, bool) {
+	s, err := nmea.Parse(raw)
+	assert.NoError(t, err)
+	return a.Add(s)
+}
+
+func TestAssembler(t *testing.T) {
+	a := NewAssembler()
+
+	// waypoints may arrive before the route
+	_, ok := add(t, a, "$GPWPL,5128.62,N,00027.58,W,EGLL*59")
+	assert.False(t, ok)
+	add(t, a, "$GPWPL,4900.58,N,00233.00,E,LFPG*54")
+
+	_, ok = add(t, a, "$GPRTE,2,1,c,0,EGLL,LFPG*1B")
+	assert.False(t, ok)
+	r, ok := add(t, a, "$GPRTE,2,2,c,0,EBBR*3C")
+	assert.True(t, ok)
+	assert.Equal(t, "0", r.Name)
+	assert.True(t, r.Active)
+	assert.Equal(t, []string{"EGLL", "LFPG", "EBBR"}, r.Idents)
+	assert.Equal(t, []string{"EBBR"}, r.Missing)
+	assert.False(t, r.Complete())
+	assert.Len(t, r.Legs, 1)
+
+	// and after it
+	add(t, a, "$GPWPL,5018.00,N,00434.00,E,EBBR*53")
+	r, ok = a.Active()
+	assert.True(t, ok)
+	assert.True(t, r.Complete())
+	assert.Len(t, r.Waypoints, 3)
+	assert.Len(t, r.Legs, 2)
+	leg := r.Legs[0]
+	assert.Equal(t, "EGLL", leg.From.ID)
+	assert.Equal(t, "LFPG", leg.To.ID)
+	assert.InDelta(t, 348000, leg.Distance, 2000)
+	assert.InDelta(t, 140.9, leg.Bearing, 0.1)
+	assert.Equal(t, r.Legs[0].Distance+r.Legs[1].Distance, r.Length())
+
+	w, ok := a.Waypoint("EBBR")
+	assert.True(t, ok)
+	assert.Equal(t, geo.LatLon{Lat: 50.3, Lon: 4.566666666666666}, w.LatLon)
+
+	// a waypoint list does not replace the active route
+	r, ok = add(t, a, "$GPRTE,1,1,w,1,EBBR,EGLL*07")
+	assert.True(t, ok)
+	assert.False(t, r.Active)
+	r, ok = a.Active()
+	assert.True(t, ok)
+	assert.Equal(t, "0", r.Name)
+
+	routes := a.Routes()
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "0", routes[0].Name)
+	assert.Equal(t, "1", routes[1].Name)
+
+	_, ok = a.Route("2")
+	assert.False(t, ok)
+}
+
+func TestAssemblerRestart(t *testing.T) {
+	a := NewAssembler()
+	add(t, a, "$GPRTE,2,1,c,0,EGLL,LFPG*1B")
+	// a new first sentence drops the previous parts
+	add(t, a, "$GPRTE,2,1,c,0,EGLL,LFPG*1B")
+	r, ok := add(t, a, "$GPRTE,2,2,c,0,EBBR*3C")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"EGLL", "LFPG", "EBBR"}, r.Idents)
+
+	// the active route becomes a waypoint list
+	r, ok = add(t, a, "$GPRTE,1,1,w,0,EBBR,EGLL*06")
+	assert.True(t, ok)
+	assert.False(t, r.Active)
+	_, ok = a.Active()
+	assert.False(t, ok)
+}
+
+func TestAssemblerMissingWaypoint(t *testing.T) {
+	a := NewAssembler()
+	add(t, a, "$GPWPL,5128.62,N,00027.58,W,EGLL*59")
+	add(t, a, "$GPWPL,4900.58,N,00233.00,E,LFPG*54")
+
+	// no leg spans the unknown EBBR
+	r, ok := add(t, a, "$GPRTE,1,1,c,0,EGLL,EBBR,LFPG*23")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"EBBR"}, r.Missing)
+	assert.Len(t, r.Waypoints, 2)
+	assert.Empty(t, r.Legs)
+	assert.Equal(t, 0.0, r.Length())
+}
@@ -34,3 +34,13 @@ func newRTE(s BaseSentence) (RTE, error) {
 		Idents:                    p.ListString(4, "ident of waypoints"),
 	}, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s RTE) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *RTE) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
@@ -64,22 +64,45 @@ func (s BaseSentence) TalkerID() string {
 // String formats the sentence into a string
 func (s BaseSentence) String() string { return s.Raw }
 
+// baseSentence is implemented by every sentence embedding a BaseSentence.
+type baseSentence interface {
+	baseSentence() BaseSentence
+}
+
+func (s BaseSentence) baseSentence() BaseSentence { return s }
+
+// BaseSentenceOf returns the BaseSentence embedded in the given sentence.
+func BaseSentenceOf(s Sentence) (BaseSentence, bool) {
+	if b, ok := s.(baseSentence); ok {
+		return b.baseSentence(), true
+	}
+	return BaseSentence{}, false
+}
+
+// Line returns the sentence as it is sent on the wire, including the
+// tag block if it has one.
+func (s BaseSentence) Line() string {
+	return s.TagBlock.String() + s.Raw
+}
+
+// SetTagBlock attaches the tag block to the sentence. The Raw sentence and
+// its Checksum are rendered again from the talker, type and fields so any
+// modification of those is reflected as well.
+func (s *BaseSentence) SetTagBlock(t TagBlock) {
+	start := SentenceStart
+	if strings.HasPrefix(s.Raw, SentenceStartEncapsulated) {
+		start = SentenceStartEncapsulated
+	}
+	s.TagBlock = t
+	s.Raw = FormatSentence(start, s.Prefix(), s.Fields)
+	s.Checksum = s.Raw[len(s.Raw)-2:]
+}
+
 // parseSentence parses a raw message into it's fields
 func parseSentence(raw string) (BaseSentence, error) {
-	raw = strings.TrimSpace(raw)
-	tagBlockParts := strings.SplitN(raw, `\`, 3)
-
-	var (
-		tagBlock TagBlock
-		err			error
-	)
-	if len(tagBlockParts) == 3 {
-		tags := tagBlockParts[1]
-		raw = tagBlockParts[2]
-		tagBlock, err = parseTagBlock(tags)
-		if err != nil {
-			return BaseSentence{}, err
-		}
+	tagBlock, raw, err := SplitTagBlock(raw)
+	if err != nil {
+		return BaseSentence{}, err
 	}
 
 	startIndex := strings.IndexAny(raw, SentenceStart+SentenceStartEncapsulated)
@@ -112,21 +135,11 @@ func parseSentence(raw string) (BaseSentence, error) {
 	}, nil
 }
 
-// parsePrefix takes the first field and splits it into a talker id and data type.
-func parsePrefix(s string) (string, string) {
-	if strings.HasPrefix(s, "PMTK") {
-		return "PMTK", s[4:]
-	}
-	if strings.HasPrefix(s, "P") {
-		return "P", s[1:]
-	}
-	if strings.HasPrefix(s, "N") {
-		return "N", s[1:]
-	}
-	if len(s) < 2 {
-		return s, ""
-	}
-	return s[:2], s[2:]
+// ParseBaseSentence parses the tag block, talker, type and fields of a
+// sentence and validates its checksum, without decoding the fields. Unlike
+// Parse, it accepts sentences of any type.
+func ParseBaseSentence(raw string) (BaseSentence, error) {
+	return parseSentence(raw)
 }
 
 // Checksum xor all the bytes in a string an return it
@@ -139,6 +152,14 @@ func Checksum(s string) string {
 	return fmt.Sprintf("%02X", checksum)
 }
 
+// FormatSentence joins the start token, prefix and fields of a sentence
+// and appends the checksum.
+// e.g. FormatSentence("$", "GPHDT", []string{"123.456", "T"}) returns $GPHDT,123.456,T*32
+func FormatSentence(start, prefix string, fields []string) string {
+	body := strings.Join(append([]string{prefix}, fields...), FieldSep)
+	return start + body + ChecksumSep + Checksum(body)
+}
+
 // MustRegisterParser register a custom parser or panic
 func MustRegisterParser(sentenceType string, parser ParserFunc) {
 	if err := RegisterParser(sentenceType, parser); err != nil {
@@ -194,6 +215,10 @@ func Parse(raw string) (Sentence, error) {
 			return newZDA(s)
 		case TypePGRME:
 			return newPGRME(s)
+		case TypePGRMM:
+			return newPGRMM(s)
+		case TypePCDIN:
+			return newPCDIN(s)
 		case TypeGSV:
 			return newGSV(s)
 		case TypeHDT:
@@ -214,6 +239,10 @@ func Parse(raw string) (Sentence, error) {
 			return newDBT(s)
 		case TypeDBS:
 			return newDBS(s)
+		case TypeDTM:
+			return newDTM(s)
+		case TypeMWV:
+			return newMWV(s)
 		}
 	}
 	if strings.HasPrefix(s.Raw, SentenceStartEncapsulated) {
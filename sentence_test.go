@@ -182,6 +182,18 @@ var prefixtests = []struct {
 		talker: "P",
 		typ:    "X",
 	},
+	{
+		name:   "registered talker starting with N",
+		prefix: "NLFOO",
+		talker: "NL",
+		typ:    "FOO",
+	},
+	{
+		name:   "user configured talker",
+		prefix: "U1XDR",
+		talker: "U1",
+		typ:    "XDR",
+	},
 }
 
 func TestPrefix(t *testing.T) {
@@ -230,3 +242,29 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBaseSentence(t *testing.T) {
+	s, err := ParseBaseSentence("$INVALID,123,123,*7D")
+	assert.NoError(t, err)
+	assert.Equal(t, "INVALID", s.Prefix())
+	assert.Equal(t, []string{"123", "123", ""}, s.Fields)
+
+	_, err = ParseBaseSentence("$INVALID,123,123,*7E")
+	assert.EqualError(t, err, "nmea: sentence checksum mismatch [7D != 7E]")
+}
+
+func TestSetTagBlock(t *testing.T) {
+	s, err := parseSentence("!AIVDM,1,1,,A,13M@ah0025QdPDTCOl`K6`nV00Sv,0*52")
+	assert.NoError(t, err)
+	assert.Equal(t, "!AIVDM,1,1,,A,13M@ah0025QdPDTCOl`K6`nV00Sv,0*52", s.Line())
+
+	s.Fields[3] = "B"
+	s.SetTagBlock(TagBlock{Time: 1553390539, Source: "Satelite_1"})
+	assert.Equal(t, "!AIVDM,1,1,,B,13M@ah0025QdPDTCOl`K6`nV00Sv,0*51", s.Raw)
+	assert.Equal(t, "51", s.Checksum)
+	assert.Equal(t, "\\c:1553390539,s:Satelite_1*62\\!AIVDM,1,1,,B,13M@ah0025QdPDTCOl`K6`nV00Sv,0*51", s.Line())
+
+	parsed, err := parseSentence(s.Line())
+	assert.NoError(t, err)
+	assert.Equal(t, s, parsed)
+}
@@ -0,0 +1,175 @@
+package signalk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/coord"
+)
+
+// Sentences formats the values of the own vessel in a delta as sentences of
+// the given talker, ready to be published on an NMEA 0183 bus. Deltas of
+// other contexts are ignored. Each update gives, when its values are
+// present:
+//
+//   - RMC from navigation.position, with the speed and course over ground
+//     and magnetic variation, or VTG from the speed and course alone
+//   - HDT from navigation.headingTrue
+//   - VHW from navigation.speedThroughWater and the headings
+//   - DPT and DBT from environment.depth.belowTransducer, and DBS from
+//     environment.depth.belowSurface
+//   - MWV from the apparent and true wind angles and speeds
+//
+// Times are those of the updates.
+func (c *Converter) Sentences(d Delta, talker string) []string {
+	if d.Context != "" && d.Context != c.Self {
+		return nil
+	}
+	var sentences []string
+	add := func(typ string, fields ...string) {
+		sentences = append(sentences, nmea.FormatSentence(nmea.SentenceStart, talker+typ, fields))
+	}
+	for _, u := range d.Updates {
+		values := map[string]interface{}{}
+		for _, v := range u.Values {
+			values[v.Path] = v.Value
+		}
+		number := func(path string) (float64, bool) {
+			return toNumber(values[path])
+		}
+		t := u.Timestamp.UTC()
+
+		sog, sogOK := number("navigation.speedOverGround")
+		cog, cogOK := number("navigation.courseOverGroundTrue")
+		if p, ok := toPosition(values["navigation.position"]); ok {
+			lat, latDir := coord.NMEALat(p.Latitude, coord.DefaultPrecision)
+			lon, lonDir := coord.NMEALon(p.Longitude, coord.DefaultPrecision)
+			variation, variationDir := "", ""
+			if v, ok := number("navigation.magneticVariation"); ok {
+				variation = formatFloat(math.Abs(v/DegreeRads), 1)
+				variationDir = nmea.East
+				if v < 0 {
+					variationDir = nmea.West
+				}
+			}
+			add(nmea.TypeRMC,
+				formatTime(t), nmea.ValidRMC, lat, latDir, lon, lonDir,
+				optional(sog/Knot, sogOK, 2), optional(degrees(cog), cogOK, 1),
+				t.Format("020106"), variation, variationDir, "A")
+		} else if sogOK || cogOK {
+			cogMagnetic, cogMagneticOK := number("navigation.courseOverGroundMagnetic")
+			add(nmea.TypeVTG,
+				optional(degrees(cog), cogOK, 1), "T",
+				optional(degrees(cogMagnetic), cogMagneticOK, 1), "M",
+				optional(sog/Knot, sogOK, 2), "N",
+				optional(sog/KPH, sogOK, 2), "K", "A")
+		}
+
+		heading, headingOK := number("navigation.headingTrue")
+		if headingOK {
+			add(nmea.TypeHDT, formatFloat(degrees(heading), 1), "T")
+		}
+		if stw, ok := number("navigation.speedThroughWater"); ok {
+			magnetic, magneticOK := number("navigation.headingMagnetic")
+			add(nmea.TypeVHW,
+				optional(degrees(heading), headingOK, 1), "T",
+				optional(degrees(magnetic), magneticOK, 1), "M",
+				formatFloat(stw/Knot, 2), "N",
+				formatFloat(stw/KPH, 2), "K")
+		}
+
+		if depth, ok := number("environment.depth.belowTransducer"); ok {
+			offset := ""
+			if v, ok := number("environment.depth.surfaceToTransducer"); ok {
+				offset = formatFloat(v, 2)
+			} else if v, ok := number("environment.depth.transducerToKeel"); ok {
+				offset = formatFloat(-v, 2)
+			}
+			add(nmea.TypeDPT, formatFloat(depth, 2), offset, "")
+			add(nmea.TypeDBT,
+				formatFloat(depth/Foot, 2), "f",
+				formatFloat(depth, 2), "M",
+				formatFloat(depth/Fathom, 2), "F")
+		}
+		if depth, ok := number("environment.depth.belowSurface"); ok {
+			add(nmea.TypeDBS,
+				formatFloat(depth/Foot, 2), "f",
+				formatFloat(depth, 2), "M",
+				formatFloat(depth/Fathom, 2), "F")
+		}
+
+		if angle, ok := number("environment.wind.angleApparent"); ok {
+			if speed, ok := number("environment.wind.speedApparent"); ok {
+				add(nmea.TypeMWV, formatFloat(degrees(angle), 1), nmea.RelativeMWV, formatFloat(speed, 2), nmea.MPSMWV, nmea.ValidMWV)
+			}
+		}
+		if angle, ok := number("environment.wind.angleTrueWater"); ok {
+			if speed, ok := number("environment.wind.speedTrue"); ok {
+				add(nmea.TypeMWV, formatFloat(degrees(angle), 1), nmea.TrueMWV, formatFloat(speed, 2), nmea.MPSMWV, nmea.ValidMWV)
+			}
+		}
+	}
+	return sentences
+}
+
+// degrees converts an angle in radians to degrees in [0, 360).
+func degrees(rad float64) float64 {
+	d := math.Mod(rad/DegreeRads, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
+
+func formatFloat(v float64, precision int) string {
+	return strconv.FormatFloat(v, 'f', precision, 64)
+}
+
+// optional formats a value, or an empty field when it is not present.
+func optional(v float64, ok bool, precision int) string {
+	if !ok {
+		return ""
+	}
+	return formatFloat(v, precision)
+}
+
+func formatTime(t time.Time) string {
+	return fmt.Sprintf("%02d%02d%02d.%02d", t.Hour(), t.Minute(), t.Second(), t.Nanosecond()/1e7)
+}
+
+// toNumber returns a numeric value, as given or unmarshalled from JSON.
+func toNumber(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toPosition returns a position value, as given or unmarshalled from JSON.
+func toPosition(v interface{}) (Position, bool) {
+	switch x := v.(type) {
+	case Position:
+		return x, true
+	case *Position:
+		if x != nil {
+			return *x, true
+		}
+	case map[string]interface{}:
+		lat, latOK := toNumber(x["latitude"])
+		lon, lonOK := toNumber(x["longitude"])
+		return Position{Latitude: lat, Longitude: lon}, latOK && lonOK
+	}
+	return Position{}, false
+}
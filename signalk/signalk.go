@@ -0,0 +1,350 @@
+// Package signalk converts sentences to Signal K deltas, and Signal K
+// values back to sentences.
+//
+// Values follow the Signal K specification: paths like
+// navigation.speedOverGround or environment.depth.belowTransducer, and SI
+// units, i.e. meters, meters per second and radians. Each update is labelled
+// with the $source of the sentence, built from the source of its TAG Block,
+// or the Label of the converter, and its talker, e.g. "nmea0183.GP".
+package signalk
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/ais"
+)
+
+const (
+	// SelfContext is the context of the own vessel.
+	SelfContext = "vessels.self"
+
+	// DefaultLabel is the source label of sentences without a TAG Block
+	// source.
+	DefaultLabel = "nmea0183"
+
+	// SourceType is the type of the sources of converted sentences.
+	SourceType = "NMEA0183"
+
+	// mmsiContext prefixes the MMSI of AIS targets to form their context.
+	mmsiContext = "vessels.urn:mrn:imo:mmsi:"
+)
+
+// Unit conversions to SI.
+const (
+	Knot       = 1852.0 / 3600 // Meters per second
+	KPH        = 1 / 3.6       // Meters per second
+	MPH        = 0.44704       // Meters per second
+	Foot       = 0.3048        // Meters
+	Fathom     = 1.8288        // Meters
+	DegreeRads = math.Pi / 180 // Radians
+)
+
+// Delta is a Signal K delta message.
+type Delta struct {
+	Context string   `json:"context,omitempty"`
+	Updates []Update `json:"updates"`
+}
+
+// Update is a set of values from one source at one time.
+type Update struct {
+	SourceRef string    `json:"$source,omitempty"`
+	Source    *Source   `json:"source,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Values    []Value   `json:"values"`
+}
+
+// Source describes the sentence an update was converted from.
+type Source struct {
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	Talker   string `json:"talker,omitempty"`
+	Sentence string `json:"sentence,omitempty"`
+}
+
+// Value is the value of a path. Objects like positions are given as
+// structs, or as maps when unmarshalled from JSON.
+type Value struct {
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// Position is the value of navigation.position.
+type Position struct {
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	Altitude  *float64 `json:"altitude,omitempty"`
+}
+
+// methodQuality names the GGA fix qualities as navigation.gnss.methodQuality.
+var methodQuality = map[string]string{
+	nmea.Invalid: "no GPS",
+	nmea.GPS:     "GNSS Fix",
+	nmea.DGPS:    "DGNSS fix",
+	nmea.PPS:     "Precise GNSS",
+	nmea.RTK:     "RTK fixed integer",
+	nmea.FRTK:    "RTK float",
+	nmea.EST:     "Estimated (DR) mode",
+}
+
+// navigationState names the AIS navigation statuses as navigation.state.
+var navigationState = map[int]string{
+	ais.UnderWayUsingEngine:  "motoring",
+	ais.AtAnchor:             "anchored",
+	ais.NotUnderCommand:      "not under command",
+	ais.RestrictedManoeuvre:  "restricted manouverability",
+	ais.ConstrainedByDraught: "constrained by draft",
+	ais.Moored:               "moored",
+	ais.Aground:              "aground",
+	ais.EngagedInFishing:     "fishing",
+	ais.UnderWaySailing:      "sailing",
+}
+
+// Converter converts sentences to deltas. Times of sentences without a
+// TAG Block timestamp are resolved from the dates of RMC and ZDA sentences,
+// or the current time otherwise, and AIS fragments are reassembled.
+type Converter struct {
+	Self  string // Context of the own vessel
+	Label string // Source label of sentences without a TAG Block source
+
+	dates   *nmea.DateTracker
+	vessels *ais.Tracker
+	now     func() time.Time
+}
+
+// NewConverter constructor
+func NewConverter() *Converter {
+	return &Converter{
+		Self:    SelfContext,
+		Label:   DefaultLabel,
+		dates:   nmea.NewDateTracker(),
+		vessels: ais.NewTracker(),
+		now:     time.Now,
+	}
+}
+
+// Convert returns the delta of a sentence. False is returned for sentences
+// that are not supported, invalid, or incomplete AIS fragments.
+func (c *Converter) Convert(s nmea.Sentence) (Delta, bool) {
+	timestamp, ok := c.dates.Update(s)
+	base, _ := nmea.BaseSentenceOf(s)
+	if base.TagBlock.Time != 0 {
+		timestamp, ok = base.TagBlock.Timestamp(), true
+	}
+	if !ok {
+		timestamp = c.now()
+	}
+
+	context := c.Self
+	var values []Value
+	if m, ok := s.(nmea.VDMVDO); ok {
+		v, ok := c.vessels.Add(m)
+		if !ok {
+			return Delta{}, false
+		}
+		context = mmsiContext + strconv.FormatUint(uint64(v.MMSI), 10)
+		values = vesselValues(v)
+	} else {
+		values = c.values(s)
+	}
+	if len(values) == 0 {
+		return Delta{}, false
+	}
+
+	label := c.Label
+	if base.TagBlock.Source != "" {
+		label = base.TagBlock.Source
+	}
+	return Delta{
+		Context: context,
+		Updates: []Update{{
+			SourceRef: label + "." + s.TalkerID(),
+			Source: &Source{
+				Label:    label,
+				Type:     SourceType,
+				Talker:   s.TalkerID(),
+				Sentence: s.DataType(),
+			},
+			Timestamp: timestamp.UTC(),
+			Values:    values,
+		}},
+	}, true
+}
+
+// values returns the values of the own vessel carried by a sentence.
+func (c *Converter) values(s nmea.Sentence) []Value {
+	var values []Value
+	add := func(path string, value interface{}) {
+		values = append(values, Value{path, value})
+	}
+	switch m := s.(type) {
+	case nmea.RMC:
+		if m.Validity != nmea.ValidRMC {
+			return nil
+		}
+		add("navigation.position", Position{Latitude: m.Latitude, Longitude: m.Longitude})
+		if m.SpeedValid {
+			add("navigation.speedOverGround", m.Speed*Knot)
+		}
+		if m.CourseValid {
+			add("navigation.courseOverGroundTrue", m.Course*DegreeRads)
+		}
+		if m.VariationValid {
+			add("navigation.magneticVariation", m.Variation*DegreeRads)
+		}
+		if t, err := nmea.DateTime(c.dates.Pivot, m.Date, m.Time); err == nil {
+			add("navigation.datetime", t.Format(time.RFC3339Nano))
+		}
+	case nmea.GGA:
+		if m.FixQuality == nmea.Invalid {
+			return nil
+		}
+		p := Position{Latitude: m.Latitude, Longitude: m.Longitude}
+		if m.AltitudeValid {
+			altitude := m.Altitude
+			p.Altitude = &altitude
+		}
+		add("navigation.position", p)
+		if q, ok := methodQuality[m.FixQuality]; ok {
+			add("navigation.gnss.methodQuality", q)
+		}
+		add("navigation.gnss.satellites", m.NumSatellites)
+		if m.HDOPValid {
+			add("navigation.gnss.horizontalDilution", m.HDOP)
+		}
+		if m.AltitudeValid {
+			add("navigation.gnss.antennaAltitude", m.Altitude)
+		}
+		if m.SeparationValid {
+			add("navigation.gnss.geoidalSeparation", m.Separation)
+		}
+	case nmea.VTG:
+		if m.Mode == nmea.NotValidVTG {
+			return nil
+		}
+		if m.TrueTrackValid {
+			add("navigation.courseOverGroundTrue", m.TrueTrack*DegreeRads)
+		}
+		if m.MagneticTrackValid {
+			add("navigation.courseOverGroundMagnetic", m.MagneticTrack*DegreeRads)
+		}
+		if m.GroundSpeedKnotsValid {
+			add("navigation.speedOverGround", m.GroundSpeedKnots*Knot)
+		}
+	case nmea.HDT:
+		if m.True {
+			add("navigation.headingTrue", m.Heading*DegreeRads)
+		}
+	case nmea.THS:
+		if m.Status != nmea.InvalidTHS {
+			add("navigation.headingTrue", m.Heading*DegreeRads)
+		}
+	case nmea.VHW:
+		if m.TrueHeadingValid {
+			add("navigation.headingTrue", m.TrueHeading*DegreeRads)
+		}
+		if m.MagneticHeadingValid {
+			add("navigation.headingMagnetic", m.MagneticHeading*DegreeRads)
+		}
+		if m.SpeedThroughWaterKnotsValid {
+			add("navigation.speedThroughWater", m.SpeedThroughWaterKnots*Knot)
+		}
+	case nmea.DPT:
+		if !m.DepthValid {
+			return nil
+		}
+		add("environment.depth.belowTransducer", m.Depth)
+		switch {
+		case m.OffsetValid && m.Offset > 0:
+			add("environment.depth.surfaceToTransducer", m.Offset)
+			add("environment.depth.belowSurface", m.Depth+m.Offset)
+		case m.OffsetValid && m.Offset < 0:
+			add("environment.depth.transducerToKeel", -m.Offset)
+			add("environment.depth.belowKeel", m.Depth+m.Offset)
+		}
+	case nmea.DBT:
+		if m.DepthMetersValid {
+			add("environment.depth.belowTransducer", m.DepthMeters)
+		}
+	case nmea.DBS:
+		if m.DepthMetersValid {
+			add("environment.depth.belowSurface", m.DepthMeters)
+		}
+	case nmea.MWV:
+		if m.Status != nmea.ValidMWV {
+			return nil
+		}
+		speed := m.WindSpeed
+		switch m.WindSpeedUnit {
+		case nmea.KPHMWV:
+			speed *= KPH
+		case nmea.KnotsMWV:
+			speed *= Knot
+		case nmea.MPHMWV:
+			speed *= MPH
+		}
+		angle := math.Mod(m.WindAngle, 360)
+		if angle > 180 {
+			angle -= 360
+		}
+		if m.Reference == nmea.RelativeMWV {
+			add("environment.wind.angleApparent", angle*DegreeRads)
+			add("environment.wind.speedApparent", speed)
+		} else {
+			add("environment.wind.angleTrueWater", angle*DegreeRads)
+			add("environment.wind.speedTrue", speed)
+		}
+	}
+	return values
+}
+
+// vesselValues returns the values known of an AIS target.
+func vesselValues(v ais.Vessel) []Value {
+	identity := map[string]interface{}{"mmsi": strconv.FormatUint(uint64(v.MMSI), 10)}
+	if v.Name != "" {
+		identity["name"] = v.Name
+	}
+	values := []Value{{"", identity}}
+	add := func(path string, value interface{}) {
+		values = append(values, Value{path, value})
+	}
+	if v.Class != "" {
+		add("sensors.ais.class", v.Class)
+	}
+	if v.CallSign != "" {
+		add("communication.callsignVhf", v.CallSign)
+	}
+	if v.PositionValid {
+		add("navigation.position", Position{Latitude: v.Latitude, Longitude: v.Longitude})
+	}
+	if v.SpeedOverGroundValid {
+		add("navigation.speedOverGround", v.SpeedOverGround*Knot)
+	}
+	if v.CourseOverGroundValid {
+		add("navigation.courseOverGroundTrue", v.CourseOverGround*DegreeRads)
+	}
+	if v.TrueHeadingValid {
+		add("navigation.headingTrue", float64(v.TrueHeading)*DegreeRads)
+	}
+	if state, ok := navigationState[v.NavigationStatus]; ok {
+		add("navigation.state", state)
+	}
+	if v.Destination != "" {
+		add("navigation.destination.commonName", v.Destination)
+	}
+	if v.Length > 0 {
+		add("design.length", map[string]interface{}{"overall": float64(v.Length)})
+	}
+	if v.Beam > 0 {
+		add("design.beam", float64(v.Beam))
+	}
+	if v.Draught > 0 {
+		add("design.draft", map[string]interface{}{"current": v.Draught})
+	}
+	if v.ShipType > 0 {
+		add("design.aisShipType", map[string]interface{}{"id": v.ShipType})
+	}
+	return values
+}
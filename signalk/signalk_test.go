@@ -0,0 +1,346 @@
+package signalk
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+)
+
+var now = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func newTestConverter() *Converter {
+	c := NewConverter()
+	c.now = func() time.Time { return now }
+	return c
+}
+
+var converttests = []struct {
+	name    string
+	raw     string
+	context string
+	source  string
+	time    time.Time
+	values  map[string]interface{}
+}{
+	{
+		name:    "RMC",
+		raw:     "$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70",
+		context: SelfContext,
+		source:  "nmea0183.GP",
+		time:    time.Date(1994, 6, 13, 22, 5, 16, 0, time.UTC),
+		values: map[string]interface{}{
+			"navigation.position":             Position{Latitude: 51.56366666666666, Longitude: -0.7040000000000001},
+			"navigation.speedOverGround":      89.41044444444445,
+			"navigation.courseOverGroundTrue": 4.045673206122856,
+			"navigation.magneticVariation":    -0.07330382858376185,
+			"navigation.datetime":             "1994-06-13T22:05:16Z",
+		},
+	},
+	{
+		name:    "GGA",
+		raw:     "$GPGGA,034225.077,3356.4650,S,15124.5567,E,1,03,9.7,-25.0,M,21.0,M,,0000*51",
+		context: SelfContext,
+		source:  "nmea0183.GP",
+		time:    now,
+		values: map[string]interface{}{
+			"navigation.position":                Position{Latitude: -33.94108333333334, Longitude: 151.40927833333333, Altitude: float64Ptr(-25)},
+			"navigation.gnss.methodQuality":      "GNSS Fix",
+			"navigation.gnss.satellites":         int64(3),
+			"navigation.gnss.horizontalDilution": 9.7,
+			"navigation.gnss.antennaAltitude":    -25.0,
+			"navigation.gnss.geoidalSeparation":  21.0,
+		},
+	},
+	{
+		name:    "VTG",
+		raw:     "$GPVTG,45.5,T,67.5,M,30.45,N,56.40,K*4B",
+		context: SelfContext,
+		source:  "nmea0183.GP",
+		time:    now,
+		values: map[string]interface{}{
+			"navigation.courseOverGroundTrue":     0.7941248096574199,
+			"navigation.courseOverGroundMagnetic": 1.1780972450961724,
+			"navigation.speedOverGround":          15.664833333333334,
+		},
+	},
+	{
+		name:    "HDT",
+		raw:     "$GPHDT,123.456,T*32",
+		context: SelfContext,
+		source:  "nmea0183.GP",
+		time:    now,
+		values: map[string]interface{}{
+			"navigation.headingTrue": 2.1547136813421197,
+		},
+	},
+	{
+		name:    "THS",
+		raw:     "$INTHS,123.456,A*20",
+		context: SelfContext,
+		source:  "nmea0183.IN",
+		time:    now,
+		values: map[string]interface{}{
+			"navigation.headingTrue": 2.1547136813421197,
+		},
+	},
+	{
+		name:    "VHW",
+		raw:     "$VWVHW,45.0,T,43.0,M,3.5,N,6.4,K*56",
+		context: SelfContext,
+		source:  "nmea0183.VW",
+		time:    now,
+		values: map[string]interface{}{
+			"navigation.headingTrue":       0.7853981633974483,
+			"navigation.headingMagnetic":   0.7504915783575618,
+			"navigation.speedThroughWater": 1.8005555555555557,
+		},
+	},
+	{
+		name:    "VTG without course",
+		raw:     "$GPVTG,,T,,M,30.45,N,56.40,K,A*26",
+		context: SelfContext,
+		source:  "nmea0183.GP",
+		time:    now,
+		values: map[string]interface{}{
+			"navigation.speedOverGround": 15.664833333333334,
+		},
+	},
+	{
+		name:    "VHW without heading",
+		raw:     "$VWVHW,,T,,M,3.5,N,6.4,K*50",
+		context: SelfContext,
+		source:  "nmea0183.VW",
+		time:    now,
+		values: map[string]interface{}{
+			"navigation.speedThroughWater": 1.8005555555555557,
+		},
+	},
+	{
+		name:    "DPT with keel offset",
+		raw:     "$SDDPT,10.5,-1.5,*66",
+		context: SelfContext,
+		source:  "nmea0183.SD",
+		time:    now,
+		values: map[string]interface{}{
+			"environment.depth.belowTransducer":  10.5,
+			"environment.depth.transducerToKeel": 1.5,
+			"environment.depth.belowKeel":        9.0,
+		},
+	},
+	{
+		name:    "DBT",
+		raw:     "$SDDBT,32.8,f,10.0,M,5.5,F*0E",
+		context: SelfContext,
+		source:  "nmea0183.SD",
+		time:    now,
+		values: map[string]interface{}{
+			"environment.depth.belowTransducer": 10.0,
+		},
+	},
+	{
+		name:    "DBS",
+		raw:     "$SDDBS,32.8,f,10.0,M,5.5,F*09",
+		context: SelfContext,
+		source:  "nmea0183.SD",
+		time:    now,
+		values: map[string]interface{}{
+			"environment.depth.belowSurface": 10.0,
+		},
+	},
+	{
+		name:    "MWV apparent wind from port",
+		raw:     "$WIMWV,270.0,R,10.0,N,A*17",
+		context: SelfContext,
+		source:  "nmea0183.WI",
+		time:    now,
+		values: map[string]interface{}{
+			"environment.wind.angleApparent": -1.5707963267948966,
+			"environment.wind.speedApparent": 5.144444444444445,
+		},
+	},
+	{
+		name:    "MWV true wind",
+		raw:     "$WIMWV,12.5,T,8.3,M,A*1B",
+		context: SelfContext,
+		source:  "nmea0183.WI",
+		time:    now,
+		values: map[string]interface{}{
+			"environment.wind.angleTrueWater": 0.2181661564992912,
+			"environment.wind.speedTrue":      8.3,
+		},
+	},
+	{
+		name:    "AIS with TAG Block",
+		raw:     "\\s:Satelite_1,c:1553390539*62\\!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A",
+		context: "vessels.urn:mrn:imo:mmsi:371798000",
+		source:  "Satelite_1.AI",
+		time:    time.Unix(1553390539, 0).UTC(),
+		values: map[string]interface{}{
+			"":                                map[string]interface{}{"mmsi": "371798000"},
+			"sensors.ais.class":               "A",
+			"navigation.position":             Position{Latitude: 48.38163333333333, Longitude: -123.39538333333333},
+			"navigation.speedOverGround":      6.327666666666667,
+			"navigation.courseOverGroundTrue": 3.9095375244672983,
+			"navigation.headingTrue":          3.7524578917878086,
+			"navigation.state":                "motoring",
+		},
+	},
+}
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func TestConvert(t *testing.T) {
+	for _, tt := range converttests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := nmea.Parse(tt.raw)
+			if !assert.NoError(t, err) {
+				return
+			}
+			d, ok := newTestConverter().Convert(s)
+			if !assert.True(t, ok) || !assert.Len(t, d.Updates, 1) {
+				return
+			}
+			assert.Equal(t, tt.context, d.Context)
+			u := d.Updates[0]
+			assert.Equal(t, tt.source, u.SourceRef)
+			assert.Equal(t, s.DataType(), u.Source.Sentence)
+			assert.Equal(t, tt.time, u.Timestamp)
+			values := map[string]interface{}{}
+			for _, v := range u.Values {
+				values[v.Path] = v.Value
+			}
+			assert.Equal(t, tt.values, values)
+		})
+	}
+}
+
+func TestConvertInvalid(t *testing.T) {
+	for _, raw := range []string{
+		"$GPRMC,220516,V,,,,,,,130694,,*3A",
+		"$GNGGA,,,,,,0,,,,M,,M,,*78",
+		"$INTHS,0.0,V*30",
+		"$GPVTG,,T,,M,,N,,K,N*2C",
+		"$GPVTG,45.5,T,67.5,M,30.45,N,56.40,K,N*29",
+		"$SDDBT,,f,,M,,F*28",
+		"$SDDPT,,,*7B",
+		"$WIMWV,214.8,R,0.1,K,V*3F",
+		"$PGRME,3.3,M,4.9,M,6.0,M*25",
+		"!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C",
+	} {
+		t.Run(raw, func(t *testing.T) {
+			s, err := nmea.Parse(raw)
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, ok := newTestConverter().Convert(s)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestConvertResolvesDates(t *testing.T) {
+	c := newTestConverter()
+	for _, raw := range []string{
+		"$GPRMC,220516,A,5133.82,N,00042.24,W,173.8,231.8,130694,004.2,W*70",
+		"$GPGGA,034225.077,3356.4650,S,15124.5567,E,1,03,9.7,-25.0,M,21.0,M,,0000*51",
+	} {
+		s, err := nmea.Parse(raw)
+		if !assert.NoError(t, err) {
+			return
+		}
+		d, ok := c.Convert(s)
+		assert.True(t, ok)
+		if s.DataType() == nmea.TypeGGA {
+			assert.Equal(t, time.Date(1994, 6, 14, 3, 42, 25, 77e6, time.UTC), d.Updates[0].Timestamp)
+		}
+	}
+}
+
+func TestDeltaJSON(t *testing.T) {
+	s, err := nmea.Parse("$GPHDT,123.456,T*32")
+	if !assert.NoError(t, err) {
+		return
+	}
+	c := newTestConverter()
+	c.Label = "gyro"
+	d, _ := c.Convert(s)
+	b, err := json.Marshal(d)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"context": "vessels.self",
+		"updates": [{
+			"$source": "gyro.GP",
+			"source": {"label": "gyro", "type": "NMEA0183", "talker": "GP", "sentence": "HDT"},
+			"timestamp": "2020-01-02T03:04:05Z",
+			"values": [{"path": "navigation.headingTrue", "value": 2.1547136813421197}]
+		}]
+	}`, string(b))
+}
+
+func TestSentences(t *testing.T) {
+	var d Delta
+	err := json.Unmarshal([]byte(`{
+		"context": "vessels.self",
+		"updates": [{
+			"timestamp": "2020-01-02T03:04:05.25Z",
+			"values": [
+				{"path": "navigation.position", "value": {"latitude": 51.5636, "longitude": -0.704}},
+				{"path": "navigation.speedOverGround", "value": 3.0866666666666664},
+				{"path": "navigation.courseOverGroundTrue", "value": 4.045673206122856},
+				{"path": "navigation.magneticVariation", "value": -0.07330382858376185},
+				{"path": "navigation.headingTrue", "value": 4.0},
+				{"path": "navigation.speedThroughWater", "value": 2.5},
+				{"path": "environment.depth.belowTransducer", "value": 10.5},
+				{"path": "environment.depth.surfaceToTransducer", "value": 0.5},
+				{"path": "environment.depth.belowSurface", "value": 11},
+				{"path": "environment.wind.angleApparent", "value": -1.5707963267948966},
+				{"path": "environment.wind.speedApparent", "value": 5.2}
+			]
+		}, {
+			"timestamp": "2020-01-02T03:04:06Z",
+			"values": [
+				{"path": "navigation.speedOverGround", "value": 3.0866666666666664},
+				{"path": "environment.wind.angleTrueWater", "value": 0.2181661564992912},
+				{"path": "environment.wind.speedTrue", "value": 8.3}
+			]
+		}]
+	}`), &d)
+	if !assert.NoError(t, err) {
+		return
+	}
+	c := NewConverter()
+	sentences := c.Sentences(d, "II")
+	assert.Equal(t, []string{
+		"$IIRMC,030405.25,A,5133.8160,N,00042.2400,W,6.00,231.8,020120,4.2,W,A*15",
+		"$IIHDT,229.2,T*29",
+		"$IIVHW,229.2,T,,M,4.86,N,9.00,K*73",
+		"$IIDPT,10.50,0.50,*5D",
+		"$IIDBT,34.45,f,10.50,M,5.74,F*25",
+		"$IIDBS,36.09,f,11.00,M,6.01,F*2D",
+		"$IIMWV,270.0,R,5.20,M,A*0C",
+		"$IIVTG,,T,,M,6.00,N,11.11,K,A*02",
+		"$IIMWV,12.5,T,8.30,M,A*35",
+	}, sentences)
+	for _, raw := range sentences {
+		_, err := nmea.Parse(raw)
+		assert.NoError(t, err, raw)
+	}
+
+	d.Context = "vessels.urn:mrn:imo:mmsi:371798000"
+	assert.Empty(t, c.Sentences(d, "II"))
+}
+
+func TestRoundTrip(t *testing.T) {
+	c := newTestConverter()
+	s, err := nmea.Parse("$WIMWV,214.8,R,12.4,M,A*18")
+	if !assert.NoError(t, err) {
+		return
+	}
+	d, ok := c.Convert(s)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"$WIMWV,214.8,R,12.40,M,A*28"}, c.Sentences(d, "WI"))
+}
@@ -0,0 +1,436 @@
+// Package sim simulates a receiver on a vessel following a route, to test
+// navigation software without hardware.
+//
+// Every epoch the vessel moves along the legs between its waypoints at the
+// configured speed, and the simulator emits the sentences a receiver,
+// compass and echo sounder would send: RMC, GGA, GSA, GSV, VTG, ZDA, HDT
+// and DPT. Positions, course, speed, heading and depth are blurred with
+// gaussian noise, and fixes drop out at random. The noise, dropouts and
+// satellite geometry come from a seeded source, so a simulation is
+// repeated exactly with the same seed.
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/coord"
+	"github.com/storskegg/go-nmea/geo"
+	"github.com/storskegg/go-nmea/gpx"
+)
+
+const (
+	// DefaultTalker is the talker of the receiver sentences.
+	DefaultTalker = "GP"
+
+	// DefaultSpeed is the speed of the vessel in knots.
+	DefaultSpeed = 6.0
+
+	// DefaultInterval is the time between two epochs.
+	DefaultInterval = time.Second
+
+	// DefaultSatellites is the number of satellites in view.
+	DefaultSatellites = 10
+
+	// MaskAngle is the elevation in degrees below which satellites are
+	// not used in the fix.
+	MaskAngle = 10.0
+
+	// headingTalker and depthTalker are the talkers of the gyro compass
+	// and the echo sounder.
+	headingTalker = "HE"
+	depthTalker   = "SD"
+
+	// maxUsed is the number of satellites a GSA sentence holds.
+	maxUsed = 12
+
+	// knot is a knot in meters per second.
+	knot = geo.NauticalMile / 3600
+)
+
+// ErrNoWaypoints is returned for a GPX document without a route or track.
+var ErrNoWaypoints = errors.New("sim: no route or track")
+
+// Satellite is a satellite in view.
+type Satellite struct {
+	PRN       int64
+	Elevation float64 // Degrees above the horizon
+	Azimuth   float64 // Degrees from true north
+}
+
+// Simulator moves a vessel along waypoints and emits the sentences of
+// every epoch.
+type Simulator struct {
+	Talker        string        // Talker of the receiver sentences, e.g. GP
+	Speed         float64       // Speed through the route in knots
+	Interval      time.Duration // Time between two epochs, the output rate
+	Loop          bool          // Start over at the first waypoint after the last one
+	PositionNoise float64       // Standard deviation of the position error in meters
+	CourseNoise   float64       // Standard deviation of the course and heading errors in degrees
+	SpeedNoise    float64       // Standard deviation of the speed error in knots
+	Dropout       float64       // Probability of an epoch without a fix, from 0 to 1
+	Altitude      float64       // Antenna altitude above mean sea level in meters
+	Depth         float64       // Depth below the transducer in meters, 0 sends no DPT
+	DepthNoise    float64       // Standard deviation of the depth error in meters
+
+	// Satellites are the satellites in view. Those above the MaskAngle are
+	// used in the fix, which is lost with fewer than 4 of them.
+	Satellites []Satellite
+
+	waypoints []geo.Waypoint
+	rand      *rand.Rand
+	after     func(time.Duration) <-chan time.Time
+
+	mu       sync.Mutex
+	time     time.Time
+	position geo.LatLon
+	leg      int // Index of the waypoint steered to
+	stop     chan struct{}
+	stopped  bool
+}
+
+// NewSimulator constructor
+func NewSimulator(waypoints []geo.Waypoint, start time.Time, seed int64) *Simulator {
+	r := rand.New(rand.NewSource(seed))
+	s := &Simulator{
+		Talker:     DefaultTalker,
+		Speed:      DefaultSpeed,
+		Interval:   DefaultInterval,
+		Satellites: RandomSatellites(r, DefaultSatellites),
+		waypoints:  waypoints,
+		rand:       r,
+		after:      time.After,
+		time:       start.UTC(),
+		leg:        1,
+		stop:       make(chan struct{}),
+	}
+	if len(waypoints) > 0 {
+		s.position = waypoints[0].LatLon
+	}
+	return s
+}
+
+// Waypoints returns the points of the first route of a GPX document, or of
+// the first track when it has no route.
+func Waypoints(g gpx.GPX) ([]geo.Waypoint, error) {
+	var points []gpx.Point
+	if len(g.Routes) > 0 {
+		points = g.Routes[0].Points
+	} else if len(g.Tracks) > 0 {
+		for _, seg := range g.Tracks[0].Segments {
+			points = append(points, seg.Points...)
+		}
+	}
+	if len(points) == 0 {
+		return nil, ErrNoWaypoints
+	}
+	waypoints := make([]geo.Waypoint, len(points))
+	for i, p := range points {
+		waypoints[i] = geo.Waypoint{ID: p.Name, LatLon: geo.LatLon{Lat: p.Lat, Lon: p.Lon}}
+	}
+	return waypoints, nil
+}
+
+// RandomSatellites returns n satellites with distinct GPS PRNs, spread
+// over the sky.
+func RandomSatellites(r *rand.Rand, n int) []Satellite {
+	prns := r.Perm(32)
+	if n > len(prns) {
+		n = len(prns)
+	}
+	sats := make([]Satellite, n)
+	for i := range sats {
+		sats[i] = Satellite{
+			PRN:       int64(prns[i] + 1),
+			Elevation: math.Round(5 + r.Float64()*80),
+			Azimuth:   math.Round(r.Float64() * 359),
+		}
+	}
+	return sats
+}
+
+// Time returns the time of the next epoch.
+func (s *Simulator) Time() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.time
+}
+
+// Position returns the true position of the vessel at the next epoch.
+func (s *Simulator) Position() geo.LatLon {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.position
+}
+
+// Done reports whether the vessel has reached the last waypoint. It never
+// does when looping.
+func (s *Simulator) Done() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.leg >= len(s.waypoints)
+}
+
+// Step returns the sentences of the current epoch and moves the vessel to
+// the next one.
+func (s *Simulator) Step() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	course, speed := 0.0, 0.0
+	if s.leg < len(s.waypoints) {
+		course = geo.InitialBearing(s.position, s.waypoints[s.leg].LatLon)
+		speed = s.Speed
+	}
+	sentences := s.epoch(course, speed)
+	s.advance(speed * knot * s.Interval.Seconds())
+	s.time = s.time.Add(s.Interval)
+	return sentences
+}
+
+// Run writes the sentences of an epoch to w, each one in a single write,
+// every Interval until the vessel reaches the last waypoint, the simulator
+// is stopped or a write fails.
+func (s *Simulator) Run(w io.Writer) error {
+	for {
+		s.mu.Lock()
+		stop, interval := s.stop, s.Interval
+		s.mu.Unlock()
+
+		done := s.Done()
+		for _, line := range s.Step() {
+			if _, err := io.WriteString(w, line+nmea.CRLF); err != nil {
+				return err
+			}
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-s.after(interval):
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// Stop makes Run return.
+func (s *Simulator) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.stopped {
+		close(s.stop)
+		s.stopped = true
+	}
+}
+
+// advance moves the vessel the distance along the legs of the route.
+func (s *Simulator) advance(distance float64) {
+	for distance > 0 && s.leg < len(s.waypoints) {
+		to := s.waypoints[s.leg].LatLon
+		remaining := geo.Distance(s.position, to)
+		if distance < remaining {
+			s.position = geo.Destination(s.position, geo.InitialBearing(s.position, to), distance)
+			return
+		}
+		distance -= remaining
+		s.position = to
+		s.leg++
+		if s.leg == len(s.waypoints) && s.Loop && len(s.waypoints) > 1 {
+			s.position = s.waypoints[0].LatLon
+			s.leg = 1
+		}
+	}
+}
+
+// epoch formats the sentences of the current epoch.
+func (s *Simulator) epoch(course, speed float64) []string {
+	var sentences []string
+	add := func(talker, typ string, fields ...string) {
+		sentences = append(sentences, nmea.FormatSentence(nmea.SentenceStart, talker+typ, fields))
+	}
+
+	t := s.time
+	hms := fmt.Sprintf("%02d%02d%02d.%02d", t.Hour(), t.Minute(), t.Second(), t.Nanosecond()/1e7)
+	used := s.used()
+	pdop, hdop, vdop, ok := dop(used)
+	fix := ok && s.rand.Float64() >= s.Dropout
+
+	sog := math.Max(0, speed+s.noise(s.SpeedNoise))
+	cog := normalize(course + s.noise(s.CourseNoise))
+	if fix {
+		p := s.position
+		if s.PositionNoise > 0 {
+			p = geo.Destination(p, s.rand.Float64()*360, math.Abs(s.noise(s.PositionNoise)))
+		}
+		lat, latDir := coord.NMEALat(p.Lat, coord.DefaultPrecision)
+		lon, lonDir := coord.NMEALon(p.Lon, coord.DefaultPrecision)
+		add(s.Talker, nmea.TypeRMC, hms, nmea.ValidRMC, lat, latDir, lon, lonDir,
+			formatFloat(sog, 1), formatFloat(cog, 1), t.Format("020106"), "", "", "A")
+		add(s.Talker, nmea.TypeGGA, hms, lat, latDir, lon, lonDir, nmea.GPS,
+			fmt.Sprintf("%02d", len(used)), formatFloat(hdop, 1),
+			formatFloat(s.Altitude, 1), "M", "", "M", "", "")
+		gsa := []string{nmea.Auto, nmea.Fix3D}
+		for i := 0; i < maxUsed; i++ {
+			if i < len(used) {
+				gsa = append(gsa, fmt.Sprintf("%02d", used[i].PRN))
+			} else {
+				gsa = append(gsa, "")
+			}
+		}
+		add(s.Talker, nmea.TypeGSA, append(gsa, formatFloat(pdop, 1), formatFloat(hdop, 1), formatFloat(vdop, 1))...)
+	} else {
+		add(s.Talker, nmea.TypeRMC, hms, nmea.InvalidRMC, "", "", "", "", "", "", t.Format("020106"), "", "", "N")
+		add(s.Talker, nmea.TypeGGA, hms, "", "", "", "", nmea.Invalid, "00", "", "", "M", "", "M", "", "")
+		add(s.Talker, nmea.TypeGSA, nmea.Auto, nmea.FixNone, "", "", "", "", "", "", "", "", "", "", "", "", "", "", "")
+	}
+	for _, gsv := range s.gsv() {
+		add(s.Talker, nmea.TypeGSV, gsv...)
+	}
+	if fix {
+		add(s.Talker, nmea.TypeVTG, formatFloat(cog, 1), "T", "", "M",
+			formatFloat(sog, 1), "N", formatFloat(sog*geo.NauticalMile/1000, 1), "K", "A")
+	} else {
+		add(s.Talker, nmea.TypeVTG, "", "T", "", "M", "", "N", "", "K", "N")
+	}
+	add(s.Talker, nmea.TypeZDA, hms, fmt.Sprintf("%02d", t.Day()), fmt.Sprintf("%02d", t.Month()),
+		strconv.Itoa(t.Year()), "00", "00")
+
+	add(headingTalker, nmea.TypeHDT, formatFloat(normalize(course+s.noise(s.CourseNoise)), 1), "T")
+	if s.Depth > 0 {
+		add(depthTalker, nmea.TypeDPT, formatFloat(math.Max(0, s.Depth+s.noise(s.DepthNoise)), 1), "", "")
+	}
+	return sentences
+}
+
+// used returns the satellites used in the fix, the highest first.
+func (s *Simulator) used() []Satellite {
+	var used []Satellite
+	for _, sat := range s.Satellites {
+		if sat.Elevation >= MaskAngle {
+			used = append(used, sat)
+		}
+	}
+	for i := 1; i < len(used); i++ {
+		for j := i; j > 0 && used[j].Elevation > used[j-1].Elevation; j-- {
+			used[j], used[j-1] = used[j-1], used[j]
+		}
+	}
+	if len(used) > maxUsed {
+		used = used[:maxUsed]
+	}
+	return used
+}
+
+// gsv returns the fields of the GSV sentences of the satellites in view,
+// four per sentence. Signal strengths rise with the elevation.
+func (s *Simulator) gsv() [][]string {
+	total := (len(s.Satellites) + 3) / 4
+	if total == 0 {
+		return [][]string{{"1", "1", "00"}}
+	}
+	var sentences [][]string
+	for n := 0; n < total; n++ {
+		fields := []string{strconv.Itoa(total), strconv.Itoa(n + 1), fmt.Sprintf("%02d", len(s.Satellites))}
+		for _, sat := range s.Satellites[n*4 : minInt(n*4+4, len(s.Satellites))] {
+			snr := math.Round(25 + sat.Elevation/90*25 + s.noise(2))
+			fields = append(fields,
+				fmt.Sprintf("%02d", sat.PRN),
+				fmt.Sprintf("%02d", int(math.Round(sat.Elevation))),
+				fmt.Sprintf("%03d", int(math.Round(sat.Azimuth))%360),
+				fmt.Sprintf("%02d", int(math.Max(0, math.Min(99, snr)))))
+		}
+		sentences = append(sentences, fields)
+	}
+	return sentences
+}
+
+// noise returns a gaussian error with the standard deviation.
+func (s *Simulator) noise(stddev float64) float64 {
+	if stddev <= 0 {
+		return 0
+	}
+	return s.rand.NormFloat64() * stddev
+}
+
+// dop returns the position, horizontal and vertical dilutions of precision
+// of the satellites, or false with fewer than 4 of them or a degenerate
+// geometry.
+func dop(sats []Satellite) (pdop, hdop, vdop float64, ok bool) {
+	if len(sats) < 4 {
+		return 0, 0, 0, false
+	}
+	// normal matrix of the unit vectors to the satellites, east, north
+	// and up, and the receiver clock
+	var m [4][8]float64
+	for _, sat := range sats {
+		el, az := sat.Elevation*math.Pi/180, sat.Azimuth*math.Pi/180
+		row := [4]float64{math.Cos(el) * math.Sin(az), math.Cos(el) * math.Cos(az), math.Sin(el), 1}
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 4; j++ {
+				m[i][j] += row[i] * row[j]
+			}
+		}
+	}
+	// invert it with Gauss-Jordan elimination
+	for i := 0; i < 4; i++ {
+		m[i][4+i] = 1
+	}
+	for c := 0; c < 4; c++ {
+		pivot := c
+		for r := c + 1; r < 4; r++ {
+			if math.Abs(m[r][c]) > math.Abs(m[pivot][c]) {
+				pivot = r
+			}
+		}
+		if math.Abs(m[pivot][c]) < 1e-12 {
+			return 0, 0, 0, false
+		}
+		m[c], m[pivot] = m[pivot], m[c]
+		d := m[c][c]
+		for j := range m[c] {
+			m[c][j] /= d
+		}
+		for r := 0; r < 4; r++ {
+			if r != c {
+				f := m[r][c]
+				for j := range m[r] {
+					m[r][j] -= f * m[c][j]
+				}
+			}
+		}
+	}
+	e, n, u := m[0][4], m[1][5], m[2][6]
+	return math.Sqrt(e + n + u), math.Sqrt(e + n), math.Sqrt(u), true
+}
+
+// normalize returns a bearing in [0, 360).
+func normalize(b float64) float64 {
+	b = math.Mod(b, 360)
+	if b < 0 {
+		b += 360
+	}
+	return b
+}
+
+func formatFloat(v float64, precision int) string {
+	return strconv.FormatFloat(v, 'f', precision, 64)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
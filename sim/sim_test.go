@@ -0,0 +1,311 @@
+package sim
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nmea "github.com/storskegg/go-nmea"
+	"github.com/storskegg/go-nmea/geo"
+	"github.com/storskegg/go-nmea/gpx"
+)
+
+var (
+	start = time.Date(2020, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	// a mile north, then a mile east
+	route = []geo.Waypoint{
+		{ID: "A", LatLon: geo.LatLon{Lat: 50, Lon: -1}},
+		{ID: "B", LatLon: geo.Destination(geo.LatLon{Lat: 50, Lon: -1}, 0, geo.NauticalMile)},
+		{ID: "C", LatLon: geo.Destination(geo.Destination(geo.LatLon{Lat: 50, Lon: -1}, 0, geo.NauticalMile), 90, geo.NauticalMile)},
+	}
+)
+
+func types(lines []string) []string {
+	var prefixes []string
+	for _, line := range lines {
+		prefixes = append(prefixes, line[1:strings.IndexByte(line, ',')])
+	}
+	return prefixes
+}
+
+func TestStep(t *testing.T) {
+	var tests = []struct {
+		name  string
+		setup func(s *Simulator)
+		types []string
+		check func(t *testing.T, sentences []nmea.Sentence)
+	}{
+		{
+			name: "fix",
+			setup: func(s *Simulator) {
+				s.Depth = 12.5
+				s.Satellites[0].Elevation = MaskAngle - 1
+			},
+			types: []string{"GPRMC", "GPGGA", "GPGSA", "GPGSV", "GPGSV", "GPGSV", "GPVTG", "GPZDA", "HEHDT", "SDDPT"},
+			check: func(t *testing.T, sentences []nmea.Sentence) {
+				rmc := sentences[0].(nmea.RMC)
+				assert.Equal(t, nmea.ValidRMC, rmc.Validity)
+				assert.InDelta(t, 50, rmc.Latitude, 1e-6)
+				assert.InDelta(t, -1, rmc.Longitude, 1e-6)
+				assert.Equal(t, 6.0, rmc.Speed)
+				assert.Equal(t, 0.0, rmc.Course)
+				assert.Equal(t, nmea.Date{Valid: true, DD: 6, MM: 5, YY: 20}, rmc.Date)
+				assert.Equal(t, nmea.Time{Valid: true, Hour: 7, Minute: 8, Second: 9}, rmc.Time)
+
+				gga := sentences[1].(nmea.GGA)
+				assert.Equal(t, nmea.GPS, gga.FixQuality)
+				assert.Equal(t, int64(9), gga.NumSatellites)
+
+				gsa := sentences[2].(nmea.GSA)
+				assert.Equal(t, nmea.Fix3D, gsa.FixType)
+				assert.Len(t, gsa.SV, 9)
+				assert.Equal(t, gga.HDOP, gsa.HDOP)
+
+				for i, s := range sentences[3:6] {
+					gsv := s.(nmea.GSV)
+					assert.Equal(t, int64(3), gsv.TotalMessages)
+					assert.Equal(t, int64(i+1), gsv.MessageNumber)
+					assert.Equal(t, int64(10), gsv.NumberSVsInView)
+				}
+
+				zda := sentences[7].(nmea.ZDA)
+				assert.Equal(t, int64(2020), zda.Year)
+
+				assert.Equal(t, 0.0, sentences[8].(nmea.HDT).Heading)
+				assert.Equal(t, 12.5, sentences[9].(nmea.DPT).Depth)
+			},
+		},
+		{
+			name: "dropout",
+			setup: func(s *Simulator) {
+				s.Dropout = 1
+				s.Talker = "GN"
+			},
+			types: []string{"GNRMC", "GNGGA", "GNGSA", "GNGSV", "GNGSV", "GNGSV", "GNVTG", "GNZDA", "HEHDT"},
+			check: func(t *testing.T, sentences []nmea.Sentence) {
+				assert.Equal(t, nmea.InvalidRMC, sentences[0].(nmea.RMC).Validity)
+				assert.Equal(t, nmea.Invalid, sentences[1].(nmea.GGA).FixQuality)
+				assert.Equal(t, nmea.FixNone, sentences[2].(nmea.GSA).FixType)
+			},
+		},
+		{
+			name:  "too few satellites",
+			setup: func(s *Simulator) { s.Satellites = s.Satellites[:2] },
+			types: []string{"GPRMC", "GPGGA", "GPGSA", "GPGSV", "GPVTG", "GPZDA", "HEHDT"},
+			check: func(t *testing.T, sentences []nmea.Sentence) {
+				assert.Equal(t, nmea.InvalidRMC, sentences[0].(nmea.RMC).Validity)
+				assert.Len(t, sentences[3].(nmea.GSV).Info, 2)
+			},
+		},
+		{
+			name: "no satellites",
+			setup: func(s *Simulator) {
+				s.Satellites = nil
+			},
+			types: []string{"GPRMC", "GPGGA", "GPGSA", "GPGSV", "GPVTG", "GPZDA", "HEHDT"},
+			check: func(t *testing.T, sentences []nmea.Sentence) {
+				gsv := sentences[3].(nmea.GSV)
+				assert.Equal(t, int64(1), gsv.TotalMessages)
+				assert.Equal(t, int64(0), gsv.NumberSVsInView)
+			},
+		},
+		{
+			name: "noise",
+			setup: func(s *Simulator) {
+				s.PositionNoise = 5
+				s.CourseNoise = 2
+				s.SpeedNoise = 0.5
+			},
+			types: []string{"GPRMC", "GPGGA", "GPGSA", "GPGSV", "GPGSV", "GPGSV", "GPVTG", "GPZDA", "HEHDT"},
+			check: func(t *testing.T, sentences []nmea.Sentence) {
+				rmc := sentences[0].(nmea.RMC)
+				p := geo.LatLon{Lat: rmc.Latitude, Lon: rmc.Longitude}
+				assert.True(t, geo.Distance(p, route[0].LatLon) < 30)
+				assert.NotEqual(t, 6.0, rmc.Speed)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSimulator(route, start, 1)
+			tt.setup(s)
+			lines := s.Step()
+			assert.Equal(t, tt.types, types(lines))
+			var sentences []nmea.Sentence
+			for _, line := range lines {
+				parsed, err := nmea.Parse(line)
+				if !assert.NoError(t, err, line) {
+					return
+				}
+				sentences = append(sentences, parsed)
+			}
+			tt.check(t, sentences)
+		})
+	}
+}
+
+func TestSeed(t *testing.T) {
+	run := func(seed int64) []string {
+		s := NewSimulator(route, start, seed)
+		s.PositionNoise = 5
+		s.Dropout = 0.2
+		var lines []string
+		for i := 0; i < 20; i++ {
+			lines = append(lines, s.Step()...)
+		}
+		return lines
+	}
+	assert.Equal(t, run(42), run(42))
+	assert.NotEqual(t, run(42), run(43))
+}
+
+func TestAdvance(t *testing.T) {
+	var tests = []struct {
+		name     string
+		loop     bool
+		steps    int
+		position geo.LatLon
+		done     bool
+	}{
+		{name: "first leg", steps: 1, position: geo.Destination(route[0].LatLon, 0, geo.NauticalMile/2)},
+		{name: "second leg", steps: 3, position: geo.Destination(route[1].LatLon, 90, geo.NauticalMile/2)},
+		{name: "end", steps: 4, position: route[2].LatLon, done: true},
+		{name: "stays at the end", steps: 6, position: route[2].LatLon, done: true},
+		{name: "loop", loop: true, steps: 4, position: route[0].LatLon},
+		{name: "loop again", loop: true, steps: 5, position: geo.Destination(route[0].LatLon, 0, geo.NauticalMile/2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSimulator(route, start, 1)
+			s.Speed = 1800 // half a mile a second
+			s.Loop = tt.loop
+			for i := 0; i < tt.steps; i++ {
+				s.Step()
+			}
+			assert.InDelta(t, tt.position.Lat, s.Position().Lat, 1e-6)
+			assert.InDelta(t, tt.position.Lon, s.Position().Lon, 1e-6)
+			assert.Equal(t, tt.done, s.Done())
+			assert.Equal(t, start.Add(time.Duration(tt.steps)*time.Second), s.Time())
+		})
+	}
+}
+
+func TestDOP(t *testing.T) {
+	var tests = []struct {
+		name string
+		sats []Satellite
+		pdop float64
+		hdop float64
+		vdop float64
+		ok   bool
+	}{
+		{
+			name: "zenith and horizon",
+			sats: []Satellite{
+				{PRN: 1, Elevation: 90},
+				{PRN: 2, Elevation: 0, Azimuth: 0},
+				{PRN: 3, Elevation: 0, Azimuth: 120},
+				{PRN: 4, Elevation: 0, Azimuth: 240},
+			},
+			pdop: 1.633,
+			hdop: 1.155,
+			vdop: 1.155,
+			ok:   true,
+		},
+		{
+			name: "too few",
+			sats: []Satellite{{PRN: 1, Elevation: 90}, {PRN: 2}, {PRN: 3, Azimuth: 90}},
+		},
+		{
+			name: "degenerate",
+			sats: []Satellite{{PRN: 1}, {PRN: 2}, {PRN: 3}, {PRN: 4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdop, hdop, vdop, ok := dop(tt.sats)
+			assert.Equal(t, tt.ok, ok)
+			assert.InDelta(t, tt.pdop, pdop, 1e-3)
+			assert.InDelta(t, tt.hdop, hdop, 1e-3)
+			assert.InDelta(t, tt.vdop, vdop, 1e-3)
+		})
+	}
+}
+
+func TestWaypoints(t *testing.T) {
+	var tests = []struct {
+		name      string
+		gpx       gpx.GPX
+		waypoints []geo.Waypoint
+		err       error
+	}{
+		{
+			name: "route",
+			gpx: gpx.GPX{
+				Routes: []gpx.Route{{Points: []gpx.Point{{Lat: 1, Lon: 2, Name: "A"}, {Lat: 3, Lon: 4, Name: "B"}}}},
+				Tracks: []gpx.Track{{Segments: []gpx.Segment{{Points: []gpx.Point{{Lat: 5, Lon: 6}}}}}},
+			},
+			waypoints: []geo.Waypoint{{ID: "A", LatLon: geo.LatLon{Lat: 1, Lon: 2}}, {ID: "B", LatLon: geo.LatLon{Lat: 3, Lon: 4}}},
+		},
+		{
+			name: "track",
+			gpx: gpx.GPX{
+				Tracks: []gpx.Track{{Segments: []gpx.Segment{
+					{Points: []gpx.Point{{Lat: 5, Lon: 6}}},
+					{Points: []gpx.Point{{Lat: 7, Lon: 8}}},
+				}}},
+			},
+			waypoints: []geo.Waypoint{{LatLon: geo.LatLon{Lat: 5, Lon: 6}}, {LatLon: geo.LatLon{Lat: 7, Lon: 8}}},
+		},
+		{
+			name: "empty",
+			gpx:  gpx.GPX{Waypoints: []gpx.Point{{Lat: 1, Lon: 2}}},
+			err:  ErrNoWaypoints,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waypoints, err := Waypoints(tt.gpx)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.waypoints, waypoints)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	s := NewSimulator(route, start, 1)
+	s.Speed = 1800
+	var waits []time.Duration
+	s.after = func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		c := make(chan time.Time, 1)
+		c <- time.Time{}
+		return c
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, s.Run(&buf))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), nmea.CRLF), nmea.CRLF)
+	assert.Len(t, lines, 5*9)
+	assert.Equal(t, []time.Duration{time.Second, time.Second, time.Second, time.Second}, waits)
+}
+
+func TestStop(t *testing.T) {
+	s := NewSimulator(route, start, 1)
+	s.Loop = true
+	s.after = func(time.Duration) <-chan time.Time { return nil }
+	done := make(chan error)
+	go func() { done <- s.Run(ioutil.Discard) }()
+	s.Stop()
+	s.Stop()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return")
+	}
+}
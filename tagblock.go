@@ -2,19 +2,152 @@ package nmea
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+)
+
+const (
+	// TagBlockSep is the token to delimit a tag block from the sentence.
+	TagBlockSep = `\`
+
+	// millisecondThreshold is the smallest unix timestamp which is interpreted
+	// as milliseconds. As seconds, it would be more than 30 000 years from now.
+	millisecondThreshold = 1e12
+
+	// MaxGroupTotal is the largest number of sentences in a tag block group.
+	MaxGroupTotal = 99
 )
 
 // TagBlock struct
 type TagBlock struct {
-	Time         int64  // TypeUnixTime unix timestamp (unit is likely to be s, but might be ms, YMMV), parameter: -c
-	RelativeTime int64  // TypeRelativeTime relative time, parameter: -r
-	Destination  string // TypeDestinationID destination identification 15 char max, parameter: -d
-	Grouping     string // TypeGrouping sentence grouping, parameter: -g
-	LineCount    int64  // TypeLineCount line count, parameter: -n
-	Source       string // TypeSourceID source identification 15 char max, parameter: -s
-	Text         string // TypeTextString valid character string, parameter -t
+	Time         int64         // TypeUnixTime unix timestamp (unit is likely to be s, but might be ms, YMMV), parameter: -c
+	RelativeTime int64         // TypeRelativeTime relative time, parameter: -r
+	Destination  string        // TypeDestinationID destination identification 15 char max, parameter: -d
+	Grouping     string        // TypeGrouping sentence grouping, parameter: -g
+	Group        SentenceGroup // Parsed sentence grouping, takes precedence over Grouping, parameter: -g
+	LineCount    int64         // TypeLineCount line count, parameter: -n
+	Source       string        // TypeSourceID source identification 15 char max, parameter: -s
+	Text         string        // TypeTextString valid character string, parameter -t
+
+	Extra map[string]string // Unrecognised tags, keyed by parameter
+}
+
+// SentenceGroup binds the sentences of a multi-line message, e.g. g:1-3-1234
+type SentenceGroup struct {
+	Number int64 // Sentence number within the group, starting at 1
+	Total  int64 // Total number of sentences in the group
+	ID     int64 // Group identification
+}
+
+// Valid reports whether the grouping contains a usable sentence number and
+// total, with the total at most MaxGroupTotal.
+func (g SentenceGroup) Valid() bool {
+	return g.Total > 0 && g.Total <= MaxGroupTotal && g.Number > 0 && g.Number <= g.Total
+}
+
+// String formats the grouping as <number>-<total>-<id>
+func (g SentenceGroup) String() string {
+	return fmt.Sprintf("%d-%d-%d", g.Number, g.Total, g.ID)
+}
+
+// parseSentenceGroup parses the value of the -g parameter.
+func parseSentenceGroup(raw string) (SentenceGroup, error) {
+	parts := strings.Split(raw, "-")
+	if len(parts) != 3 {
+		return SentenceGroup{}, fmt.Errorf("nmea: tagblock grouping is malformed [%s]", raw)
+	}
+	var (
+		g   SentenceGroup
+		err error
+	)
+	if g.Number, err = parseInt64(parts[0]); err != nil {
+		return SentenceGroup{}, err
+	}
+	if g.Total, err = parseInt64(parts[1]); err != nil {
+		return SentenceGroup{}, err
+	}
+	if g.ID, err = parseInt64(parts[2]); err != nil {
+		return SentenceGroup{}, err
+	}
+	if !g.Valid() {
+		return SentenceGroup{}, fmt.Errorf("nmea: tagblock grouping is out of range [%s]", raw)
+	}
+	return g, nil
+}
+
+// Timestamp returns the -c parameter as a time. Values which are too large
+// to be a unix time in seconds are treated as milliseconds.
+func (t TagBlock) Timestamp() time.Time {
+	if t.Time >= millisecondThreshold {
+		return time.Unix(t.Time/1000, (t.Time%1000)*int64(time.Millisecond))
+	}
+	return time.Unix(t.Time, 0)
+}
+
+// SetTimestamp sets the -c parameter, in milliseconds when millis is true.
+func (t *TagBlock) SetTimestamp(ts time.Time, millis bool) {
+	if millis {
+		t.Time = ts.UnixNano() / int64(time.Millisecond)
+	} else {
+		t.Time = ts.Unix()
+	}
+}
+
+// IsEmpty reports whether the tag block has no parameters.
+func (t TagBlock) IsEmpty() bool {
+	return t.Time == 0 && t.RelativeTime == 0 && t.Destination == "" &&
+		t.Grouping == "" && t.Group == (SentenceGroup{}) && t.LineCount == 0 && t.Source == "" &&
+		t.Text == "" && len(t.Extra) == 0
+}
+
+// String formats the tag block including the delimiters and checksum,
+// e.g. \s:Satelite_1,c:1553390539*62\
+// An empty tag block results in an empty string.
+func (t TagBlock) String() string {
+	if t.IsEmpty() {
+		return ""
+	}
+	fields := t.fields()
+	return TagBlockSep + fields + ChecksumSep + Checksum(fields) + TagBlockSep
+}
+
+// fields joins the tag block parameters in a stable order.
+func (t TagBlock) fields() string {
+	var items []string
+	if t.Time != 0 {
+		items = append(items, "c:"+strconv.FormatInt(t.Time, 10))
+	}
+	if t.Destination != "" {
+		items = append(items, "d:"+t.Destination)
+	}
+	if t.Group != (SentenceGroup{}) {
+		items = append(items, "g:"+t.Group.String())
+	} else if t.Grouping != "" {
+		items = append(items, "g:"+t.Grouping)
+	}
+	if t.LineCount != 0 {
+		items = append(items, "n:"+strconv.FormatInt(t.LineCount, 10))
+	}
+	if t.RelativeTime != 0 {
+		items = append(items, "r:"+strconv.FormatInt(t.RelativeTime, 10))
+	}
+	if t.Source != "" {
+		items = append(items, "s:"+t.Source)
+	}
+	if t.Text != "" {
+		items = append(items, "t:"+t.Text)
+	}
+	keys := make([]string, 0, len(t.Extra))
+	for k := range t.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		items = append(items, k+":"+t.Extra[k])
+	}
+	return strings.Join(items, FieldSep)
 }
 
 func parseInt64(raw string) (int64, error) {
@@ -25,6 +158,21 @@ func parseInt64(raw string) (int64, error) {
 	return i, nil
 }
 
+// SplitTagBlock separates a line into its tag block and the sentence
+// following it. Lines without a tag block result in an empty TagBlock.
+func SplitTagBlock(line string) (TagBlock, string, error) {
+	line = strings.TrimSpace(line)
+	parts := strings.SplitN(line, TagBlockSep, 3)
+	if len(parts) != 3 {
+		return TagBlock{}, line, nil
+	}
+	tagBlock, err := parseTagBlock(parts[1])
+	if err != nil {
+		return TagBlock{}, "", err
+	}
+	return tagBlock, parts[2], nil
+}
+
 // parseTagBlock adds support for tagblocks
 // https://gpsd.gitlab.io/gpsd/AIVDM.html#_nmea_tag_blocks
 func parseTagBlock(tags string) (TagBlock, error) {
@@ -37,7 +185,7 @@ func parseTagBlock(tags string) (TagBlock, error) {
 		fieldsRaw   = tags[0:sumSepIndex]
 		checksumRaw = strings.ToUpper(tags[sumSepIndex+1:])
 		checksum    = Checksum(fieldsRaw)
-		tagBlock	TagBlock
+		tagBlock    TagBlock
 		err         error
 	)
 
@@ -64,6 +212,11 @@ func parseTagBlock(tags string) (TagBlock, error) {
 			tagBlock.Destination = value
 		case "g": // Grouping
 			tagBlock.Grouping = value
+			// Groupings which are not of the form <number>-<total>-<id>,
+			// or out of range, are kept as the raw string only.
+			if group, err := parseSentenceGroup(value); err == nil {
+				tagBlock.Group = group
+			}
 		case "n": // Line count
 			tagBlock.LineCount, err = parseInt64(value)
 			if err != nil {
@@ -78,6 +231,11 @@ func parseTagBlock(tags string) (TagBlock, error) {
 			tagBlock.Source = value
 		case "t": // Text string
 			tagBlock.Text = value
+		default:
+			if tagBlock.Extra == nil {
+				tagBlock.Extra = map[string]string{}
+			}
+			tagBlock.Extra[key] = value
 		}
 	}
 	return tagBlock, nil
@@ -2,6 +2,7 @@ package nmea
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -37,6 +38,7 @@ var tagblocktests = []struct {
 		msg: TagBlock{
 			Time:   1564827317,
 			Source: "",
+			Extra:  map[string]string{"x": "NorSat_1"},
 		},
 	},
 	{
@@ -45,6 +47,7 @@ var tagblocktests = []struct {
 		msg: TagBlock{
 			Time:   1564827317,
 			Source: "",
+			Extra:  map[string]string{"x": "NorSat_1"},
 		},
 	},
 	{
@@ -54,6 +57,7 @@ var tagblocktests = []struct {
 		msg: TagBlock{
 			Time:   1564827317000,
 			Source: "",
+			Extra:  map[string]string{"x": "NorSat_1"},
 		},
 	},
 	{
@@ -122,3 +126,90 @@ func TestTagBlock(t *testing.T) {
 		})
 	}
 }
+
+var tagblockformattests = []struct {
+	name string
+	tags TagBlock
+	raw  string
+}{
+	{
+		name: "empty tag block",
+		tags: TagBlock{},
+		raw:  "",
+	},
+	{
+		name: "source and time",
+		tags: TagBlock{
+			Time:   1553390539,
+			Source: "Satelite_1",
+		},
+		raw: "\\c:1553390539,s:Satelite_1*62\\",
+	},
+	{
+		name: "all tags",
+		tags: TagBlock{
+			Time:         1564827317,
+			RelativeTime: 1553390539,
+			Destination:  "ara",
+			Grouping:     "bulk",
+			Source:       "satelite",
+			Text:         "helloworld",
+			LineCount:    13,
+		},
+		raw: "\\c:1564827317,d:ara,g:bulk,n:13,r:1553390539,s:satelite,t:helloworld*3F\\",
+	},
+	{
+		name: "unknown tags",
+		tags: TagBlock{
+			Time:  1564827317,
+			Extra: map[string]string{"y": "2", "x": "NorSat_1"},
+		},
+		raw: "\\c:1564827317,x:NorSat_1,y:2*1F\\",
+	},
+}
+
+func TestTagBlockString(t *testing.T) {
+	for _, tt := range tagblockformattests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := tt.tags.String()
+			assert.Equal(t, tt.raw, raw)
+			if raw != "" {
+				parsed, err := parseTagBlock(raw[1 : len(raw)-1])
+				assert.NoError(t, err)
+				assert.Equal(t, tt.tags, parsed)
+			}
+		})
+	}
+}
+
+func TestTagBlockTimestamp(t *testing.T) {
+	seconds := TagBlock{Time: 1553390539}
+	assert.Equal(t, time.Unix(1553390539, 0), seconds.Timestamp())
+
+	millis := TagBlock{Time: 1553390539123}
+	assert.Equal(t, time.Unix(1553390539, 123000000), millis.Timestamp())
+
+	var tags TagBlock
+	ts := time.Date(2019, 3, 24, 1, 22, 19, 456000000, time.UTC)
+	tags.SetTimestamp(ts, true)
+	assert.Equal(t, int64(1553390539456), tags.Time)
+	assert.True(t, ts.Equal(tags.Timestamp()))
+
+	tags.SetTimestamp(ts, false)
+	assert.Equal(t, int64(1553390539), tags.Time)
+}
+
+func TestSplitTagBlock(t *testing.T) {
+	tags, sentence, err := SplitTagBlock("\\s:Satelite_1,c:1553390539*62\\!AIVDM,1,1,,A,13M@ah0025QdPDTCOl`K6`nV00Sv,0*52\r\n")
+	assert.NoError(t, err)
+	assert.Equal(t, TagBlock{Time: 1553390539, Source: "Satelite_1"}, tags)
+	assert.Equal(t, "!AIVDM,1,1,,A,13M@ah0025QdPDTCOl`K6`nV00Sv,0*52", sentence)
+
+	tags, sentence, err = SplitTagBlock("$GPHDT,123.456,T*32")
+	assert.NoError(t, err)
+	assert.Equal(t, TagBlock{}, tags)
+	assert.Equal(t, "$GPHDT,123.456,T*32", sentence)
+
+	_, _, err = SplitTagBlock("\\s:Satelite_1*00\\$GPHDT,123.456,T*32")
+	assert.EqualError(t, err, "nmea: tagblock checksum mismatch [10 != 00]")
+}
@@ -0,0 +1,241 @@
+package nmea
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// System identifies the satellite navigation system a talker reports for.
+type System string
+
+const (
+	// SystemNone is used for talkers which are not satellite receivers.
+	SystemNone System = ""
+	// SystemGPS United States Global Positioning System
+	SystemGPS System = "GPS"
+	// SystemGLONASS Russian GLObal NAvigation Satellite System
+	SystemGLONASS System = "GLONASS"
+	// SystemGalileo European Galileo system
+	SystemGalileo System = "Galileo"
+	// SystemBeiDou Chinese BeiDou Navigation Satellite System
+	SystemBeiDou System = "BeiDou"
+	// SystemQZSS Japanese Quasi-Zenith Satellite System
+	SystemQZSS System = "QZSS"
+	// SystemNavIC Indian Navigation with Indian Constellation
+	SystemNavIC System = "NavIC"
+	// SystemMulti is used for combined solutions from several systems.
+	SystemMulti System = "GNSS"
+)
+
+// Talker describes a talker identifier.
+type Talker struct {
+	ID          string // The talker id (e.g GP)
+	Description string // Human readable description of the device
+	System      System // Satellite system, SystemNone for non GNSS devices
+}
+
+// Known reports whether the talker is present in the talker registry.
+func (t Talker) Known() bool {
+	return t.Description != ""
+}
+
+// String returns the talker id
+func (t Talker) String() string {
+	return t.ID
+}
+
+// PrefixSplitter splits the first field of a sentence into a talker id
+// and a data type. It returns false if it does not handle the prefix.
+type PrefixSplitter func(prefix string) (talker, typ string, ok bool)
+
+var (
+	talkersMu = &sync.Mutex{}
+	talkers   = map[string]Talker{}
+
+	prefixSplittersMu = &sync.Mutex{}
+	prefixSplitters   []PrefixSplitter
+)
+
+func init() {
+	for _, t := range []Talker{
+		{"AB", "Independent AIS base station", SystemNone},
+		{"AD", "Dependent AIS base station", SystemNone},
+		{"AG", "Autopilot - general", SystemNone},
+		{"AI", "Mobile AIS station", SystemNone},
+		{"AN", "AIS aid to navigation", SystemNone},
+		{"AP", "Autopilot - magnetic", SystemNone},
+		{"AR", "AIS receiving station", SystemNone},
+		{"AS", "AIS limited base station", SystemNone},
+		{"AT", "AIS transmitting station", SystemNone},
+		{"AX", "AIS simplex repeater", SystemNone},
+		{"BD", "BeiDou receiver (legacy)", SystemBeiDou},
+		{"BI", "Bilge system", SystemNone},
+		{"BN", "Bridge navigational watch alarm system", SystemNone},
+		{"BS", "AIS base station (legacy)", SystemNone},
+		{"CD", "Digital selective calling", SystemNone},
+		{"CR", "Data receiver", SystemNone},
+		{"CS", "Satellite communications", SystemNone},
+		{"CT", "Radio-telephone (MF/HF)", SystemNone},
+		{"CV", "Radio-telephone (VHF)", SystemNone},
+		{"CX", "Scanning receiver", SystemNone},
+		{"DF", "Direction finder", SystemNone},
+		{"DU", "Duplex repeater station", SystemNone},
+		{"EC", "Electronic chart system (ECS)", SystemNone},
+		{"EI", "Electronic chart display and information system (ECDIS)", SystemNone},
+		{"EP", "Emergency position indicating radio beacon (EPIRB)", SystemNone},
+		{"ER", "Engine room monitoring system", SystemNone},
+		{"FR", "Fire detection", SystemNone},
+		{"GA", "Galileo receiver", SystemGalileo},
+		{"GB", "BeiDou receiver", SystemBeiDou},
+		{"GI", "NavIC receiver", SystemNavIC},
+		{"GL", "GLONASS receiver", SystemGLONASS},
+		{"GN", "Global navigation satellite system (GNSS)", SystemMulti},
+		{"GP", "Global positioning system (GPS)", SystemGPS},
+		{"GQ", "QZSS receiver", SystemQZSS},
+		{"HC", "Heading - magnetic compass", SystemNone},
+		{"HE", "Heading - north seeking gyro", SystemNone},
+		{"HF", "Heading - fluxgate", SystemNone},
+		{"HN", "Heading - non north seeking gyro", SystemNone},
+		{"II", "Integrated instrumentation", SystemNone},
+		{"IN", "Integrated navigation", SystemNone},
+		{"LC", "Loran C", SystemNone},
+		{"NL", "Navigation light controller", SystemNone},
+		{"RA", "Radar and/or radar plotting", SystemNone},
+		{"RC", "Propulsion machinery including remote control", SystemNone},
+		{"SD", "Sounder, depth", SystemNone},
+		{"SN", "Electronic positioning system, other/general", SystemNone},
+		{"SS", "Sounder, scanning", SystemNone},
+		{"TI", "Turn rate indicator", SystemNone},
+		{"UP", "Microprocessor controller", SystemNone},
+		{"VD", "Velocity sensor, doppler", SystemNone},
+		{"VM", "Velocity sensor, speed log, water, magnetic", SystemNone},
+		{"VR", "Voyage data recorder", SystemNone},
+		{"VW", "Velocity sensor, speed log, water, mechanical", SystemNone},
+		{"WI", "Weather instruments", SystemNone},
+		{"YX", "Transducer", SystemNone},
+		{"ZA", "Timekeeper, atomic clock", SystemNone},
+		{"ZC", "Timekeeper, chronometer", SystemNone},
+		{"ZQ", "Timekeeper, quartz", SystemNone},
+		{"ZV", "Timekeeper, radio update", SystemNone},
+	} {
+		talkers[t.ID] = t
+	}
+	for i := 0; i <= 9; i++ {
+		id := fmt.Sprintf("U%d", i)
+		talkers[id] = Talker{id, "User configured talker", SystemNone}
+	}
+}
+
+// MustRegisterTalker registers a talker or panics
+func MustRegisterTalker(t Talker) {
+	if err := RegisterTalker(t); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterTalker adds a talker to the talker registry.
+func RegisterTalker(t Talker) error {
+	talkersMu.Lock()
+	defer talkersMu.Unlock()
+
+	if t.ID == "" || t.Description == "" {
+		return fmt.Errorf("nmea: talker must have an id and a description")
+	}
+	if _, ok := talkers[t.ID]; ok {
+		return fmt.Errorf("nmea: talker '%q' already exists", t.ID)
+	}
+	talkers[t.ID] = t
+	return nil
+}
+
+// LookupTalker returns the registered talker for the given id.
+func LookupTalker(id string) (Talker, bool) {
+	talkersMu.Lock()
+	defer talkersMu.Unlock()
+
+	t, ok := talkers[id]
+	return t, ok
+}
+
+// Talkers returns all registered talkers.
+func Talkers() []Talker {
+	talkersMu.Lock()
+	defer talkersMu.Unlock()
+
+	list := make([]Talker, 0, len(talkers))
+	for _, t := range talkers {
+		list = append(list, t)
+	}
+	return list
+}
+
+// RegisterPrefixSplitter adds a function used to split sentence prefixes
+// into a talker id and a data type. Splitters are tried in the order they
+// were registered, before the built-in rules.
+func RegisterPrefixSplitter(fn PrefixSplitter) {
+	prefixSplittersMu.Lock()
+	defer prefixSplittersMu.Unlock()
+
+	prefixSplitters = append(prefixSplitters, fn)
+}
+
+// parsePrefix takes the first field and splits it into a talker id and data type.
+func parsePrefix(s string) (string, string) {
+	prefixSplittersMu.Lock()
+	splitters := prefixSplitters
+	prefixSplittersMu.Unlock()
+
+	for _, fn := range splitters {
+		if talker, typ, ok := fn(s); ok {
+			return talker, typ
+		}
+	}
+	if strings.HasPrefix(s, "PMTK") {
+		return "PMTK", s[4:]
+	}
+	if strings.HasPrefix(s, "P") {
+		return "P", s[1:]
+	}
+	if len(s) > 5 {
+		// Longer than a talker and a three letter type, so a registered
+		// talker may be longer than two characters.
+		if id := longestTalker(s); id != "" {
+			return id, s[len(id):]
+		}
+	} else if len(s) >= 2 {
+		if _, ok := LookupTalker(s[:2]); ok {
+			return s[:2], s[2:]
+		}
+	}
+	if strings.HasPrefix(s, "N") {
+		return "N", s[1:]
+	}
+	if len(s) < 2 {
+		return s, ""
+	}
+	return s[:2], s[2:]
+}
+
+// longestTalker returns the longest registered talker id which s starts
+// with, leaving at least three characters for the type.
+func longestTalker(s string) string {
+	talkersMu.Lock()
+	defer talkersMu.Unlock()
+	var id string
+	for t := range talkers {
+		if len(t) > len(id) && len(t) <= len(s)-3 && strings.HasPrefix(s, t) {
+			id = t
+		}
+	}
+	return id
+}
+
+// TalkerInfo returns the registered talker of the sentence. If the talker
+// is not registered only the ID is set.
+func (s BaseSentence) TalkerInfo() Talker {
+	if t, ok := LookupTalker(s.Talker); ok {
+		return t
+	}
+	return Talker{ID: s.Talker}
+}
@@ -0,0 +1,171 @@
+package nmea
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var talkertests = []struct {
+	name   string
+	id     string
+	known  bool
+	system System
+}{
+	{
+		name:   "GPS",
+		id:     "GP",
+		known:  true,
+		system: SystemGPS,
+	},
+	{
+		name:   "GLONASS",
+		id:     "GL",
+		known:  true,
+		system: SystemGLONASS,
+	},
+	{
+		name:   "combined GNSS",
+		id:     "GN",
+		known:  true,
+		system: SystemMulti,
+	},
+	{
+		name:   "integrated instrumentation",
+		id:     "II",
+		known:  true,
+		system: SystemNone,
+	},
+	{
+		name:   "user configured",
+		id:     "U3",
+		known:  true,
+		system: SystemNone,
+	},
+	{
+		name:  "unknown talker",
+		id:    "QQ",
+		known: false,
+	},
+}
+
+func TestLookupTalker(t *testing.T) {
+	for _, tt := range talkertests {
+		t.Run(tt.name, func(t *testing.T) {
+			talker, ok := LookupTalker(tt.id)
+			assert.Equal(t, tt.known, ok)
+			if ok {
+				assert.Equal(t, tt.id, talker.ID)
+				assert.Equal(t, tt.system, talker.System)
+				assert.True(t, talker.Known())
+			}
+		})
+	}
+}
+
+func TestTalkerInfo(t *testing.T) {
+	s, err := parseSentence("$GAFOO,1,2*43")
+	assert.NoError(t, err)
+	assert.Equal(t, Talker{"GA", "Galileo receiver", SystemGalileo}, s.TalkerInfo())
+
+	s, err = parseSentence("$QQFOO,1,2*45")
+	assert.NoError(t, err)
+	assert.Equal(t, Talker{ID: "QQ"}, s.TalkerInfo())
+	assert.False(t, s.TalkerInfo().Known())
+}
+
+// unregisterTalker removes a talker registered by a test.
+func unregisterTalker(id string) {
+	talkersMu.Lock()
+	defer talkersMu.Unlock()
+
+	delete(talkers, id)
+}
+
+// restorePrefixSplitters returns a function restoring the splitters
+// registered before a test.
+func restorePrefixSplitters() func() {
+	prefixSplittersMu.Lock()
+	defer prefixSplittersMu.Unlock()
+
+	splitters := prefixSplitters
+	return func() {
+		prefixSplittersMu.Lock()
+		defer prefixSplittersMu.Unlock()
+
+		prefixSplitters = splitters
+	}
+}
+
+func TestRegisterTalker(t *testing.T) {
+	err := RegisterTalker(Talker{ID: "XT", Description: "Test talker"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer unregisterTalker("XT")
+
+	talker, ok := LookupTalker("XT")
+	assert.True(t, ok)
+	assert.Equal(t, "Test talker", talker.Description)
+
+	err = RegisterTalker(Talker{ID: "XT", Description: "Test talker"})
+	assert.EqualError(t, err, "nmea: talker '\"XT\"' already exists")
+
+	err = RegisterTalker(Talker{ID: "XU"})
+	assert.Error(t, err)
+
+	assert.Panics(t, func() {
+		MustRegisterTalker(Talker{ID: "GP", Description: "GPS"})
+	})
+}
+
+func TestRegisterPrefixSplitter(t *testing.T) {
+	defer restorePrefixSplitters()()
+	RegisterPrefixSplitter(func(prefix string) (string, string, bool) {
+		if strings.HasPrefix(prefix, "XYZ") {
+			return "XYZ", prefix[3:], true
+		}
+		return "", "", false
+	})
+
+	talker, typ := parsePrefix("XYZABC")
+	assert.Equal(t, "XYZ", talker)
+	assert.Equal(t, "ABC", typ)
+
+	talker, typ = parsePrefix("GPRMC")
+	assert.Equal(t, "GP", talker)
+	assert.Equal(t, "RMC", typ)
+}
+
+func TestParsePrefix(t *testing.T) {
+	if !assert.NoError(t, RegisterTalker(Talker{ID: "U1X", Description: "Test talker"})) {
+		return
+	}
+	defer unregisterTalker("U1X")
+
+	var tests = []struct {
+		prefix string
+		talker string
+		typ    string
+	}{
+		{prefix: "GPRMC", talker: "GP", typ: "RMC"},
+		{prefix: "PMTK001", talker: "PMTK", typ: "001"},
+		{prefix: "PGRME", talker: "P", typ: "GRME"},
+		{prefix: "NLRMC", talker: "NL", typ: "RMC"},
+		{prefix: "NQRMC", talker: "N", typ: "QRMC"},
+		{prefix: "U1XRMC", talker: "U1X", typ: "RMC"},
+		{prefix: "U1RMC", talker: "U1", typ: "RMC"},
+		{prefix: "QQQRMC", talker: "QQ", typ: "QRMC"},
+		{prefix: "XXABCD", talker: "XX", typ: "ABCD"},
+		{prefix: "QQQ", talker: "QQ", typ: "Q"},
+		{prefix: "G", talker: "G", typ: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			talker, typ := parsePrefix(tt.prefix)
+			assert.Equal(t, tt.talker, talker)
+			assert.Equal(t, tt.typ, typ)
+		})
+	}
+}
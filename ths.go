@@ -34,3 +34,13 @@ func newTHS(s BaseSentence) (THS, error) {
 	}
 	return m, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s THS) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *THS) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 )
 
@@ -78,7 +79,8 @@ func ParseGPS(s string) (float64, error) {
 	}
 }
 
-// FormatGPS formats a GPS/NMEA coordinate
+// FormatGPS formats a GPS/NMEA coordinate without its direction. The coord
+// package formats sentence fields with other precisions and the direction.
 func FormatGPS(l float64) string {
 	padding := ""
 	degrees := math.Floor(math.Abs(l))
@@ -154,12 +156,17 @@ func ParseDMS(s string) (float64, error) {
 }
 
 // FormatDMS returns the degrees, minutes, seconds format for the given LatLong.
+// Negative values are prefixed with a minus sign.
 func FormatDMS(l float64) string {
 	val := math.Abs(l)
 	degrees := int(math.Floor(val))
 	minutes := int(math.Floor(60 * (val - float64(degrees))))
 	seconds := 3600 * (val - float64(degrees) - (float64(minutes) / 60))
-	return fmt.Sprintf("%d\u00B0 %d' %f\"", degrees, minutes, seconds)
+	sign := ""
+	if l < 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%d\u00B0 %d' %f\"", sign, degrees, minutes, seconds)
 }
 
 // Time type
@@ -177,6 +184,14 @@ func (t Time) String() string {
 	return fmt.Sprintf("%02d:%02d:%07.4f", t.Hour, t.Minute, seconds)
 }
 
+// Duration returns the time elapsed since midnight.
+func (t Time) Duration() time.Duration {
+	return time.Duration(t.Hour)*time.Hour +
+		time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second +
+		time.Duration(t.Millisecond)*time.Millisecond
+}
+
 // timeRe is used to validate time strings
 var timeRe = regexp.MustCompile(`^\d{6}(\.\d*)?$`)
 
@@ -187,25 +202,67 @@ func ParseTime(s string) (Time, error) {
 	if s == "" {
 		return Time{}, nil
 	}
+	d, err := ParseTimeOfDay(s)
+	if err != nil {
+		return Time{}, err
+	}
+	return Time{
+		true,
+		int(d / time.Hour),
+		int(d % time.Hour / time.Minute),
+		int(d % time.Minute / time.Second),
+		int(d % time.Second / time.Millisecond),
+	}, nil
+}
+
+// ParseTimeOfDay parses wall clock time, e.g. hhmmss.ssss, into the time
+// elapsed since midnight. Unlike ParseTime, the fraction of the second is
+// kept down to the nanosecond.
+func ParseTimeOfDay(s string) (time.Duration, error) {
 	if !timeRe.MatchString(s) {
-		return Time{}, fmt.Errorf("parse time: expected hhmmss.ss format, got '%s'", s)
+		return 0, fmt.Errorf("parse time: expected hhmmss.ss format, got '%s'", s)
 	}
 	hour, _ := strconv.Atoi(s[:2])
 	minute, _ := strconv.Atoi(s[2:4])
-	second, _ := strconv.ParseFloat(s[4:], 64)
-	whole, frac := math.Modf(second)
-	return Time{true, hour, minute, int(whole), int(round(frac * 1000))}, nil
+	second, _ := strconv.Atoi(s[4:6])
+	// The fraction is parsed as digits rather than as a float so that
+	// no precision is lost, anything beyond nanoseconds is dropped.
+	var nanos int
+	if len(s) > 7 {
+		frac := s[7:]
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		nanos, _ = strconv.Atoi(frac + strings.Repeat("0", 9-len(frac)))
+	}
+	return time.Duration(hour)*time.Hour +
+		time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second +
+		time.Duration(nanos), nil
 }
 
-// round is implemented here because it wasn't added until go1.10
-// this code is taken directly from the math.Round documentation
-// TODO: use math.Round after a reasonable amount of time
-func round(x float64) float64 {
-	t := math.Trunc(x)
-	if math.Abs(x-t) >= 0.5 {
-		return t + math.Copysign(1, x)
+// timeOfDay returns the time elapsed since midnight of the time field i of
+// the sentence. The raw field is used, when it agrees with t, to keep the
+// part of the second below a millisecond.
+func (s BaseSentence) timeOfDay(i int, t Time) time.Duration {
+	if i < len(s.Fields) {
+		if d, err := ParseTimeOfDay(s.Fields[i]); err == nil && d.Truncate(time.Millisecond) == t.Duration() {
+			return d
+		}
 	}
-	return t
+	return t.Duration()
+}
+
+// NullInt64 is an int64 field which may be empty in the sentence.
+type NullInt64 struct {
+	Value int64
+	Valid bool // Valid is false if the field is empty
+}
+
+// NullFloat64 is a float64 field which may be empty in the sentence.
+type NullFloat64 struct {
+	Value float64
+	Valid bool // Valid is false if the field is empty
 }
 
 // Date type
@@ -221,6 +278,65 @@ func (d Date) String() string {
 	return fmt.Sprintf("%02d/%02d/%02d", d.DD, d.MM, d.YY)
 }
 
+// DefaultCenturyPivot is the century pivot used when a two-digit year is
+// converted without an explicit pivot. Years below it are in the 21st
+// century, the others in the 20th. GPS time starts in 1980.
+const DefaultCenturyPivot = 80
+
+// FullYear returns the four-digit year using the given century pivot.
+func (d Date) FullYear(pivot int) int {
+	if d.YY < pivot {
+		return 2000 + d.YY
+	}
+	return 1900 + d.YY
+}
+
+// CorrectRollover corrects a date hit by the GPS week rollover. The
+// reference is typically the firmware build date of the receiver: no valid
+// date can be older than it. A date before the reference is moved forward
+// by multiples of 1024 weeks and true is returned along with it.
+func (d Date) CorrectRollover(reference time.Time) (Date, bool) {
+	date, ok := dateUTC(d.FullYear(DefaultCenturyPivot), d.MM, d.DD)
+	if !d.Valid || !ok {
+		return d, false
+	}
+	corrected, ok := CorrectWeekRollover(date, truncateDay(reference))
+	if !ok {
+		return d, false
+	}
+	return Date{true, corrected.Day(), int(corrected.Month()), corrected.Year() % 100}, true
+}
+
+// DateTime combines a date and a time of day into a UTC time.Time. The
+// two-digit year of the date is expanded using the century pivot.
+func DateTime(pivot int, d Date, t Time) (time.Time, error) {
+	return dateTime(pivot, d, t, t.Duration())
+}
+
+// dateTime combines a date and the time of day t, elapsed since midnight.
+func dateTime(pivot int, d Date, t Time, tod time.Duration) (time.Time, error) {
+	if !t.Valid {
+		return time.Time{}, errors.New("nmea: invalid time")
+	}
+	date, ok := dateUTC(d.FullYear(pivot), d.MM, d.DD)
+	if !d.Valid || !ok {
+		return time.Time{}, errors.New("nmea: invalid date")
+	}
+	return date.Add(tod), nil
+}
+
+// dateUTC returns midnight UTC of the date, or false when the date does
+// not exist, e.g. 31/02.
+func dateUTC(year, month, day int) (time.Time, bool) {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return t, t.Year() == year && int(t.Month()) == month && t.Day() == day
+}
+
+// truncateDay returns midnight UTC of the day of t.
+func truncateDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
 // ParseDate field ddmmyy format
 func ParseDate(ddmmyy string) (Date, error) {
 	if ddmmyy == "" {
@@ -255,7 +371,7 @@ func LatDir(l float64) string {
 // LonDir returns the longitude direction symbol
 func LonDir(l float64) string {
 	if l < 0.0 {
-		return East
+		return West
 	}
-	return West
+	return East
 }
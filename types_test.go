@@ -3,6 +3,7 @@ package nmea
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -121,6 +122,11 @@ func TestLatLongPrint(t *testing.T) {
 			dms:   "45° 0' 0.000000\"",
 			gps:   "4500.0000",
 		},
+		{
+			value: -33.94057166666666,
+			gps:   "3356.4343",
+			dms:   "-33° 56' 26.058000\"",
+		},
 	}
 
 	for _, tt := range tests {
@@ -178,6 +184,40 @@ func TestTimeString(t *testing.T) {
 	}
 }
 
+func TestTimeDuration(t *testing.T) {
+	d := Time{true, 1, 2, 3, 4}
+	assert.Equal(t, time.Hour+2*time.Minute+3*time.Second+4*time.Millisecond, d.Duration())
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"010203", time.Hour + 2*time.Minute + 3*time.Second, true},
+		{"010203.04", time.Hour + 2*time.Minute + 3*time.Second + 40*time.Millisecond, true},
+		{"000000.123456", 123456 * time.Microsecond, true},
+		{"000000.9999", 999900 * time.Microsecond, true},
+		{"000000.1234567891", 123456789, true},
+		{"", 0, false},
+		{"1122xx.123", 0, false},
+	}
+	for _, tt := range tests {
+		d, err := ParseTimeOfDay(tt.value)
+		if tt.ok {
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+
+	tm, err := ParseTime("000000.9999")
+	assert.NoError(t, err)
+	assert.Equal(t, Time{true, 0, 0, 0, 999}, tm)
+}
+
 func TestDateParse(t *testing.T) {
 	datetests := []struct {
 		value    string
@@ -220,6 +260,29 @@ func TestDateString(t *testing.T) {
 	}
 }
 
+func TestDateFullYear(t *testing.T) {
+	assert.Equal(t, 2019, Date{true, 1, 2, 19}.FullYear(DefaultCenturyPivot))
+	assert.Equal(t, 1994, Date{true, 1, 2, 94}.FullYear(DefaultCenturyPivot))
+	assert.Equal(t, 2079, Date{true, 1, 2, 79}.FullYear(DefaultCenturyPivot))
+	assert.Equal(t, 1980, Date{true, 1, 2, 80}.FullYear(DefaultCenturyPivot))
+	assert.Equal(t, 1960, Date{true, 1, 2, 60}.FullYear(50))
+}
+
+func TestDateTime(t *testing.T) {
+	dt, err := DateTime(DefaultCenturyPivot, Date{true, 13, 6, 94}, Time{true, 22, 5, 16, 250})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(1994, 6, 13, 22, 5, 16, 250e6, time.UTC), dt)
+
+	_, err = DateTime(DefaultCenturyPivot, Date{true, 31, 2, 94}, Time{true, 22, 5, 16, 0})
+	assert.EqualError(t, err, "nmea: invalid date")
+
+	_, err = DateTime(DefaultCenturyPivot, Date{}, Time{true, 22, 5, 16, 0})
+	assert.EqualError(t, err, "nmea: invalid date")
+
+	_, err = DateTime(DefaultCenturyPivot, Date{true, 13, 6, 94}, Time{})
+	assert.EqualError(t, err, "nmea: invalid time")
+}
+
 func TestLatDir(t *testing.T) {
 	tests := []struct {
 		value    float64
@@ -240,8 +303,8 @@ func TestLonDir(t *testing.T) {
 		value    float64
 		expected string
 	}{
-		{100.0, "W"},
-		{-100.0, "E"},
+		{100.0, "E"},
+		{-100.0, "W"},
 	}
 	for _, tt := range tests {
 		if s := LonDir(tt.value); s != tt.expected {
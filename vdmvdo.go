@@ -33,3 +33,13 @@ func newVDMVDO(s BaseSentence) (VDMVDO, error) {
 	}
 	return m, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s VDMVDO) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *VDMVDO) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
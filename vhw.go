@@ -8,21 +8,38 @@ const (
 // VHW contains information about water speed and heading
 type VHW struct {
 	BaseSentence
-	TrueHeading            float64
-	MagneticHeading        float64
-	SpeedThroughWaterKnots float64
-	SpeedThroughWaterKPH   float64
+	TrueHeading                 float64
+	TrueHeadingValid            bool // TrueHeading is present in the sentence
+	MagneticHeading             float64
+	MagneticHeadingValid        bool // MagneticHeading is present in the sentence
+	SpeedThroughWaterKnots      float64
+	SpeedThroughWaterKnotsValid bool // SpeedThroughWaterKnots is present in the sentence
+	SpeedThroughWaterKPH        float64
+	SpeedThroughWaterKPHValid   bool // SpeedThroughWaterKPH is present in the sentence
 }
 
 // newVHW constructor
 func newVHW(s BaseSentence) (VHW, error) {
 	p := NewParser(s)
 	p.AssertType(TypeVHW)
-	return VHW{
-		BaseSentence:           s,
-		TrueHeading:            p.Float64(0, "true heading"),
-		MagneticHeading:        p.Float64(2, "magnetic heading"),
-		SpeedThroughWaterKnots: p.Float64(4, "speed through water in knots"),
-		SpeedThroughWaterKPH:   p.Float64(6, "speed through water in kilometers per hour"),
-	}, p.Err()
+	m := VHW{BaseSentence: s}
+	trueHeading := p.NullFloat64(0, "true heading")
+	m.TrueHeading, m.TrueHeadingValid = trueHeading.Value, trueHeading.Valid
+	magneticHeading := p.NullFloat64(2, "magnetic heading")
+	m.MagneticHeading, m.MagneticHeadingValid = magneticHeading.Value, magneticHeading.Valid
+	knots := p.NullFloat64(4, "speed through water in knots")
+	m.SpeedThroughWaterKnots, m.SpeedThroughWaterKnotsValid = knots.Value, knots.Valid
+	kph := p.NullFloat64(6, "speed through water in kilometers per hour")
+	m.SpeedThroughWaterKPH, m.SpeedThroughWaterKPHValid = kph.Value, kph.Valid
+	return m, p.Err()
+}
+
+// MarshalJSON implements json.Marshaler
+func (s VHW) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *VHW) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
 }
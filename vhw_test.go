@@ -16,10 +16,24 @@ var vhw = []struct {
 		name: "good sentence",
 		raw:  "$VWVHW,45.0,T,43.0,M,3.5,N,6.4,K*56",
 		msg: VHW{
-			TrueHeading:            45.0,
-			MagneticHeading:        43.0,
-			SpeedThroughWaterKnots: 3.5,
-			SpeedThroughWaterKPH:   6.4,
+			TrueHeading:                 45.0,
+			TrueHeadingValid:            true,
+			MagneticHeading:             43.0,
+			MagneticHeadingValid:        true,
+			SpeedThroughWaterKnots:      3.5,
+			SpeedThroughWaterKnotsValid: true,
+			SpeedThroughWaterKPH:        6.4,
+			SpeedThroughWaterKPHValid:   true,
+		},
+	},
+	{
+		name: "good sentence without heading",
+		raw:  "$VWVHW,,T,,M,3.5,N,6.4,K*50",
+		msg: VHW{
+			SpeedThroughWaterKnots:      3.5,
+			SpeedThroughWaterKnotsValid: true,
+			SpeedThroughWaterKPH:        6.4,
+			SpeedThroughWaterKPHValid:   true,
 		},
 	},
 	{
@@ -3,16 +3,33 @@ package nmea
 const (
 	// TypeVTG type for VTG sentences
 	TypeVTG = "VTG"
+	// AutonomousVTG mode character
+	AutonomousVTG = "A"
+	// DifferentialVTG mode character
+	DifferentialVTG = "D"
+	// EstimatedVTG mode character
+	EstimatedVTG = "E"
+	// ManualVTG mode character
+	ManualVTG = "M"
+	// SimulatorVTG mode character
+	SimulatorVTG = "S"
+	// NotValidVTG mode character, the data is not valid
+	NotValidVTG = "N"
 )
 
 // VTG represents track & speed data.
 // http://aprs.gids.nl/nmea/#vtg
 type VTG struct {
 	BaseSentence
-	TrueTrack        float64
-	MagneticTrack    float64
-	GroundSpeedKnots float64
-	GroundSpeedKPH   float64
+	TrueTrack             float64
+	TrueTrackValid        bool // TrueTrack is present in the sentence
+	MagneticTrack         float64
+	MagneticTrackValid    bool // MagneticTrack is present in the sentence
+	GroundSpeedKnots      float64
+	GroundSpeedKnotsValid bool // GroundSpeedKnots is present in the sentence
+	GroundSpeedKPH        float64
+	GroundSpeedKPHValid   bool   // GroundSpeedKPH is present in the sentence
+	Mode                  string // FAA mode indicator, empty before NMEA 2.3
 }
 
 // newVTG parses the VTG sentence into this struct.
@@ -20,11 +37,27 @@ type VTG struct {
 func newVTG(s BaseSentence) (VTG, error) {
 	p := NewParser(s)
 	p.AssertType(TypeVTG)
-	return VTG{
-		BaseSentence:     s,
-		TrueTrack:        p.Float64(0, "true track"),
-		MagneticTrack:    p.Float64(2, "magnetic track"),
-		GroundSpeedKnots: p.Float64(4, "ground speed (knots)"),
-		GroundSpeedKPH:   p.Float64(6, "ground speed (km/h)"),
-	}, p.Err()
+	m := VTG{BaseSentence: s}
+	trueTrack := p.NullFloat64(0, "true track")
+	m.TrueTrack, m.TrueTrackValid = trueTrack.Value, trueTrack.Valid
+	magneticTrack := p.NullFloat64(2, "magnetic track")
+	m.MagneticTrack, m.MagneticTrackValid = magneticTrack.Value, magneticTrack.Valid
+	knots := p.NullFloat64(4, "ground speed (knots)")
+	m.GroundSpeedKnots, m.GroundSpeedKnotsValid = knots.Value, knots.Valid
+	kph := p.NullFloat64(6, "ground speed (km/h)")
+	m.GroundSpeedKPH, m.GroundSpeedKPHValid = kph.Value, kph.Valid
+	if len(s.Fields) > 8 {
+		m.Mode = p.EnumString(8, "mode", AutonomousVTG, DifferentialVTG, EstimatedVTG, ManualVTG, SimulatorVTG, NotValidVTG)
+	}
+	return m, p.Err()
+}
+
+// MarshalJSON implements json.Marshaler
+func (s VTG) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *VTG) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
 }
@@ -16,12 +16,41 @@ var vtgtests = []struct {
 		name: "good sentence",
 		raw:  "$GPVTG,45.5,T,67.5,M,30.45,N,56.40,K*4B",
 		msg: VTG{
-			TrueTrack:        45.5,
-			MagneticTrack:    67.5,
-			GroundSpeedKnots: 30.45,
-			GroundSpeedKPH:   56.4,
+			TrueTrack:             45.5,
+			TrueTrackValid:        true,
+			MagneticTrack:         67.5,
+			MagneticTrackValid:    true,
+			GroundSpeedKnots:      30.45,
+			GroundSpeedKnotsValid: true,
+			GroundSpeedKPH:        56.4,
+			GroundSpeedKPHValid:   true,
 		},
 	},
+	{
+		name: "good sentence with mode",
+		raw:  "$GPVTG,231.8,T,,M,173.80,N,321.88,K,A*08",
+		msg: VTG{
+			TrueTrack:             231.8,
+			TrueTrackValid:        true,
+			GroundSpeedKnots:      173.8,
+			GroundSpeedKnotsValid: true,
+			GroundSpeedKPH:        321.88,
+			GroundSpeedKPHValid:   true,
+			Mode:                  AutonomousVTG,
+		},
+	},
+	{
+		name: "not valid",
+		raw:  "$GPVTG,,T,,M,,N,,K,N*2C",
+		msg: VTG{
+			Mode: NotValidVTG,
+		},
+	},
+	{
+		name: "bad mode",
+		raw:  "$GPVTG,45.5,T,67.5,M,30.45,N,56.40,K,X*3F",
+		err:  "nmea: GPVTG invalid mode: X",
+	},
 	{
 		name: "bad true track",
 		raw:  "$GPVTG,T,45.5,67.5,M,30.45,N,56.40,K*4B",
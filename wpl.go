@@ -24,3 +24,13 @@ func newWPL(s BaseSentence) (WPL, error) {
 		Ident:        p.String(4, "ident of nth waypoint"),
 	}, p.Err()
 }
+
+// MarshalJSON implements json.Marshaler
+func (s WPL) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *WPL) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
@@ -0,0 +1,110 @@
+package nmea
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxSentenceLength is the maximum length of a sentence including the
+	// start token and the line ending, excluding any tag block.
+	MaxSentenceLength = 82
+
+	// CRLF is the line ending required by NMEA 0183.
+	CRLF = "\r\n"
+)
+
+var (
+	// ErrSentenceTooLong is returned when a sentence exceeds MaxSentenceLength.
+	ErrSentenceTooLong = errors.New("nmea: sentence exceeds the maximum length")
+
+	// ErrRateLimited is returned when a sentence is dropped by a rate limit.
+	ErrRateLimited = errors.New("nmea: sentence dropped by rate limit")
+)
+
+// Writer writes checksummed sentences to an io.Writer, one per line.
+type Writer struct {
+	LineEnding string // Line ending written after each sentence, CRLF by default
+
+	mu     sync.Mutex
+	w      io.Writer
+	limits map[string]time.Duration
+	last   map[string]time.Time
+	now    func() time.Time
+}
+
+// NewWriter constructor
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		LineEnding: CRLF,
+		w:          w,
+		limits:     map[string]time.Duration{},
+		last:       map[string]time.Time{},
+		now:        time.Now,
+	}
+}
+
+// SetRateLimit sets the minimum interval between two sentences of the given
+// key. The key is either a talker id (e.g GP) or a full prefix (e.g GPGSV),
+// the latter taking precedence. Sentences sent too early are dropped and
+// ErrRateLimited is returned. A zero interval removes the limit.
+func (w *Writer) SetRateLimit(key string, interval time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if interval <= 0 {
+		delete(w.limits, key)
+		return
+	}
+	w.limits[key] = interval
+}
+
+// Write writes the sentence. The sentence is rendered again from its fields
+// so the checksum is always correct, and its tag block is preserved.
+func (w *Writer) Write(s Sentence) error {
+	base, ok := BaseSentenceOf(s)
+	if !ok {
+		return w.writeLine(TagBlock{}, s.TalkerID(), s.Prefix(), s.String())
+	}
+	base.SetTagBlock(base.TagBlock)
+	return w.writeLine(base.TagBlock, base.Talker, base.Prefix(), base.Raw)
+}
+
+// WriteFields writes a sentence built from the prefix and fields. The prefix
+// may start with '!' for encapsulated sentences, '$' is used otherwise.
+func (w *Writer) WriteFields(prefix string, fields ...string) error {
+	start := SentenceStart
+	if strings.HasPrefix(prefix, SentenceStart) || strings.HasPrefix(prefix, SentenceStartEncapsulated) {
+		start, prefix = prefix[:1], prefix[1:]
+	}
+	talker, _ := parsePrefix(prefix)
+	return w.writeLine(TagBlock{}, talker, prefix, FormatSentence(start, prefix, fields))
+}
+
+func (w *Writer) writeLine(tags TagBlock, talker, prefix, raw string) error {
+	if len(raw)+len(CRLF) > MaxSentenceLength {
+		return ErrSentenceTooLong
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := prefix
+	interval, ok := w.limits[key]
+	if !ok {
+		key = talker
+		interval, ok = w.limits[key]
+	}
+	if ok {
+		now := w.now()
+		if last, seen := w.last[key]; seen && now.Sub(last) < interval {
+			return ErrRateLimited
+		}
+		w.last[key] = now
+	}
+	_, err := io.WriteString(w.w, tags.String()+raw+w.LineEnding)
+	return err
+}
@@ -0,0 +1,72 @@
+package nmea
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatSentence(t *testing.T) {
+	assert.Equal(t, "$GPHDT,123.456,T*32", FormatSentence("$", "GPHDT", []string{"123.456", "T"}))
+	assert.Equal(t, "!AIVDM,1,1,,A,13M@ah0025QdPDTCOl`K6`nV00Sv,0*52",
+		FormatSentence("!", "AIVDM", []string{"1", "1", "", "A", "13M@ah0025QdPDTCOl`K6`nV00Sv", "0"}))
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	s, err := Parse("\\s:Satelite_1,c:1553390539*62\\!AIVDM,1,1,,A,13M@ah0025QdPDTCOl`K6`nV00Sv,0*52")
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(s))
+	assert.NoError(t, w.WriteFields("GPHDT", "123.456", "T"))
+	assert.NoError(t, w.WriteFields("!AIVDM", "1", "1", "", "A", "13M@ah0025QdPDTCOl`K6`nV00Sv", "0"))
+
+	assert.Equal(t, "\\c:1553390539,s:Satelite_1*62\\!AIVDM,1,1,,A,13M@ah0025QdPDTCOl`K6`nV00Sv,0*52\r\n"+
+		"$GPHDT,123.456,T*32\r\n"+
+		"!AIVDM,1,1,,A,13M@ah0025QdPDTCOl`K6`nV00Sv,0*52\r\n", buf.String())
+}
+
+func TestWriterLineEnding(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.LineEnding = "\n"
+	assert.NoError(t, w.WriteFields("GPHDT", "123.456", "T"))
+	assert.Equal(t, "$GPHDT,123.456,T*32\n", buf.String())
+}
+
+func TestWriterTooLong(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	assert.Equal(t, ErrSentenceTooLong, w.WriteFields("GPTXT", strings.Repeat("x", 71)))
+	assert.NoError(t, w.WriteFields("GPTXT", strings.Repeat("x", 70)))
+	assert.Len(t, buf.String(), MaxSentenceLength)
+}
+
+func TestWriterRateLimit(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := NewWriter(&buf)
+	w.now = func() time.Time { return now }
+	w.SetRateLimit("GP", time.Second)
+	w.SetRateLimit("GPGSV", 5*time.Second)
+
+	assert.NoError(t, w.WriteFields("GPHDT", "123.456", "T"))
+	assert.Equal(t, ErrRateLimited, w.WriteFields("GPHDT", "123.456", "T"))
+	assert.NoError(t, w.WriteFields("IIHDT", "123.456", "T"))
+	assert.NoError(t, w.WriteFields("GPGSV", "1", "1", "0"))
+
+	now = now.Add(time.Second)
+	assert.NoError(t, w.WriteFields("GPHDT", "123.456", "T"))
+	assert.Equal(t, ErrRateLimited, w.WriteFields("GPGSV", "1", "1", "0"))
+
+	// Without its own limit the sentence falls back to the talker limit.
+	w.SetRateLimit("GPGSV", 0)
+	assert.Equal(t, ErrRateLimited, w.WriteFields("GPGSV", "1", "1", "0"))
+	now = now.Add(time.Second)
+	assert.NoError(t, w.WriteFields("GPGSV", "1", "1", "0"))
+	assert.Equal(t, 5, strings.Count(buf.String(), CRLF))
+}
@@ -1,5 +1,10 @@
 package nmea
 
+import (
+	"errors"
+	"time"
+)
+
 const (
 	// TypeZDA type for ZDA sentences
 	TypeZDA = "ZDA"
@@ -15,6 +20,7 @@ type ZDA struct {
 	Year          int64
 	OffsetHours   int64 // Local time zone offset from GMT, hours
 	OffsetMinutes int64 // Local time zone offset from GMT, minutes
+	DateCorrected bool  // Date was corrected for the GPS week rollover
 }
 
 // newZDA constructor
@@ -31,3 +37,51 @@ func newZDA(s BaseSentence) (ZDA, error) {
 		OffsetMinutes: p.Int64(5, "offset (minutes)"),
 	}, p.Err()
 }
+
+// DateTime returns the date and time of the sentence. The location of the
+// result is the local zone of the sentence, where local time is UTC plus
+// the offset.
+func (s ZDA) DateTime() (time.Time, error) {
+	if !s.Time.Valid {
+		return time.Time{}, errors.New("nmea: invalid time")
+	}
+	date, ok := dateUTC(int(s.Year), int(s.Month), int(s.Day))
+	if !ok || s.Year == 0 {
+		return time.Time{}, errors.New("nmea: invalid date")
+	}
+	offset := int(s.OffsetHours)*3600 + int(s.OffsetMinutes)*60
+	if s.OffsetHours < 0 && s.OffsetMinutes > 0 {
+		offset = int(s.OffsetHours)*3600 - int(s.OffsetMinutes)*60
+	}
+	utc := date.Add(s.timeOfDay(0, s.Time))
+	return utc.In(time.FixedZone("", offset)), nil
+}
+
+// CorrectRollover returns the sentence with its date corrected for the GPS
+// week rollover, see Date.CorrectRollover. DateCorrected is set on the
+// returned sentence when a correction was applied, which is also reported.
+func (s ZDA) CorrectRollover(reference time.Time) (ZDA, bool) {
+	date, ok := dateUTC(int(s.Year), int(s.Month), int(s.Day))
+	if !ok || s.Year == 0 {
+		return s, false
+	}
+	corrected, ok := CorrectWeekRollover(date, truncateDay(reference))
+	if !ok {
+		return s, false
+	}
+	s.Year = int64(corrected.Year())
+	s.Month = int64(corrected.Month())
+	s.Day = int64(corrected.Day())
+	s.DateCorrected = true
+	return s, true
+}
+
+// MarshalJSON implements json.Marshaler
+func (s ZDA) MarshalJSON() ([]byte, error) {
+	return marshalSentence(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *ZDA) UnmarshalJSON(b []byte) error {
+	return unmarshalSentence(b, s)
+}
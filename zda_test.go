@@ -2,6 +2,7 @@ package nmea
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -53,3 +54,29 @@ func TestZDA(t *testing.T) {
 		})
 	}
 }
+
+func TestZDADateTime(t *testing.T) {
+	m, err := Parse("$GPZDA,172809.456,12,07,1996,-02,30*7B")
+	assert.NoError(t, err)
+	dt, err := m.(ZDA).DateTime()
+	assert.NoError(t, err)
+	assert.True(t, time.Date(1996, 7, 12, 17, 28, 9, 456e6, time.UTC).Equal(dt))
+	_, offset := dt.Zone()
+	assert.Equal(t, -(2*3600 + 30*60), offset)
+
+	m, err = Parse("$GPZDA,172809.456,,,,00,00*54")
+	assert.NoError(t, err)
+	_, err = m.(ZDA).DateTime()
+	assert.EqualError(t, err, "nmea: invalid date")
+
+	m, err = Parse("$GPZDA,172809.456,31,02,1996,00,00*53")
+	assert.NoError(t, err)
+	_, err = m.(ZDA).DateTime()
+	assert.EqualError(t, err, "nmea: invalid date")
+
+	m, err = Parse("$GPZDA,172809.123456,12,07,1996,00,00*67")
+	assert.NoError(t, err)
+	dt, err = m.(ZDA).DateTime()
+	assert.NoError(t, err)
+	assert.True(t, time.Date(1996, 7, 12, 17, 28, 9, 123456e3, time.UTC).Equal(dt))
+}